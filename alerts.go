@@ -0,0 +1,595 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/fsutil"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/timearg"
+)
+
+// AlertOp - сравнение в правиле оповещения.
+type AlertOp string
+
+const (
+	AlertAbove        AlertOp = ">"
+	AlertAboveOrEqual AlertOp = ">="
+	AlertBelow        AlertOp = "<"
+	AlertBelowOrEqual AlertOp = "<="
+	AlertCrosses      AlertOp = "crosses"
+	AlertCrossesAbove AlertOp = "crosses_above"
+	AlertCrossesBelow AlertOp = "crosses_below"
+)
+
+func (op AlertOp) crossing() bool {
+	return op == AlertCrosses || op == AlertCrossesAbove || op == AlertCrossesBelow
+}
+
+// AlertMetric - наблюдаемая величина: поле тикера или индикатор по свечам.
+type AlertMetric struct {
+	Pair       string
+	Field      string // last_trade, buy_price, sell_price, high, low, avg или spread (в б.п.)
+	Indicator  string // rsi, sma или ema
+	Period     int
+	Resolution int // в минутах
+}
+
+func (m AlertMetric) String() string {
+	if m.Indicator != "" {
+		return fmt.Sprintf("%s(%d) on %s %s", strings.ToUpper(m.Indicator), m.Period, m.Pair, formatResolution(m.Resolution))
+	}
+	return m.Pair + " " + m.Field
+}
+
+// AlertCondition - разобранное выражение правила.
+type AlertCondition struct {
+	Metric    AlertMetric
+	Op        AlertOp
+	Threshold float64
+}
+
+var tickerFields = map[string]bool{
+	"last_trade": true, "buy_price": true, "sell_price": true,
+	"high": true, "low": true, "avg": true, "spread": true,
+}
+
+// ParseAlertCondition разбирает выражения вида
+//
+//	BTC_USD last_trade crosses 60000
+//	RSI(14) on ETH_USD 1h < 30
+//	BTC_USD spread > 50bps
+func ParseAlertCondition(expr string) (AlertCondition, error) {
+	fields := strings.Fields(expr)
+	var cond AlertCondition
+	var rest []string
+
+	switch {
+	case len(fields) == 6 && strings.EqualFold(fields[1], "on"):
+		name, period, err := parseIndicatorCall(fields[0])
+		if err != nil {
+			return cond, fmt.Errorf("%q: %w", expr, err)
+		}
+		resolution, err := timearg.ParseResolution(fields[3])
+		if err != nil {
+			return cond, fmt.Errorf("%q: %w", expr, err)
+		}
+		cond.Metric = AlertMetric{Indicator: name, Period: period, Pair: strings.ToUpper(fields[2]), Resolution: resolution}
+		rest = fields[4:]
+	case len(fields) == 4:
+		field := strings.ToLower(fields[1])
+		if !tickerFields[field] {
+			return cond, fmt.Errorf("%q: unknown ticker field %q", expr, fields[1])
+		}
+		cond.Metric = AlertMetric{Pair: strings.ToUpper(fields[0]), Field: field}
+		rest = fields[2:]
+	default:
+		return cond, fmt.Errorf("%q: want \"PAIR FIELD OP VALUE\" or \"IND(N) on PAIR RES OP VALUE\"", expr)
+	}
+
+	if _, _, err := exmo.SplitPair(cond.Metric.Pair); err != nil {
+		return cond, fmt.Errorf("%q: %w", expr, err)
+	}
+	cond.Op = AlertOp(strings.ToLower(rest[0]))
+	switch cond.Op {
+	case AlertAbove, AlertAboveOrEqual, AlertBelow, AlertBelowOrEqual, AlertCrosses, AlertCrossesAbove, AlertCrossesBelow:
+	default:
+		return cond, fmt.Errorf("%q: unknown operator %q", expr, rest[0])
+	}
+
+	value := strings.ToLower(rest[1])
+	if strings.HasSuffix(value, "bps") {
+		if cond.Metric.Field != "spread" {
+			return cond, fmt.Errorf("%q: bps is only valid for spread", expr)
+		}
+		value = strings.TrimSuffix(value, "bps")
+	}
+	threshold, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return cond, fmt.Errorf("%q: invalid threshold %q", expr, rest[1])
+	}
+	cond.Threshold = threshold
+	return cond, nil
+}
+
+func parseIndicatorCall(s string) (string, int, error) {
+	open, close := strings.Index(s, "("), strings.LastIndex(s, ")")
+	if open <= 0 || close != len(s)-1 {
+		return "", 0, fmt.Errorf("invalid indicator %q, want NAME(PERIOD)", s)
+	}
+	name := strings.ToLower(s[:open])
+	if name != "rsi" && name != "sma" && name != "ema" {
+		return "", 0, fmt.Errorf("unknown indicator %q", s[:open])
+	}
+	period, err := strconv.Atoi(s[open+1 : close])
+	if err != nil || period <= 0 {
+		return "", 0, fmt.Errorf("invalid period in %q", s)
+	}
+	return name, period, nil
+}
+
+func formatResolution(minutes int) string {
+	switch {
+	case minutes%(24*60) == 0:
+		return strconv.Itoa(minutes/(24*60)) + "d"
+	case minutes%60 == 0:
+		return strconv.Itoa(minutes/60) + "h"
+	}
+	return strconv.Itoa(minutes) + "m"
+}
+
+// AlertRule - правило с гистерезисом и паузой между оповещениями.
+// Hysteresis задается в единицах величины: после срабатывания правило
+// снова взводится, только когда величина отойдет от порога на эту величину.
+type AlertRule struct {
+	Name       string
+	Expr       string
+	Hysteresis float64
+	Cooldown   time.Duration
+	Condition  AlertCondition
+}
+
+func NewAlertRule(name, expr string, hysteresis float64, cooldown time.Duration) (*AlertRule, error) {
+	cond, err := ParseAlertCondition(expr)
+	if err != nil {
+		return nil, err
+	}
+	if hysteresis < 0 || cooldown < 0 {
+		return nil, fmt.Errorf("%q: hysteresis and cooldown must not be negative", expr)
+	}
+	if name == "" {
+		name = expr
+	}
+	return &AlertRule{Name: name, Expr: expr, Hysteresis: hysteresis, Cooldown: cooldown, Condition: cond}, nil
+}
+
+// RuleState - сохраняемое состояние правила. Для сравнений Active означает,
+// что условие выполнено, для пересечений - что величина выше порога.
+type RuleState struct {
+	Expr        string    `json:"expr"`
+	Initialized bool      `json:"initialized"`
+	Active      bool      `json:"active"`
+	LastValue   float64   `json:"last_value"`
+	LastFired   time.Time `json:"last_fired,omitempty"`
+	Updated     time.Time `json:"updated"`
+}
+
+// step обновляет состояние новым значением и сообщает, нужно ли оповещение.
+func (r *AlertRule) step(st *RuleState, v float64, now time.Time) bool {
+	thr, h := r.Condition.Threshold, r.Hysteresis
+	fire := false
+
+	switch op := r.Condition.Op; {
+	case op.crossing():
+		above := v >= thr
+		switch {
+		case !st.Initialized:
+			st.Active = above
+		case !st.Active && above && v >= thr+h:
+			st.Active = true
+			fire = op != AlertCrossesBelow
+		case st.Active && !above && v <= thr-h:
+			st.Active = false
+			fire = op != AlertCrossesAbove
+		}
+	default:
+		var met bool
+		var distance float64 // насколько величина ушла от порога в сторону отмены
+		switch op {
+		case AlertAbove:
+			met, distance = v > thr, thr-v
+		case AlertAboveOrEqual:
+			met, distance = v >= thr, thr-v
+		case AlertBelow:
+			met, distance = v < thr, v-thr
+		case AlertBelowOrEqual:
+			met, distance = v <= thr, v-thr
+		}
+		switch {
+		case !st.Active && met:
+			st.Active = true
+			fire = true
+		case st.Active && !met && distance >= h:
+			st.Active = false
+		}
+	}
+
+	st.Initialized = true
+	st.LastValue = v
+	st.Updated = now
+	if fire && !st.LastFired.IsZero() && now.Sub(st.LastFired) < r.Cooldown {
+		return false
+	}
+	if fire {
+		st.LastFired = now
+	}
+	return fire
+}
+
+// Alert - сработавшее правило.
+type Alert struct {
+	Rule      string    `json:"rule"`
+	Expr      string    `json:"expr"`
+	Metric    string    `json:"metric"`
+	Pair      string    `json:"pair"`
+	Op        AlertOp   `json:"op"`
+	Threshold float64   `json:"threshold"`
+	Value     float64   `json:"value"`
+	Time      time.Time `json:"time"`
+}
+
+func (a Alert) String() string {
+	return fmt.Sprintf("[%s] %s: %s = %s (%s %s)", a.Time.UTC().Format(time.RFC3339), a.Rule, a.Metric,
+		formatFloat(a.Value), a.Op, formatFloat(a.Threshold))
+}
+
+// AlertSink доставляет оповещения.
+type AlertSink interface {
+	Notify(ctx context.Context, a Alert) error
+}
+
+// WriterSink печатает оповещения построчно, например в stdout.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+func (s *WriterSink) Notify(_ context.Context, a Alert) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err := fmt.Fprintln(s.w, a.String())
+	return err
+}
+
+// WebhookSink отправляет оповещение POST-запросом с JSON в теле.
+type WebhookSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhookSink(url string, client *http.Client) *WebhookSink {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &WebhookSink{url: url, client: client}
+}
+
+func (s *WebhookSink) Notify(ctx context.Context, a Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: server returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// FileSink дописывает оповещения в файл по одному JSON на строку.
+type FileSink struct {
+	mu   sync.Mutex
+	path string
+}
+
+func NewFileSink(path string) *FileSink {
+	return &FileSink{path: path}
+}
+
+func (s *FileSink) Notify(_ context.Context, a Alert) error {
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// AlertEngine опрашивает биржу, проверяет правила и рассылает оповещения.
+type AlertEngine struct {
+	ex        exmo.Exchanger
+	rules     []*AlertRule
+	sinks     []AlertSink
+	statePath string
+	now       func() time.Time
+
+	mu    sync.Mutex
+	state map[string]*RuleState
+}
+
+type AlertOption func(*AlertEngine)
+
+func WithAlertSinks(sinks ...AlertSink) AlertOption {
+	return func(e *AlertEngine) {
+		e.sinks = append(e.sinks, sinks...)
+	}
+}
+
+// WithAlertState сохраняет состояние правил в файл, чтобы оно пережило перезапуск.
+func WithAlertState(path string) AlertOption {
+	return func(e *AlertEngine) {
+		e.statePath = path
+	}
+}
+
+func WithAlertClock(now func() time.Time) AlertOption {
+	return func(e *AlertEngine) {
+		e.now = now
+	}
+}
+
+func NewAlertEngine(ex exmo.Exchanger, rules []*AlertRule, opts ...AlertOption) (*AlertEngine, error) {
+	e := &AlertEngine{
+		ex:    ex,
+		rules: rules,
+		now:   time.Now,
+		state: make(map[string]*RuleState),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	seen := make(map[string]bool)
+	for _, r := range rules {
+		if seen[r.Name] {
+			return nil, fmt.Errorf("duplicate alert rule name %q", r.Name)
+		}
+		seen[r.Name] = true
+	}
+	if err := e.loadState(); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+func (e *AlertEngine) loadState() error {
+	if e.statePath == "" {
+		return nil
+	}
+	data, err := os.ReadFile(e.statePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &e.state); err != nil {
+		return fmt.Errorf("alert state %s: %w", e.statePath, err)
+	}
+	return nil
+}
+
+func (e *AlertEngine) saveStateLocked() error {
+	if e.statePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(e.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(e.statePath, data)
+}
+
+// State возвращает копию состояния правила.
+func (e *AlertEngine) State(name string) (RuleState, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	st, ok := e.state[name]
+	if !ok {
+		return RuleState{}, false
+	}
+	return *st, true
+}
+
+// Evaluate проверяет все правила один раз. Ошибка получения величины для
+// одного правила не мешает проверить остальные; ошибки возвращаются вместе.
+func (e *AlertEngine) Evaluate(ctx context.Context) ([]Alert, error) {
+	now := e.now()
+	values := newAlertValues(e.ex, now)
+	var errs []error
+	var fired []Alert
+
+	e.mu.Lock()
+	for _, r := range e.rules {
+		v, err := values.get(r.Condition.Metric)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", r.Name, err))
+			continue
+		}
+		st := e.state[r.Name]
+		// Правило с измененным выражением начинается с чистого состояния
+		if st == nil || st.Expr != r.Expr {
+			st = &RuleState{Expr: r.Expr}
+			e.state[r.Name] = st
+		}
+		if r.step(st, v, now) {
+			fired = append(fired, Alert{
+				Rule:      r.Name,
+				Expr:      r.Expr,
+				Metric:    r.Condition.Metric.String(),
+				Pair:      r.Condition.Metric.Pair,
+				Op:        r.Condition.Op,
+				Threshold: r.Condition.Threshold,
+				Value:     v,
+				Time:      now,
+			})
+		}
+	}
+	if err := e.saveStateLocked(); err != nil {
+		errs = append(errs, err)
+	}
+	e.mu.Unlock()
+
+	for _, a := range fired {
+		for _, s := range e.sinks {
+			if err := s.Notify(ctx, a); err != nil {
+				errs = append(errs, fmt.Errorf("%s: notify: %w", a.Rule, err))
+			}
+		}
+	}
+	return fired, errors.Join(errs...)
+}
+
+// Run проверяет правила с интервалом до отмены контекста. Ошибки
+// передаются в onError и не прерывают работу.
+func (e *AlertEngine) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if _, err := e.Evaluate(ctx); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// alertValues получает величины для одной проверки: тикер запрашивается
+// один раз, свечи - один раз на пару и разрешение.
+type alertValues struct {
+	ex      exmo.Exchanger
+	now     time.Time
+	ticker  exmo.Ticker
+	tickErr error
+	closes  map[string][]float64
+}
+
+func newAlertValues(ex exmo.Exchanger, now time.Time) *alertValues {
+	return &alertValues{ex: ex, now: now, closes: make(map[string][]float64)}
+}
+
+func (a *alertValues) get(m AlertMetric) (float64, error) {
+	if m.Indicator == "" {
+		return a.tickerValue(m)
+	}
+
+	key := fmt.Sprintf("%s/%d/%d", m.Pair, m.Resolution, m.Period)
+	closes, ok := a.closes[key]
+	if !ok {
+		step := time.Duration(m.Resolution) * time.Minute
+		from := a.now.Add(-step * time.Duration(indicator.Warmup(m.Period)))
+		var err error
+		closes, err = a.ex.GetClosePrice(m.Pair, m.Resolution, from, a.now)
+		if err != nil {
+			return 0, err
+		}
+		a.closes[key] = closes
+	}
+
+	var series []float64
+	switch m.Indicator {
+	case "rsi":
+		series = indicator.CalculateRSI(closes, m.Period)
+	case "sma":
+		series = indicator.CalculateSMA(closes, m.Period)
+	case "ema":
+		series = indicator.CalculateEMA(closes, m.Period)
+	}
+	if len(series) == 0 || math.IsNaN(series[len(series)-1]) {
+		return 0, fmt.Errorf("not enough candles for %s: got %d", m, len(closes))
+	}
+	return series[len(series)-1], nil
+}
+
+func (a *alertValues) tickerValue(m AlertMetric) (float64, error) {
+	if a.ticker == nil && a.tickErr == nil {
+		a.ticker, a.tickErr = a.ex.GetTicker()
+	}
+	if a.tickErr != nil {
+		return 0, a.tickErr
+	}
+	v, ok := a.ticker[m.Pair]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", exmo.ErrInvalidPair, m.Pair)
+	}
+
+	var raw string
+	switch m.Field {
+	case "spread":
+		bid, ask := parsePrice(v.BuyPrice), parsePrice(v.SellPrice)
+		if bid <= 0 || ask <= 0 {
+			return 0, fmt.Errorf("no bid or ask for %s", m.Pair)
+		}
+		// Спред в базисных пунктах к середине
+		return (ask - bid) / ((ask + bid) / 2) * 10000, nil
+	case "last_trade":
+		raw = v.LastTrade
+	case "buy_price":
+		raw = v.BuyPrice
+	case "sell_price":
+		raw = v.SellPrice
+	case "high":
+		raw = v.High
+	case "low":
+		raw = v.Low
+	case "avg":
+		raw = v.Avg
+	}
+	f, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s: invalid %s %q", m.Pair, m.Field, raw)
+	}
+	return f, nil
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func TestParseAlertCondition(t *testing.T) {
+	cond, err := ParseAlertCondition("BTC_USD last_trade crosses 60000")
+	require.NoError(t, err)
+	assert.Equal(t, AlertCondition{Metric: AlertMetric{Pair: "BTC_USD", Field: "last_trade"}, Op: AlertCrosses, Threshold: 60000}, cond)
+
+	cond, err = ParseAlertCondition("RSI(14) on eth_usd 1h < 30")
+	require.NoError(t, err)
+	assert.Equal(t, AlertMetric{Pair: "ETH_USD", Indicator: "rsi", Period: 14, Resolution: 60}, cond.Metric)
+	assert.Equal(t, "RSI(14) on ETH_USD 1h", cond.Metric.String())
+
+	cond, err = ParseAlertCondition("BTC_USD spread > 50bps")
+	require.NoError(t, err)
+	assert.Equal(t, 50.0, cond.Threshold)
+
+	for _, bad := range []string{
+		"BTC_USD last_trade crosses",
+		"BTC_USD volume > 1",
+		"BTCUSD last_trade > 1",
+		"BTC_USD last_trade ~ 1",
+		"BTC_USD last_trade > 1bps",
+		"MACD(3) on BTC_USD 1h > 1",
+		"RSI(0) on BTC_USD 1h > 1",
+		"RSI(14) on BTC_USD 1x > 1",
+	} {
+		_, err := ParseAlertCondition(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func stepAll(t *testing.T, r *AlertRule, values ...float64) []bool {
+	t.Helper()
+	var st RuleState
+	now := time.Unix(0, 0)
+	fired := make([]bool, len(values))
+	for i, v := range values {
+		now = now.Add(time.Minute)
+		fired[i] = r.step(&st, v, now)
+	}
+	return fired
+}
+
+func TestAlertRule_Step(t *testing.T) {
+	above, err := NewAlertRule("", "BTC_USD last_trade > 100", 5, 0)
+	require.NoError(t, err)
+	// Повторное срабатывание только после отката на гистерезис
+	assert.Equal(t, []bool{false, true, false, false, false, true},
+		stepAll(t, above, 90, 101, 97, 102, 94, 101))
+
+	crosses, err := NewAlertRule("", "BTC_USD last_trade crosses 100", 0, 0)
+	require.NoError(t, err)
+	// Первое значение только задает сторону
+	assert.Equal(t, []bool{false, false, true, false, true},
+		stepAll(t, crosses, 110, 105, 99, 98, 100))
+
+	up, err := NewAlertRule("", "BTC_USD last_trade crosses_above 100", 2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{false, false, true, false, false, true},
+		stepAll(t, up, 95, 101, 103, 99, 97, 102))
+
+	cooldown, err := NewAlertRule("", "BTC_USD last_trade < 10", 0, 3*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []bool{true, false, false, false, false, true},
+		stepAll(t, cooldown, 5, 11, 5, 11, 11, 5))
+}
+
+func TestAlertEngine_Evaluate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.EXPECT().GetTicker().Return(exmo.Ticker{
+		"BTC_USD": {LastTrade: "61000", BuyPrice: "60900", SellPrice: "61100"},
+	}, nil).Times(2)
+	closes := []float64{10, 9, 8, 7, 6, 5, 4, 3}
+	mock.EXPECT().GetClosePrice("ETH_USD", 60, now.Add(-16*time.Hour), now).Return(closes, nil)
+	mock.EXPECT().GetClosePrice("ETH_USD", 60, now.Add(-16*time.Hour).Add(time.Hour), now.Add(time.Hour)).Return(closes, nil)
+
+	var rules []*AlertRule
+	for _, expr := range []string{
+		"BTC_USD last_trade > 60000",
+		"BTC_USD spread > 30bps",
+		"RSI(3) on ETH_USD 1h < 30",
+		"SMA(3) on ETH_USD 1h < 5",
+		"XRP_USD last_trade > 1",
+	} {
+		r, err := NewAlertRule("", expr, 0, 0)
+		require.NoError(t, err)
+		rules = append(rules, r)
+	}
+
+	var out bytes.Buffer
+	statePath := filepath.Join(t.TempDir(), "state.json")
+	clock := now
+	engine, err := NewAlertEngine(mock, rules, WithAlertSinks(NewWriterSink(&out)),
+		WithAlertState(statePath), WithAlertClock(func() time.Time { return clock }))
+	require.NoError(t, err)
+
+	alerts, err := engine.Evaluate(context.Background())
+	assert.ErrorIs(t, err, exmo.ErrInvalidPair)
+	require.Len(t, alerts, 4)
+	assert.Equal(t, "BTC_USD last_trade > 60000", alerts[0].Rule)
+	assert.Equal(t, "RSI(3) on ETH_USD 1h", alerts[2].Metric)
+	assert.Equal(t, "SMA(3) on ETH_USD 1h < 5", alerts[3].Rule)
+	assert.Contains(t, out.String(), "BTC_USD last_trade = 61000 (> 60000)")
+
+	// Спред 200/61000 ~ 32.8 б.п. выше порога
+	st, ok := engine.State("BTC_USD spread > 30bps")
+	require.True(t, ok)
+	assert.InDelta(t, 32.78, st.LastValue, 0.01)
+
+	// Состояние переживает перезапуск: активные правила не срабатывают повторно
+	restarted, err := NewAlertEngine(mock, rules, WithAlertState(statePath),
+		WithAlertClock(func() time.Time { return clock.Add(time.Hour) }))
+	require.NoError(t, err)
+	alerts, _ = restarted.Evaluate(context.Background())
+	assert.Empty(t, alerts)
+}
+
+func TestAlertSinks(t *testing.T) {
+	alert := Alert{Rule: "r", Metric: "BTC_USD last_trade", Op: AlertAbove, Threshold: 1, Value: 2, Time: time.Unix(0, 0)}
+
+	var got Alert
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		body, _ := io.ReadAll(r.Body)
+		require.NoError(t, json.Unmarshal(body, &got))
+		if got.Rule == "fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer ts.Close()
+
+	sink := NewWebhookSink(ts.URL, nil)
+	require.NoError(t, sink.Notify(context.Background(), alert))
+	assert.Equal(t, alert.Rule, got.Rule)
+	failing := alert
+	failing.Rule = "fail"
+	assert.ErrorContains(t, sink.Notify(context.Background(), failing), "status 502")
+
+	path := filepath.Join(t.TempDir(), "alerts", "log.ndjson")
+	file := NewFileSink(path)
+	require.NoError(t, file.Notify(context.Background(), alert))
+	require.NoError(t, file.Notify(context.Background(), alert))
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+}
+
+func TestRun_Alert(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+	mock.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "61000"}}, nil)
+
+	code, stdout, stderr := runCLI("alert", "-once", "-state", "", "-rule", "BTC_USD last_trade crosses_above 1", "-rule", "BTC_USD last_trade > 60000")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, 1, strings.Count(stdout, "\n"))
+	assert.Contains(t, stdout, "BTC_USD last_trade > 60000")
+
+	code, _, _ = runCLI("alert", "-once")
+	assert.Equal(t, exitUsage, code)
+	code, _, _ = runCLI("alert", "-rule", "nonsense")
+	assert.Equal(t, exitUsage, code)
+
+	mock.EXPECT().GetTicker().Return(nil, errors.New("down"))
+	code, _, stderr = runCLI("alert", "-once", "-state", "", "-rule", "BTC_USD last_trade > 1")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "down")
+}
@@ -0,0 +1,435 @@
+// Package arbitrage ищет треугольный арбитраж на одной бирже: перебирает
+// круги из трех валют по тикеру и оценивает их доходность по глубине стаканов.
+package arbitrage
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// arbitragePairsTTL - как часто сканер перечитывает список пар из тикера.
+const arbitragePairsTTL = 10 * time.Minute
+
+// Triangle - три валюты, попарно связанные торговыми парами.
+type Triangle struct {
+	Currencies [3]string
+	Pairs      [3]string // Pairs[i] связывает Currencies[i] и Currencies[(i+1)%3]
+}
+
+// FindTriangles перечисляет все треугольники валют среди переданных пар.
+func FindTriangles(pairs []string) []Triangle {
+	links := make(map[[2]string]string)
+	neighbors := make(map[string]map[string]bool)
+	for _, pair := range pairs {
+		base, quote, err := exmo.SplitPair(pair)
+		if err != nil || base == quote {
+			continue
+		}
+		links[[2]string{base, quote}] = pair
+		links[[2]string{quote, base}] = pair
+		for _, c := range [][2]string{{base, quote}, {quote, base}} {
+			if neighbors[c[0]] == nil {
+				neighbors[c[0]] = make(map[string]bool)
+			}
+			neighbors[c[0]][c[1]] = true
+		}
+	}
+
+	currencies := make([]string, 0, len(neighbors))
+	for c := range neighbors {
+		currencies = append(currencies, c)
+	}
+	sort.Strings(currencies)
+
+	var result []Triangle
+	for _, a := range currencies {
+		for _, b := range currencies {
+			if b <= a || !neighbors[a][b] {
+				continue
+			}
+			for _, c := range currencies {
+				if c <= b || !neighbors[a][c] || !neighbors[b][c] {
+					continue
+				}
+				result = append(result, Triangle{
+					Currencies: [3]string{a, b, c},
+					Pairs:      [3]string{links[[2]string{a, b}], links[[2]string{b, c}], links[[2]string{c, a}]},
+				})
+			}
+		}
+	}
+	return result
+}
+
+// ArbitrageLeg - одна сделка круга.
+type ArbitrageLeg struct {
+	Pair     string    `json:"pair"`
+	From     string    `json:"from"`
+	To       string    `json:"to"`
+	Side     exmo.Type `json:"side"`
+	AvgPrice float64   `json:"avg_price"`
+	In       float64   `json:"in"`
+	Out      float64   `json:"out"`
+}
+
+// ArbitrageOpportunity - круг с доходностью выше порога. Size - максимальная
+// сумма в стартовой валюте, при которой доходность по глубине стаканов
+// остается не ниже порога; Return и Legs посчитаны для этой суммы.
+type ArbitrageOpportunity struct {
+	Start      string         `json:"start"`
+	Cycle      [3]string      `json:"cycle"`
+	TopReturn  float64        `json:"top_return"`
+	Return     float64        `json:"return"`
+	Size       float64        `json:"size"`
+	Profit     float64        `json:"profit"`
+	Legs       []ArbitrageLeg `json:"legs"`
+	ObservedAt time.Time      `json:"observed_at"`
+}
+
+func (o ArbitrageOpportunity) String() string {
+	return fmt.Sprintf("%s: top %.4f%%, %.4f%% on %s %s (profit %s %s)",
+		strings.Join(append(o.Cycle[:], o.Cycle[0]), "->"),
+		o.TopReturn*100, o.Return*100, formatFloat(o.Size), o.Start, formatFloat(o.Profit), o.Start)
+}
+
+// ArbitrageScanner ищет треугольный арбитраж по тикеру и стаканам биржи.
+type ArbitrageScanner struct {
+	exchange  exmo.Exchanger
+	fee       float64
+	minReturn float64
+	depth     int
+	chunk     int
+	interval  time.Duration
+	budget    int
+	now       func() time.Time
+
+	mu        sync.Mutex
+	triangles []Triangle
+	pairs     []string
+	loadedAt  time.Time
+}
+
+type ArbitrageOption func(*ArbitrageScanner)
+
+// WithTakerFee задает комиссию тейкера в долях на каждую сделку круга.
+func WithTakerFee(fee float64) ArbitrageOption {
+	return func(s *ArbitrageScanner) {
+		s.fee = fee
+	}
+}
+
+// WithMinReturn задает порог чистой доходности круга в долях (0.001 = 0.1%).
+func WithMinReturn(r float64) ArbitrageOption {
+	return func(s *ArbitrageScanner) {
+		s.minReturn = r
+	}
+}
+
+// WithBookDepth задает глубину запрашиваемых стаканов.
+func WithBookDepth(limit int) ArbitrageOption {
+	return func(s *ArbitrageScanner) {
+		s.depth = limit
+	}
+}
+
+// WithPollInterval задает желаемый интервал между сканированиями.
+func WithPollInterval(d time.Duration) ArbitrageOption {
+	return func(s *ArbitrageScanner) {
+		s.interval = d
+	}
+}
+
+// WithRequestBudget ограничивает число запросов к бирже в минуту.
+func WithRequestBudget(perMinute int) ArbitrageOption {
+	return func(s *ArbitrageScanner) {
+		s.budget = perMinute
+	}
+}
+
+func NewArbitrageScanner(exchange exmo.Exchanger, opts ...ArbitrageOption) *ArbitrageScanner {
+	s := &ArbitrageScanner{
+		exchange: exchange,
+		fee:      0.003,
+		depth:    50,
+		chunk:    40,
+		interval: 10 * time.Second,
+		budget:   60,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Scan выполняет один проход: при необходимости обновляет список пар,
+// загружает стаканы и возвращает возможности, отсортированные по доходности.
+func (s *ArbitrageScanner) Scan() ([]ArbitrageOpportunity, error) {
+	triangles, pairs, err := s.loadTriangles()
+	if err != nil {
+		return nil, err
+	}
+	if len(triangles) == 0 {
+		return nil, nil
+	}
+
+	book := make(exmo.OrderBook, len(pairs))
+	for start := 0; start < len(pairs); start += s.chunk {
+		end := start + s.chunk
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		part, err := s.exchange.GetOrderBook(s.depth, pairs[start:end]...)
+		if err != nil {
+			return nil, err
+		}
+		for pair, levels := range part {
+			book[pair] = levels
+		}
+	}
+
+	now := s.now()
+	var result []ArbitrageOpportunity
+	for _, tr := range triangles {
+		for _, cycle := range [][3]string{
+			tr.Currencies,
+			{tr.Currencies[0], tr.Currencies[2], tr.Currencies[1]},
+		} {
+			legs, err := newCycleLegs(cycle, book)
+			if err != nil {
+				continue
+			}
+			opp, ok := s.evaluate(cycle, legs)
+			if !ok {
+				continue
+			}
+			opp.ObservedAt = now
+			result = append(result, opp)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].TopReturn > result[j].TopReturn })
+	return result, nil
+}
+
+// RequestsPerScan возвращает число запросов одного прохода без учета тикера.
+func (s *ArbitrageScanner) RequestsPerScan() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := (len(s.pairs) + s.chunk - 1) / s.chunk
+	if n == 0 {
+		n = 1
+	}
+	return n
+}
+
+// Interval возвращает фактический интервал опроса: не меньше заданного
+// и такой, чтобы проходы укладывались в бюджет запросов.
+func (s *ArbitrageScanner) Interval() time.Duration {
+	interval := s.interval
+	if s.budget > 0 {
+		minimal := time.Duration(s.RequestsPerScan()) * time.Minute / time.Duration(s.budget)
+		if minimal > interval {
+			interval = minimal
+		}
+	}
+	return interval
+}
+
+// Run сканирует биржу до отмены контекста и передает результаты в handle.
+// Ошибки отдельных проходов передаются в handle и не прерывают работу.
+func (s *ArbitrageScanner) Run(ctx context.Context, handle func([]ArbitrageOpportunity, error)) error {
+	for {
+		opps, err := s.Scan()
+		handle(opps, err)
+
+		timer := time.NewTimer(s.Interval())
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+func (s *ArbitrageScanner) loadTriangles() ([]Triangle, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.triangles != nil && s.now().Sub(s.loadedAt) < arbitragePairsTTL {
+		return s.triangles, s.pairs, nil
+	}
+
+	ticker, err := s.exchange.GetTicker()
+	if err != nil {
+		return nil, nil, err
+	}
+	all := make([]string, 0, len(ticker))
+	for pair := range ticker {
+		all = append(all, pair)
+	}
+	triangles := FindTriangles(all)
+
+	used := make(map[string]struct{})
+	for _, tr := range triangles {
+		for _, p := range tr.Pairs {
+			used[p] = struct{}{}
+		}
+	}
+	pairs := make([]string, 0, len(used))
+	for p := range used {
+		pairs = append(pairs, p)
+	}
+	sort.Strings(pairs)
+
+	s.triangles, s.pairs, s.loadedAt = triangles, pairs, s.now()
+	return triangles, pairs, nil
+}
+
+// evaluate считает доходность на вершине стакана и ищет бинарным поиском
+// максимальный объем, при котором доходность не ниже порога.
+func (s *ArbitrageScanner) evaluate(cycle [3]string, legs []cycleLeg) (ArbitrageOpportunity, bool) {
+	top := 1.0
+	for _, l := range legs {
+		top *= l.topRate() * (1 - s.fee)
+	}
+	top--
+	if top < s.minReturn {
+		return ArbitrageOpportunity{}, false
+	}
+
+	hi := legs[0].capacity()
+	lo := 0.0
+	for i := 0; i < 60; i++ {
+		mid := (lo + hi) / 2
+		if r, _, ok := s.simulate(legs, mid); ok && r >= s.minReturn {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	if lo <= 0 {
+		return ArbitrageOpportunity{}, false
+	}
+	r, trace, _ := s.simulate(legs, lo)
+	return ArbitrageOpportunity{
+		Start:     cycle[0],
+		Cycle:     cycle,
+		TopReturn: top,
+		Return:    r,
+		Size:      lo,
+		Profit:    lo * r,
+		Legs:      trace,
+	}, true
+}
+
+func (s *ArbitrageScanner) simulate(legs []cycleLeg, amount float64) (float64, []ArbitrageLeg, bool) {
+	if amount <= 0 {
+		return 0, nil, false
+	}
+	trace := make([]ArbitrageLeg, 0, len(legs))
+	in := amount
+	for _, l := range legs {
+		out, ok := l.convert(in)
+		if !ok {
+			return 0, nil, false
+		}
+		out *= 1 - s.fee
+		leg := ArbitrageLeg{Pair: l.pair, From: l.from, To: l.to, Side: l.side, In: in, Out: out}
+		if l.side == exmo.Sell {
+			leg.AvgPrice = out / (1 - s.fee) / in
+		} else {
+			leg.AvgPrice = in / (out / (1 - s.fee))
+		}
+		trace = append(trace, leg)
+		in = out
+	}
+	return in/amount - 1, trace, true
+}
+
+type cycleLeg struct {
+	pair     string
+	from, to string
+	side     exmo.Type
+	levels   []exmo.BookLevel
+}
+
+func newCycleLegs(cycle [3]string, book exmo.OrderBook) ([]cycleLeg, error) {
+	legs := make([]cycleLeg, 0, 3)
+	for i := range cycle {
+		from, to := cycle[i], cycle[(i+1)%3]
+		leg := cycleLeg{from: from, to: to}
+		var err error
+		if levels, ok := book[from+"_"+to]; ok {
+			leg.pair, leg.side = from+"_"+to, exmo.Sell
+			leg.levels, err = levels.Bids()
+		} else if levels, ok := book[to+"_"+from]; ok {
+			leg.pair, leg.side = to+"_"+from, exmo.Buy
+			leg.levels, err = levels.Asks()
+		} else {
+			return nil, fmt.Errorf("no order book between %s and %s", from, to)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(leg.levels) == 0 {
+			return nil, fmt.Errorf("empty order book for %s", leg.pair)
+		}
+		legs = append(legs, leg)
+	}
+	return legs, nil
+}
+
+// topRate - курс from -> to по лучшему уровню без комиссии.
+func (l cycleLeg) topRate() float64 {
+	if l.side == exmo.Sell {
+		return l.levels[0].Price
+	}
+	return 1 / l.levels[0].Price
+}
+
+// capacity - сколько валюты from может принять вся доступная глубина.
+func (l cycleLeg) capacity() float64 {
+	var total float64
+	for _, lvl := range l.levels {
+		if l.side == exmo.Sell {
+			total += lvl.Quantity
+		} else {
+			total += lvl.Quantity * lvl.Price
+		}
+	}
+	return total
+}
+
+// convert проводит amount валюты from через стакан и возвращает сумму в to.
+func (l cycleLeg) convert(amount float64) (float64, bool) {
+	var out float64
+	left := amount
+	for _, lvl := range l.levels {
+		if left <= 0 {
+			break
+		}
+		if l.side == exmo.Sell {
+			take := math.Min(left, lvl.Quantity)
+			out += take * lvl.Price
+			left -= take
+		} else {
+			spend := math.Min(left, lvl.Quantity*lvl.Price)
+			out += spend / lvl.Price
+			left -= spend
+		}
+	}
+	return out, left <= amount*1e-12
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,139 @@
+package arbitrage
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func arbitrageBook() exmo.OrderBook {
+	return exmo.OrderBook{
+		"BTC_EUR": {
+			Bid: [][]string{{"100", "1"}, {"90", "10"}},
+			Ask: [][]string{{"101", "5"}},
+		},
+		"EUR_USD": {
+			Bid: [][]string{{"1.1", "1000"}},
+			Ask: [][]string{{"1.11", "1000"}},
+		},
+		"BTC_USD": {
+			Bid: [][]string{{"99", "5"}},
+			Ask: [][]string{{"100", "5"}},
+		},
+	}
+}
+
+func TestFindTriangles(t *testing.T) {
+	triangles := FindTriangles([]string{"BTC_USD", "BTC_EUR", "EUR_USD", "ETH_BTC", "bad"})
+
+	require.Len(t, triangles, 1)
+	assert.Equal(t, [3]string{"BTC", "EUR", "USD"}, triangles[0].Currencies)
+	assert.Equal(t, [3]string{"BTC_EUR", "EUR_USD", "BTC_USD"}, triangles[0].Pairs)
+}
+
+func TestArbitrageScanner_Scan(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_EUR": {}, "EUR_USD": {}, "BTC_USD": {}, "ETH_BTC": {}}, nil)
+	mockExchanger.EXPECT().GetOrderBook(50, "BTC_EUR", "BTC_USD", "EUR_USD").Return(arbitrageBook(), nil).Times(2)
+
+	scanner := NewArbitrageScanner(mockExchanger, WithTakerFee(0), WithMinReturn(0.05))
+
+	opps, err := scanner.Scan()
+	require.NoError(t, err)
+	require.Len(t, opps, 1)
+
+	opp := opps[0]
+	assert.Equal(t, "BTC", opp.Start)
+	assert.Equal(t, [3]string{"BTC", "EUR", "USD"}, opp.Cycle)
+	assert.InDelta(t, 0.1, opp.TopReturn, 1e-9)
+	// Второй уровень BTC_EUR по 90 снижает доходность до порога при 11/6 BTC
+	assert.InDelta(t, 11.0/6, opp.Size, 1e-6)
+	assert.InDelta(t, 0.05, opp.Return, 1e-6)
+	require.Len(t, opp.Legs, 3)
+	assert.Equal(t, exmo.Buy, opp.Legs[2].Side)
+
+	// Повторный проход не запрашивает тикер заново
+	_, err = scanner.Scan()
+	require.NoError(t, err)
+}
+
+func TestArbitrageScanner_Fees(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_EUR": {}, "EUR_USD": {}, "BTC_USD": {}}, nil)
+	mockExchanger.EXPECT().GetOrderBook(50, gomock.Any()).Return(arbitrageBook(), nil)
+
+	// 10% грязной доходности съедаются комиссией 4% на каждую сделку
+	scanner := NewArbitrageScanner(mockExchanger, WithTakerFee(0.04), WithMinReturn(0))
+	opps, err := scanner.Scan()
+
+	require.NoError(t, err)
+	assert.Empty(t, opps)
+}
+
+func TestArbitrageScanner_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	scanner := NewArbitrageScanner(mockExchanger)
+
+	mockExchanger.EXPECT().GetTicker().Return(nil, errors.New("exchange error"))
+	_, err := scanner.Scan()
+	assert.Error(t, err)
+
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_EUR": {}, "EUR_USD": {}, "BTC_USD": {}}, nil)
+	mockExchanger.EXPECT().GetOrderBook(50, gomock.Any()).Return(nil, errors.New("exchange error"))
+	_, err = scanner.Scan()
+	assert.Error(t, err)
+}
+
+func TestArbitrageScanner_Interval(t *testing.T) {
+	scanner := NewArbitrageScanner(nil, WithPollInterval(time.Second), WithRequestBudget(6))
+	assert.Equal(t, 10*time.Second, scanner.Interval())
+
+	scanner = NewArbitrageScanner(nil, WithPollInterval(time.Minute), WithRequestBudget(600))
+	assert.Equal(t, time.Minute, scanner.Interval())
+}
+
+func TestArbitrageScanner_Run(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_EUR": {}, "EUR_USD": {}, "BTC_USD": {}}, nil)
+	mockExchanger.EXPECT().GetOrderBook(50, gomock.Any()).Return(arbitrageBook(), nil).MinTimes(2)
+
+	scanner := NewArbitrageScanner(mockExchanger,
+		WithTakerFee(0),
+		WithPollInterval(time.Millisecond),
+		WithRequestBudget(0),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	scans := 0
+	err := scanner.Run(ctx, func(opps []ArbitrageOpportunity, err error) {
+		assert.NoError(t, err)
+		assert.NotEmpty(t, opps)
+		scans++
+		if scans == 2 {
+			cancel()
+		}
+	})
+
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 2, scans)
+}
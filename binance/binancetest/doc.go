@@ -0,0 +1,4 @@
+// Package binancetest - поддельный публичный API Binance Spot для тестов,
+// отдельно от пакета binance, чтобы его импортеры не тянули
+// net/http/httptest.
+package binancetest
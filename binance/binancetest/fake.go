@@ -0,0 +1,330 @@
+package binancetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// FakeTicker - значения /api/v3/ticker/24hr для одной пары FakeBinance.
+type FakeTicker struct {
+	Bid, Ask, Last   float64
+	High, Low, Avg   float64
+	Volume, QuoteVol float64
+	CloseTimeMillis  int64
+}
+
+type fakeTrade struct {
+	id         int64
+	price, qty float64
+	time       int64
+	buyerMaker bool
+}
+
+// fakeSymbol и fakeFilter - описание пары в ответе /api/v3/exchangeInfo.
+type fakeSymbol struct {
+	Symbol     string       `json:"symbol"`
+	Status     string       `json:"status"`
+	BaseAsset  string       `json:"baseAsset"`
+	QuoteAsset string       `json:"quoteAsset"`
+	Filters    []fakeFilter `json:"filters"`
+}
+
+type fakeFilter struct {
+	FilterType  string `json:"filterType"`
+	MinPrice    string `json:"minPrice,omitempty"`
+	MaxPrice    string `json:"maxPrice,omitempty"`
+	TickSize    string `json:"tickSize,omitempty"`
+	MinQty      string `json:"minQty,omitempty"`
+	MaxQty      string `json:"maxQty,omitempty"`
+	StepSize    string `json:"stepSize,omitempty"`
+	MinNotional string `json:"minNotional,omitempty"`
+	MaxNotional string `json:"maxNotional,omitempty"`
+}
+
+type fakeFailure struct {
+	status int
+	code   int
+	msg    string
+}
+
+// FakeBinance - поддельный публичный API Binance Spot для тестов. Пары
+// задаются в форме Binance: AddSymbol("BTC", "USDT") создает BTCUSDT.
+type FakeBinance struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	symbols  []fakeSymbol
+	tickers  map[string]FakeTicker
+	books    map[string][2][]exmo.BookLevel
+	trades   map[string][]fakeTrade
+	klines   map[string][]exmo.Candle
+	failures map[string]fakeFailure
+	requests map[string]int
+	nextID   int64
+}
+
+// NewFakeBinance запускает сервер. Его нужно закрыть через Close.
+func NewFakeBinance() *FakeBinance {
+	f := &FakeBinance{
+		tickers:  make(map[string]FakeTicker),
+		books:    make(map[string][2][]exmo.BookLevel),
+		trades:   make(map[string][]fakeTrade),
+		klines:   make(map[string][]exmo.Candle),
+		failures: make(map[string]fakeFailure),
+		requests: make(map[string]int),
+		nextID:   1,
+	}
+	mux := http.NewServeMux()
+	for endpoint, h := range map[string]func(url.Values) (interface{}, *fakeFailure){
+		"/api/v3/exchangeInfo": f.handleExchangeInfo,
+		"/api/v3/ticker/24hr":  f.handleTicker,
+		"/api/v3/trades":       f.handleTrades,
+		"/api/v3/depth":        f.handleDepth,
+		"/api/v3/klines":       f.handleKlines,
+	} {
+		mux.HandleFunc(endpoint, f.wrap(endpoint, h))
+	}
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+// URL возвращает адрес сервера для binance.WithBaseURL.
+func (f *FakeBinance) URL() string {
+	return f.server.URL
+}
+
+func (f *FakeBinance) Close() {
+	f.server.Close()
+}
+
+// Client создает клиента, направленного на этот сервер.
+func (f *FakeBinance) Client(opts ...binance.Option) *binance.Client {
+	return binance.NewClient(append([]binance.Option{binance.WithBaseURL(f.server.URL)}, opts...)...)
+}
+
+// AddSymbol добавляет торгуемую пару с шагом цены 0.01, минимальным
+// количеством 0.0001 и минимальной суммой 5.
+func (f *FakeBinance) AddSymbol(base, quote string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.symbols = append(f.symbols, fakeSymbol{
+		Symbol:     base + quote,
+		Status:     "TRADING",
+		BaseAsset:  base,
+		QuoteAsset: quote,
+		Filters: []fakeFilter{
+			{FilterType: "PRICE_FILTER", MinPrice: "0.01", MaxPrice: "1000000", TickSize: "0.01"},
+			{FilterType: "LOT_SIZE", MinQty: "0.0001", MaxQty: "9000", StepSize: "0.0001"},
+			{FilterType: "NOTIONAL", MinNotional: "5", MaxNotional: "9000000"},
+		},
+	})
+}
+
+// SetTicker задает значения тикера пары, например "BTCUSDT".
+func (f *FakeBinance) SetTicker(symbol string, t FakeTicker) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tickers[symbol] = t
+}
+
+// SetBook задает стакан: bids по убыванию цены, asks по возрастанию.
+func (f *FakeBinance) SetBook(symbol string, bids, asks []exmo.BookLevel) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.books[symbol] = [2][]exmo.BookLevel{bids, asks}
+}
+
+// AddTrade добавляет сделку в конец ленты. buyerMaker означает, что
+// сделку инициировал продавец.
+func (f *FakeBinance) AddTrade(symbol string, price, qty float64, timeMillis int64, buyerMaker bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.trades[symbol] = append(f.trades[symbol], fakeTrade{id: f.nextID, price: price, qty: qty, time: timeMillis, buyerMaker: buyerMaker})
+	f.nextID++
+}
+
+// SetKlines задает свечи пары в порядке возрастания времени.
+func (f *FakeBinance) SetKlines(symbol string, candles []exmo.Candle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.klines[symbol] = candles
+}
+
+// Fail заставляет эндпоинт, например "/api/v3/depth", отвечать ошибкой
+// {"code":code,"msg":msg} со статусом status до вызова ClearFailures.
+func (f *FakeBinance) Fail(endpoint string, status, code int, msg string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures[endpoint] = fakeFailure{status: status, code: code, msg: msg}
+}
+
+// ClearFailures убирает ошибки, заданные Fail.
+func (f *FakeBinance) ClearFailures() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failures = make(map[string]fakeFailure)
+}
+
+// Requests возвращает число запросов к эндпоинту.
+func (f *FakeBinance) Requests(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[endpoint]
+}
+
+func (f *FakeBinance) wrap(endpoint string, h func(url.Values) (interface{}, *fakeFailure)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests[endpoint]++
+		failure, failed := f.failures[endpoint]
+		f.mu.Unlock()
+
+		var result interface{}
+		if !failed {
+			var fail *fakeFailure
+			result, fail = h(r.URL.Query())
+			if fail != nil {
+				failure, failed = *fail, true
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if failed {
+			w.WriteHeader(failure.status)
+			json.NewEncoder(w).Encode(map[string]interface{}{"code": failure.code, "msg": failure.msg})
+			return
+		}
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+var errFakeSymbol = &fakeFailure{status: http.StatusBadRequest, code: -1121, msg: "Invalid symbol."}
+
+// knownLocked проверяет, что пара добавлена через AddSymbol.
+func (f *FakeBinance) knownLocked(symbol string) bool {
+	for _, s := range f.symbols {
+		if s.Symbol == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *FakeBinance) handleExchangeInfo(url.Values) (interface{}, *fakeFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return map[string]interface{}{"timezone": "UTC", "symbols": f.symbols}, nil
+}
+
+func (f *FakeBinance) handleTicker(url.Values) (interface{}, *fakeFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	result := make([]map[string]interface{}, 0, len(f.tickers))
+	for symbol, t := range f.tickers {
+		result = append(result, map[string]interface{}{
+			"symbol":           symbol,
+			"bidPrice":         formatFloat(t.Bid),
+			"askPrice":         formatFloat(t.Ask),
+			"lastPrice":        formatFloat(t.Last),
+			"highPrice":        formatFloat(t.High),
+			"lowPrice":         formatFloat(t.Low),
+			"weightedAvgPrice": formatFloat(t.Avg),
+			"volume":           formatFloat(t.Volume),
+			"quoteVolume":      formatFloat(t.QuoteVol),
+			"closeTime":        t.CloseTimeMillis,
+		})
+	}
+	return result, nil
+}
+
+func (f *FakeBinance) handleTrades(query url.Values) (interface{}, *fakeFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbol := query.Get("symbol")
+	if !f.knownLocked(symbol) {
+		return nil, errFakeSymbol
+	}
+	trades := f.trades[symbol]
+	if limit, err := strconv.Atoi(query.Get("limit")); err == nil && limit < len(trades) {
+		trades = trades[len(trades)-limit:]
+	}
+	result := make([]map[string]interface{}, 0, len(trades))
+	for _, t := range trades {
+		result = append(result, map[string]interface{}{
+			"id":           t.id,
+			"price":        formatFloat(t.price),
+			"qty":          formatFloat(t.qty),
+			"quoteQty":     formatFloat(t.price * t.qty),
+			"time":         t.time,
+			"isBuyerMaker": t.buyerMaker,
+		})
+	}
+	return result, nil
+}
+
+func (f *FakeBinance) handleDepth(query url.Values) (interface{}, *fakeFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbol := query.Get("symbol")
+	if !f.knownLocked(symbol) {
+		return nil, errFakeSymbol
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		limit = 100
+	}
+	book := f.books[symbol]
+	levels := func(side []exmo.BookLevel) [][]string {
+		if len(side) > limit {
+			side = side[:limit]
+		}
+		out := make([][]string, 0, len(side))
+		for _, l := range side {
+			out = append(out, []string{formatFloat(l.Price), formatFloat(l.Quantity)})
+		}
+		return out
+	}
+	return map[string]interface{}{"lastUpdateId": 1, "bids": levels(book[0]), "asks": levels(book[1])}, nil
+}
+
+func (f *FakeBinance) handleKlines(query url.Values) (interface{}, *fakeFailure) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	symbol := query.Get("symbol")
+	if !f.knownLocked(symbol) {
+		return nil, errFakeSymbol
+	}
+	start, _ := strconv.ParseInt(query.Get("startTime"), 10, 64)
+	end, err := strconv.ParseInt(query.Get("endTime"), 10, 64)
+	if err != nil {
+		end = 1<<63 - 1
+	}
+	limit, err := strconv.Atoi(query.Get("limit"))
+	if err != nil {
+		limit = 500
+	}
+	result := make([][]interface{}, 0)
+	for _, c := range f.klines[symbol] {
+		if c.T < start || c.T > end {
+			continue
+		}
+		if len(result) == limit {
+			break
+		}
+		result = append(result, []interface{}{
+			c.T, formatFloat(c.O), formatFloat(c.H), formatFloat(c.L), formatFloat(c.C), formatFloat(c.V),
+			c.T + 59999, "0", 0, "0", "0", "0",
+		})
+	}
+	return result, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
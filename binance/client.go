@@ -0,0 +1,335 @@
+package binance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/symbol"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// DefaultBaseURL - адрес публичного REST API Binance Spot.
+const DefaultBaseURL = "https://api.binance.com"
+
+// DefaultAliases сопоставляет канонический USD стейблкоину USDT:
+// у Binance нет пар к доллару, а стратегии настроены на BTC_USD.
+var DefaultAliases = map[string]string{"USD": "USDT"}
+
+// Client - адаптер публичного API Binance Spot к exmo.Exchanger. Пары
+// принимаются и возвращаются в канонической форме BTC_USD.
+type Client struct {
+	client  *http.Client
+	url     string
+	aliases map[string]string
+	retry   exmo.RetryPolicy
+	limiter *exmo.RateLimiter
+	sleep   func(time.Duration)
+	metrics *telemetry.Metrics
+
+	mu      sync.Mutex
+	symbols *symbols
+}
+
+// Option настраивает Client.
+type Option func(*Client)
+
+// WithBaseURL задает адрес API, например адрес FakeBinance.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.url = u
+	}
+}
+
+// WithTimeout ограничивает время одного HTTP-запроса.
+func WithTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.client.Timeout = d
+	}
+}
+
+// WithAliases заменяет DefaultAliases: ключ - каноническая валюта,
+// значение - валюта Binance.
+func WithAliases(aliases map[string]string) Option {
+	return func(c *Client) {
+		c.aliases = aliases
+	}
+}
+
+// WithRetry включает повторы временных ошибок: сетевых сбоев, 429, 418 и 5xx.
+func WithRetry(policy exmo.RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit ограничивает частоту запросов клиента, 0 - без ограничения.
+// Запросы сверх лимита ждут своей очереди.
+func WithRateLimit(perMinute, burst int) Option {
+	return func(c *Client) {
+		if perMinute <= 0 {
+			c.limiter = nil
+			return
+		}
+		c.limiter = exmo.NewRateLimiter(perMinute, burst)
+	}
+}
+
+// WithMetrics подключает сбор метрик запросов клиента.
+func WithMetrics(m *telemetry.Metrics) Option {
+	return func(c *Client) {
+		c.metrics = m
+	}
+}
+
+// NewClient создает клиента. Список пар загружается из /api/v3/exchangeInfo
+// при первом запросе.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		client:  &http.Client{},
+		url:     DefaultBaseURL,
+		aliases: DefaultAliases,
+		sleep:   time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// APIError - ошибка, которую вернул Binance: {"code":-1121,"msg":"Invalid symbol."}.
+// Категории проверяются через errors.Is с ошибками пакета exmo.
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+	Code       int
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("binance %s: error %d: %s (status %d)", e.Endpoint, e.Code, e.Message, e.StatusCode)
+	}
+	return fmt.Sprintf("binance %s: %s", e.Endpoint, e.Message)
+}
+
+// Is сопоставляет ошибку с exmo.ErrRateLimited, exmo.ErrInvalidPair и exmo.ErrAuth.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case exmo.ErrRateLimited:
+		// 418 - IP заблокирован за игнорирование 429
+		return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusTeapot
+	case exmo.ErrInvalidPair:
+		return e.Code == -1121
+	case exmo.ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.Code == -2014 || e.Code == -2015
+	}
+	return false
+}
+
+// get выполняет GET-запрос и декодирует ответ в v. Временные ошибки
+// повторяются по RetryPolicy.
+func (c *Client) get(endpoint string, query url.Values, v interface{}) error {
+	u := c.url + endpoint
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	for attempt := 1; ; attempt++ {
+		err := c.do(u, endpoint, v)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(err) {
+			return err
+		}
+		c.metrics.Retried(endpoint)
+		c.sleep(c.retry.Delay(attempt))
+	}
+}
+
+// decodeError - ответ получен, но не разобран. Такие ошибки не повторяются.
+type decodeError struct {
+	endpoint string
+	err      error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("binance %s: decode response: %v", e.endpoint, e.err)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос после ошибки.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return errors.Is(apiErr, exmo.ErrRateLimited) || apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	var decodeErr *decodeError
+	return !errors.As(err, &decodeErr)
+}
+
+// do выполняет одну попытку запроса.
+func (c *Client) do(u, endpoint string, v interface{}) error {
+	if c.limiter != nil {
+		if wait := c.limiter.Reserve(); wait > 0 {
+			c.metrics.LimiterWaited(wait)
+			c.sleep(wait)
+		}
+	}
+	start := time.Now()
+	resp, err := c.client.Get(u)
+	if err != nil {
+		c.metrics.ObserveRequest(endpoint, 0, time.Since(start))
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	c.metrics.ObserveRequest(endpoint, resp.StatusCode, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("binance %s: read response: %w", endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		apiErr := &APIError{Endpoint: endpoint, StatusCode: resp.StatusCode}
+		var payload struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(body, &payload) == nil && payload.Msg != "" {
+			apiErr.Code, apiErr.Message = payload.Code, payload.Msg
+		} else {
+			apiErr.Message = fmt.Sprintf("server returned non-200 status %d", resp.StatusCode)
+		}
+		return apiErr
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		c.metrics.DecodeFailed(endpoint)
+		return &decodeError{endpoint: endpoint, err: err}
+	}
+	return nil
+}
+
+// symbolInfo - пара из /api/v3/exchangeInfo.
+type symbolInfo struct {
+	Symbol     string         `json:"symbol"`
+	Status     string         `json:"status"`
+	BaseAsset  string         `json:"baseAsset"`
+	QuoteAsset string         `json:"quoteAsset"`
+	Filters    []symbolFilter `json:"filters"`
+
+	pair string // каноническая форма
+}
+
+type symbolFilter struct {
+	FilterType  string `json:"filterType"`
+	MinPrice    string `json:"minPrice,omitempty"`
+	MaxPrice    string `json:"maxPrice,omitempty"`
+	TickSize    string `json:"tickSize,omitempty"`
+	MinQty      string `json:"minQty,omitempty"`
+	MaxQty      string `json:"maxQty,omitempty"`
+	StepSize    string `json:"stepSize,omitempty"`
+	MinNotional string `json:"minNotional,omitempty"`
+	MaxNotional string `json:"maxNotional,omitempty"`
+}
+
+// symbols - торгуемые пары Binance в обе стороны: канонические и биржевые.
+type symbols struct {
+	mapper      *symbol.Mapper
+	byPair      map[string]symbolInfo
+	bySymbol    map[string]string
+	currencies  exmo.Currencies
+	pairsSorted []string
+}
+
+// loadSymbols загружает список пар один раз. Ошибка не запоминается,
+// следующий вызов повторит запрос.
+func (c *Client) loadSymbols() (*symbols, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.symbols != nil {
+		return c.symbols, nil
+	}
+
+	var info struct {
+		Symbols []symbolInfo `json:"symbols"`
+	}
+	if err := c.get("/api/v3/exchangeInfo", nil, &info); err != nil {
+		return nil, err
+	}
+	s := &symbols{
+		mapper:     symbol.NewMapper(symbol.Concat, c.aliases),
+		byPair:     make(map[string]symbolInfo),
+		bySymbol:   make(map[string]string),
+		currencies: make(exmo.Currencies),
+	}
+	for _, si := range info.Symbols {
+		if si.Status != "TRADING" {
+			continue
+		}
+		pair := s.mapper.Canonical(si.BaseAsset, si.QuoteAsset)
+		// При псевдониме USD=USDT пара BTCUSD, если она есть, не должна
+		// перекрыть BTCUSDT: берется только символ, в который пара переводится
+		if venue, err := s.mapper.Venue(pair); err != nil || venue != si.Symbol {
+			continue
+		}
+		si.pair = pair
+		s.byPair[pair] = si
+		s.bySymbol[si.Symbol] = pair
+		s.currencies[s.mapper.CanonicalAsset(si.BaseAsset)] = struct{}{}
+		s.currencies[s.mapper.CanonicalAsset(si.QuoteAsset)] = struct{}{}
+		s.pairsSorted = append(s.pairsSorted, pair)
+	}
+	sort.Strings(s.pairsSorted)
+	c.symbols = s
+	return s, nil
+}
+
+// lookup возвращает описание канонической пары.
+func (c *Client) lookup(pair string) (symbolInfo, error) {
+	s, err := c.loadSymbols()
+	if err != nil {
+		return symbolInfo{}, err
+	}
+	p, err := symbol.Parse(pair)
+	if err == nil {
+		_, err = s.mapper.Venue(p.String())
+	}
+	if err != nil {
+		return symbolInfo{}, fmt.Errorf("%w: %v", exmo.ErrInvalidPair, err)
+	}
+	si, ok := s.byPair[p.String()]
+	if !ok {
+		return symbolInfo{}, fmt.Errorf("%w: %s is not traded on binance", exmo.ErrInvalidPair, p)
+	}
+	return si, nil
+}
+
+// Pairs возвращает отсортированный список торгуемых пар в канонической форме.
+func (c *Client) Pairs() ([]string, error) {
+	s, err := c.loadSymbols()
+	if err != nil {
+		return nil, err
+	}
+	return append([]string(nil), s.pairsSorted...), nil
+}
+
+func parseFloat(s string) float64 {
+	v, _ := strconv.ParseFloat(s, 64)
+	return v
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,241 @@
+package binance_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance/binancetest"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+var _ exmo.Exchanger = (*binance.Client)(nil)
+
+func newFake(t *testing.T) *binancetest.FakeBinance {
+	f := binancetest.NewFakeBinance()
+	t.Cleanup(f.Close)
+	f.AddSymbol("BTC", "USDT")
+	f.AddSymbol("ETH", "BTC")
+	return f
+}
+
+func TestClient_Symbols(t *testing.T) {
+	f := newFake(t)
+	// BTCUSD тоже переводится в BTC_USD, но канонический BTC_USD - это BTCUSDT
+	f.AddSymbol("BTC", "USD")
+	client := f.Client()
+
+	pairs, err := client.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC_USD", "ETH_BTC"}, pairs)
+
+	currencies, err := client.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, exmo.Currencies{"BTC": {}, "USD": {}, "ETH": {}}, currencies)
+
+	_, err = client.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.Requests("/api/v3/exchangeInfo"), "exchangeInfo is cached")
+
+	client = f.Client(binance.WithAliases(nil))
+	pairs, err = client.Pairs()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"BTC_USD", "BTC_USDT", "ETH_BTC"}, pairs)
+}
+
+func TestClient_GetTicker(t *testing.T) {
+	f := newFake(t)
+	f.SetTicker("BTCUSDT", binancetest.FakeTicker{Bid: 50000, Ask: 50010, Last: 50005, High: 51000, Low: 49000, Avg: 50200, Volume: 12, QuoteVol: 602400, CloseTimeMillis: 1700000000123})
+	f.SetTicker("DOGEUSDT", binancetest.FakeTicker{Bid: 0.1})
+
+	ticker, err := f.Client().GetTicker()
+	require.NoError(t, err)
+	assert.Equal(t, exmo.Ticker{"BTC_USD": {
+		BuyPrice:  "50000",
+		SellPrice: "50010",
+		LastTrade: "50005",
+		High:      "51000",
+		Low:       "49000",
+		Avg:       "50200",
+		Vol:       "12",
+		VolCurr:   "602400",
+		Updated:   1700000000,
+	}}, ticker, "symbols missing from exchangeInfo are skipped")
+}
+
+func TestClient_GetTrades(t *testing.T) {
+	f := newFake(t)
+	f.AddTrade("BTCUSDT", 50000, 0.5, 1700000000000, false)
+	f.AddTrade("BTCUSDT", 49990, 0.1, 1700000001000, true)
+
+	trades, err := f.Client().GetTrades("btc_usd")
+	require.NoError(t, err)
+	require.Len(t, trades["BTC_USD"], 2)
+	assert.Equal(t, exmo.Pair{TradeID: 2, Date: 1700000001, Type: exmo.Sell, Quantity: "0.1", Price: "49990", Amount: "4999"}, trades["BTC_USD"][0])
+	assert.Equal(t, exmo.Buy, trades["BTC_USD"][1].Type)
+
+	_, err = f.Client().GetTrades("XRP_USD")
+	assert.True(t, errors.Is(err, exmo.ErrInvalidPair), "got %v", err)
+	_, err = f.Client().GetTrades("BTC_USDT")
+	assert.EqualError(t, err, "invalid pair: pair BTC_USDT: USDT is the venue name of USD, use USD")
+	assert.Equal(t, 1, f.Requests("/api/v3/trades"), "unknown pair is rejected locally")
+}
+
+func TestClient_GetOrderBook(t *testing.T) {
+	f := newFake(t)
+	f.SetBook("BTCUSDT",
+		[]exmo.BookLevel{{Price: 100, Quantity: 1}, {Price: 99, Quantity: 2}, {Price: 98, Quantity: 3}},
+		[]exmo.BookLevel{{Price: 101, Quantity: 0.5}, {Price: 102, Quantity: 1}},
+	)
+
+	book, err := f.Client().GetOrderBook(2, "BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, exmo.OrderBookPair{
+		AskQuantity: "1.5",
+		AskAmount:   "152.5",
+		AskTop:      "101",
+		BidQuantity: "3",
+		BidAmount:   "298",
+		BidTop:      "100",
+		Ask:         [][]string{{"101", "0.5", "50.5"}, {"102", "1", "102"}},
+		Bid:         [][]string{{"100", "1", "100"}, {"99", "2", "198"}},
+	}, book["BTC_USD"])
+
+	bids, err := book["BTC_USD"].Bids()
+	require.NoError(t, err)
+	assert.Equal(t, []exmo.BookLevel{{Price: 100, Quantity: 1}, {Price: 99, Quantity: 2}}, bids)
+}
+
+func TestClient_GetCandlesHistory(t *testing.T) {
+	f := newFake(t)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]exmo.Candle, 1500)
+	for i := range candles {
+		candles[i] = exmo.Candle{T: start.Add(time.Duration(i) * time.Minute).UnixMilli(), O: 1, H: 2, L: 0.5, C: float64(i), V: 10}
+	}
+	f.SetKlines("BTCUSDT", candles)
+	client := f.Client()
+
+	history, err := client.GetCandlesHistory("BTC_USD", 1, start, start.Add(1499*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, candles, history.Candles)
+	assert.Equal(t, 2, f.Requests("/api/v3/klines"), "history is paginated")
+
+	closes, err := client.GetClosePrice("BTC_USD", 1, start, start.Add(2*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{0, 1, 2}, closes)
+
+	_, err = client.GetCandlesHistory("BTC_USD", 7, start, start)
+	assert.ErrorContains(t, err, "unsupported resolution")
+}
+
+func TestClient_GetPairSettings(t *testing.T) {
+	settings, err := newFake(t).Client().GetPairSettings()
+	require.NoError(t, err)
+	require.Contains(t, settings, "BTC_USD")
+	ps := settings["BTC_USD"]
+	assert.Equal(t, exmo.PairSetting{
+		MinQuantity:    0.0001,
+		MaxQuantity:    9000,
+		MinPrice:       0.01,
+		MaxPrice:       1000000,
+		MinAmount:      5,
+		MaxAmount:      9000000,
+		PricePrecision: 2,
+	}, ps)
+	assert.Equal(t, 0.0001, ps.QuantityStep())
+}
+
+func TestAPIError_Is(t *testing.T) {
+	f := newFake(t)
+	client := f.Client()
+
+	f.Fail("/api/v3/depth", http.StatusTooManyRequests, -1003, "Too many requests")
+	_, err := client.GetOrderBook(10, "BTC_USD")
+	var apiErr *binance.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, -1003, apiErr.Code)
+	assert.True(t, errors.Is(err, exmo.ErrRateLimited))
+	assert.False(t, errors.Is(err, exmo.ErrInvalidPair))
+	assert.EqualError(t, err, "binance /api/v3/depth: error -1003: Too many requests (status 429)")
+
+	f.Fail("/api/v3/depth", http.StatusBadRequest, -1121, "Invalid symbol.")
+	_, err = client.GetOrderBook(10, "BTC_USD")
+	assert.True(t, errors.Is(err, exmo.ErrInvalidPair))
+
+	f.Fail("/api/v3/depth", http.StatusUnauthorized, -2015, "Invalid API-key, IP, or permissions for action.")
+	_, err = client.GetOrderBook(10, "BTC_USD")
+	assert.True(t, errors.Is(err, exmo.ErrAuth))
+
+	f.ClearFailures()
+	f.Fail("/api/v3/exchangeInfo", http.StatusBadGateway, 0, "")
+	_, err = f.Client().GetTicker()
+	assert.EqualError(t, err, "binance /api/v3/exchangeInfo: server returned non-200 status 502")
+}
+
+func TestClient_Retry(t *testing.T) {
+	f := newFake(t)
+	var sleeps []time.Duration
+	client := f.Client(
+		binance.WithRetry(exmo.RetryPolicy{MaxAttempts: 3, Backoff: 100 * time.Millisecond}),
+		binance.WithSleep(func(d time.Duration) { sleeps = append(sleeps, d) }),
+	)
+
+	f.Fail("/api/v3/depth", http.StatusBadGateway, 0, "")
+	_, err := client.GetOrderBook(10, "BTC_USD")
+	assert.EqualError(t, err, "binance /api/v3/depth: server returned non-200 status 502")
+	assert.Equal(t, 3, f.Requests("/api/v3/depth"))
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, sleeps)
+
+	f.Fail("/api/v3/depth", http.StatusTeapot, -1003, "Way too many requests; IP banned.")
+	_, err = client.GetOrderBook(10, "BTC_USD")
+	assert.True(t, errors.Is(err, exmo.ErrRateLimited))
+	assert.Equal(t, 6, f.Requests("/api/v3/depth"), "418 is retried")
+
+	f.Fail("/api/v3/depth", http.StatusBadRequest, -1121, "Invalid symbol.")
+	_, err = client.GetOrderBook(10, "BTC_USD")
+	assert.True(t, errors.Is(err, exmo.ErrInvalidPair))
+	assert.Equal(t, 7, f.Requests("/api/v3/depth"), "client errors are not retried")
+}
+
+func TestClient_Metrics(t *testing.T) {
+	f := newFake(t)
+	f.SetBook("BTCUSDT", []exmo.BookLevel{{Price: 100, Quantity: 1}}, nil)
+
+	m := telemetry.NewMetrics()
+	var waited time.Duration
+	client := f.Client(
+		binance.WithMetrics(m),
+		binance.WithRetry(exmo.RetryPolicy{MaxAttempts: 2}),
+		binance.WithRateLimit(60, 1),
+		binance.WithSleep(func(d time.Duration) { waited += d }),
+	)
+	f.Fail("/api/v3/depth", http.StatusServiceUnavailable, 0, "")
+	_, err := client.GetOrderBook(10, "BTC_USD")
+	require.Error(t, err)
+	f.ClearFailures()
+	_, err = client.GetOrderBook(10, "BTC_USD")
+	require.NoError(t, err)
+
+	var b strings.Builder
+	require.NoError(t, m.WritePrometheus(&b))
+	out := b.String()
+	for _, line := range []string{
+		`exmo_requests_total{endpoint="/api/v3/exchangeInfo",code="2xx"} 1`,
+		`exmo_requests_total{endpoint="/api/v3/depth",code="5xx"} 2`,
+		`exmo_requests_total{endpoint="/api/v3/depth",code="2xx"} 1`,
+		`exmo_retries_total{endpoint="/api/v3/depth"} 1`,
+		// Первый запрос занимает единственный маркер, остальные ждут
+		`exmo_rate_limit_waits_total 3`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+	assert.Positive(t, waited)
+}
@@ -0,0 +1,18 @@
+// Package binance - адаптер публичного REST API Binance Spot к интерфейсу
+// exmo.Exchanger.
+//
+// Клиент принимает и возвращает пары в канонической форме BTC_USD (см. пакет
+// symbol), сам переводя их в символы Binance. По умолчанию каноническому USD
+// соответствует USDT, поэтому стратегии, настроенные на BTC_USD для Exmo,
+// работают с Binance без изменений:
+//
+//	client := binance.NewClient(binance.WithTimeout(10 * time.Second))
+//	book, err := client.GetOrderBook(20, "BTC_USD") // стакан BTCUSDT
+//
+// Повторы, ограничение частоты и метрики подключаются опциями WithRetry,
+// WithRateLimit и WithMetrics с теми же типами, что у клиента Exmo.
+//
+// Ошибки Binance проверяются через errors.Is с exmo.ErrRateLimited,
+// exmo.ErrInvalidPair и exmo.ErrAuth. Приватное API (ордера, балансы)
+// адаптер не поддерживает.
+package binance
@@ -0,0 +1,11 @@
+package binance
+
+import "time"
+
+// WithSleep подменяет ожидание между попытками и в ограничителе запросов
+// для тестов пакета binance_test.
+func WithSleep(sleep func(time.Duration)) Option {
+	return func(c *Client) {
+		c.sleep = sleep
+	}
+}
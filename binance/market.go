@@ -0,0 +1,299 @@
+package binance
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// Сделок на пару в GetTrades, как у /trades Exmo.
+const tradesLimit = 100
+
+// Допустимые значения limit у /api/v3/depth.
+var depthLimits = []int{5, 10, 20, 50, 100, 500, 1000, 5000}
+
+// Интервалы свечей Binance по разрешению в минутах.
+var intervals = map[int]string{
+	1:         "1m",
+	3:         "3m",
+	5:         "5m",
+	15:        "15m",
+	30:        "30m",
+	60:        "1h",
+	120:       "2h",
+	240:       "4h",
+	360:       "6h",
+	480:       "8h",
+	720:       "12h",
+	24 * 60:   "1d",
+	3 * 1440:  "3d",
+	7 * 1440:  "1w",
+	30 * 1440: "1M",
+}
+
+// Свечей в одном ответе /api/v3/klines.
+const klinesLimit = 1000
+
+func (c *Client) GetTicker() (exmo.Ticker, error) {
+	s, err := c.loadSymbols()
+	if err != nil {
+		return nil, err
+	}
+	var raw []struct {
+		Symbol           string `json:"symbol"`
+		WeightedAvgPrice string `json:"weightedAvgPrice"`
+		LastPrice        string `json:"lastPrice"`
+		BidPrice         string `json:"bidPrice"`
+		AskPrice         string `json:"askPrice"`
+		HighPrice        string `json:"highPrice"`
+		LowPrice         string `json:"lowPrice"`
+		Volume           string `json:"volume"`
+		QuoteVolume      string `json:"quoteVolume"`
+		CloseTime        int64  `json:"closeTime"`
+	}
+	if err := c.get("/api/v3/ticker/24hr", nil, &raw); err != nil {
+		return nil, err
+	}
+	ticker := make(exmo.Ticker, len(raw))
+	for _, t := range raw {
+		pair, ok := s.bySymbol[t.Symbol]
+		if !ok {
+			continue
+		}
+		ticker[pair] = exmo.TickerValue{
+			BuyPrice:  t.BidPrice,
+			SellPrice: t.AskPrice,
+			LastTrade: t.LastPrice,
+			High:      t.HighPrice,
+			Low:       t.LowPrice,
+			Avg:       t.WeightedAvgPrice,
+			Vol:       t.Volume,
+			VolCurr:   t.QuoteVolume,
+			Updated:   t.CloseTime / 1000,
+		}
+	}
+	return ticker, nil
+}
+
+// GetTrades возвращает последние сделки, новые первыми, как Exmo. Binance
+// отдает сделки по одной паре, поэтому на каждую пару уходит свой запрос.
+func (c *Client) GetTrades(pairs ...string) (exmo.Trades, error) {
+	trades := make(exmo.Trades, len(pairs))
+	for _, pair := range pairs {
+		si, err := c.lookup(pair)
+		if err != nil {
+			return nil, err
+		}
+		var raw []struct {
+			ID           int64  `json:"id"`
+			Price        string `json:"price"`
+			Qty          string `json:"qty"`
+			QuoteQty     string `json:"quoteQty"`
+			Time         int64  `json:"time"`
+			IsBuyerMaker bool   `json:"isBuyerMaker"`
+		}
+		query := url.Values{"symbol": {si.Symbol}, "limit": {strconv.Itoa(tradesLimit)}}
+		if err := c.get("/api/v3/trades", query, &raw); err != nil {
+			return nil, err
+		}
+		list := make([]exmo.Pair, len(raw))
+		for i, t := range raw {
+			// Покупатель-мейкер означает, что сделку инициировал продавец
+			side := exmo.Buy
+			if t.IsBuyerMaker {
+				side = exmo.Sell
+			}
+			list[len(raw)-1-i] = exmo.Pair{
+				TradeID:  t.ID,
+				Date:     t.Time / 1000,
+				Type:     side,
+				Quantity: t.Qty,
+				Price:    t.Price,
+				Amount:   t.QuoteQty,
+			}
+		}
+		trades[si.pair] = list
+	}
+	return trades, nil
+}
+
+// GetOrderBook возвращает стаканы глубиной limit. Уровни дополняются суммой,
+// как у Exmo: [цена, количество, сумма].
+func (c *Client) GetOrderBook(limit int, pairs ...string) (exmo.OrderBook, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	depth := depthLimits[len(depthLimits)-1]
+	for _, l := range depthLimits {
+		if l >= limit {
+			depth = l
+			break
+		}
+	}
+
+	book := make(exmo.OrderBook, len(pairs))
+	for _, pair := range pairs {
+		si, err := c.lookup(pair)
+		if err != nil {
+			return nil, err
+		}
+		var raw struct {
+			Bids [][]string `json:"bids"`
+			Asks [][]string `json:"asks"`
+		}
+		query := url.Values{"symbol": {si.Symbol}, "limit": {strconv.Itoa(depth)}}
+		if err := c.get("/api/v3/depth", query, &raw); err != nil {
+			return nil, err
+		}
+		var v exmo.OrderBookPair
+		v.Ask, v.AskQuantity, v.AskAmount, v.AskTop = bookSide(raw.Asks, limit)
+		v.Bid, v.BidQuantity, v.BidAmount, v.BidTop = bookSide(raw.Bids, limit)
+		book[si.pair] = v
+	}
+	return book, nil
+}
+
+// bookSide переводит уровни [цена, количество] в формат Exmo и считает итоги.
+func bookSide(raw [][]string, limit int) (levels [][]string, quantity, amount, top string) {
+	if len(raw) > limit {
+		raw = raw[:limit]
+	}
+	var qtySum, amountSum float64
+	levels = make([][]string, 0, len(raw))
+	for _, l := range raw {
+		if len(l) < 2 {
+			continue
+		}
+		price, qty := parseFloat(l[0]), parseFloat(l[1])
+		qtySum += qty
+		amountSum += price * qty
+		levels = append(levels, []string{l[0], l[1], formatFloat(price * qty)})
+	}
+	if len(levels) > 0 {
+		top = levels[0][0]
+	}
+	return levels, formatFloat(qtySum), formatFloat(amountSum), top
+}
+
+func (c *Client) GetCurrencies() (exmo.Currencies, error) {
+	s, err := c.loadSymbols()
+	if err != nil {
+		return nil, err
+	}
+	currencies := make(exmo.Currencies, len(s.currencies))
+	for cur := range s.currencies {
+		currencies[cur] = struct{}{}
+	}
+	return currencies, nil
+}
+
+// GetCandlesHistory загружает свечи с временем открытия в [start, end],
+// при необходимости несколькими запросами.
+func (c *Client) GetCandlesHistory(pair string, resolution int, start, end time.Time) (exmo.CandlesHistory, error) {
+	interval, ok := intervals[resolution]
+	if !ok {
+		return exmo.CandlesHistory{}, fmt.Errorf("binance: unsupported resolution %d minutes", resolution)
+	}
+	si, err := c.lookup(pair)
+	if err != nil {
+		return exmo.CandlesHistory{}, err
+	}
+
+	var history exmo.CandlesHistory
+	from, to := start.UnixMilli(), end.UnixMilli()
+	for from <= to {
+		var rows [][]interface{}
+		query := url.Values{
+			"symbol":    {si.Symbol},
+			"interval":  {interval},
+			"startTime": {strconv.FormatInt(from, 10)},
+			"endTime":   {strconv.FormatInt(to, 10)},
+			"limit":     {strconv.Itoa(klinesLimit)},
+		}
+		if err := c.get("/api/v3/klines", query, &rows); err != nil {
+			return exmo.CandlesHistory{}, err
+		}
+		for _, row := range rows {
+			candle, err := parseKline(row)
+			if err != nil {
+				return exmo.CandlesHistory{}, err
+			}
+			history.Candles = append(history.Candles, candle)
+		}
+		if len(rows) < klinesLimit {
+			break
+		}
+		from = history.Candles[len(history.Candles)-1].T + 1
+	}
+	return history, nil
+}
+
+// parseKline разбирает строку [openTime, "open", "high", "low", "close", "volume", ...].
+func parseKline(row []interface{}) (exmo.Candle, error) {
+	if len(row) < 6 {
+		return exmo.Candle{}, fmt.Errorf("binance: kline has %d fields, want at least 6", len(row))
+	}
+	openTime, ok := row[0].(float64)
+	if !ok {
+		return exmo.Candle{}, fmt.Errorf("binance: kline open time %v is not a number", row[0])
+	}
+	var values [5]float64
+	for i := range values {
+		s, ok := row[i+1].(string)
+		if !ok {
+			return exmo.Candle{}, fmt.Errorf("binance: kline field %d is %T, want string", i+1, row[i+1])
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return exmo.Candle{}, fmt.Errorf("binance: kline field %d: %w", i+1, err)
+		}
+		values[i] = v
+	}
+	return exmo.Candle{T: int64(openTime), O: values[0], H: values[1], L: values[2], C: values[3], V: values[4]}, nil
+}
+
+func (c *Client) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	history, err := c.GetCandlesHistory(pair, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return history.ClosePrices(), nil
+}
+
+// GetPairSettings переводит фильтры PRICE_FILTER, LOT_SIZE и NOTIONAL в
+// ограничения пар. Комиссии публичный API не отдает, они остаются нулевыми.
+func (c *Client) GetPairSettings() (exmo.PairSettings, error) {
+	s, err := c.loadSymbols()
+	if err != nil {
+		return nil, err
+	}
+	settings := make(exmo.PairSettings, len(s.byPair))
+	for pair, si := range s.byPair {
+		var ps exmo.PairSetting
+		for _, f := range si.Filters {
+			switch f.FilterType {
+			case "PRICE_FILTER":
+				ps.MinPrice, ps.MaxPrice = parseFloat(f.MinPrice), parseFloat(f.MaxPrice)
+				ps.PricePrecision = precision(parseFloat(f.TickSize))
+			case "LOT_SIZE":
+				ps.MinQuantity, ps.MaxQuantity = parseFloat(f.MinQty), parseFloat(f.MaxQty)
+			case "NOTIONAL", "MIN_NOTIONAL":
+				ps.MinAmount, ps.MaxAmount = parseFloat(f.MinNotional), parseFloat(f.MaxNotional)
+			}
+		}
+		settings[pair] = ps
+	}
+	return settings, nil
+}
+
+// precision возвращает число знаков шага цены: 0.01 - 2.
+func precision(tick float64) int {
+	if tick <= 0 || tick >= 1 {
+		return 0
+	}
+	return int(math.Round(-math.Log10(tick)))
+}
@@ -0,0 +1,387 @@
+// Package candlestore - локальное файловое хранилище свечей и
+// StoreExchanger, который отдает свечи из хранилища и догружает
+// недостающие интервалы с биржи.
+package candlestore
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/fsutil"
+)
+
+// candleRecordSize - размер записи в файле свечей: время и пять float64.
+const candleRecordSize = 8 * 6
+
+// maxCandlesPerRequest ограничивает число свечей в одном запросе при синхронизации.
+const maxCandlesPerRequest = 1000
+
+// TimeRange - закрытый интервал времени [From, To].
+type TimeRange struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// CandleStore - локальное хранилище свечей без внешнего сервера.
+// Для каждой пары и разрешения хранятся два файла:
+// <pair>/<resolution>.candles - отсортированные по времени записи фиксированной
+// длины, и <pair>/<resolution>.ranges - уже синхронизированные интервалы.
+type CandleStore struct {
+	dir string
+	mu  sync.RWMutex
+	now func() time.Time
+}
+
+// NewCandleStore создает хранилище в каталоге dir. Каталог создается при первой записи.
+func NewCandleStore(dir string) *CandleStore {
+	return &CandleStore{dir: dir, now: time.Now}
+}
+
+// Dir возвращает каталог хранилища.
+func (s *CandleStore) Dir() string {
+	return s.dir
+}
+
+func (s *CandleStore) candlesPath(pair string, resolution int) (string, error) {
+	return s.pairPath(pair, strconv.Itoa(resolution)+".candles")
+}
+
+func (s *CandleStore) rangesPath(pair string, resolution int) (string, error) {
+	return s.pairPath(pair, strconv.Itoa(resolution)+".ranges")
+}
+
+// pairPath возвращает путь файла в каталоге пары. Пара становится именем
+// каталога, поэтому разделители путей и ".." в ней запрещены: иначе файлы
+// читались бы и писались за пределами хранилища.
+func (s *CandleStore) pairPath(pair, name string) (string, error) {
+	if pair == "" || strings.ContainsAny(pair, `/\`) || strings.Contains(pair, "..") {
+		return "", fmt.Errorf("%w: %q", exmo.ErrInvalidPair, pair)
+	}
+	return filepath.Join(s.dir, pair, name), nil
+}
+
+// Range возвращает свечи, открытые в интервале [from, to].
+func (s *CandleStore) Range(pair string, resolution int, from, to time.Time) ([]exmo.Candle, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	path, err := s.candlesPath(pair, resolution)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	n := int(info.Size() / candleRecordSize)
+
+	// Бинарный поиск первой записи не раньше from
+	var readErr error
+	buf := make([]byte, candleRecordSize)
+	timeAt := func(i int) int64 {
+		if _, err := f.ReadAt(buf[:8], int64(i)*candleRecordSize); err != nil {
+			readErr = err
+			return math.MaxInt64
+		}
+		return int64(binary.LittleEndian.Uint64(buf[:8]))
+	}
+	fromMs, toMs := from.UnixMilli(), to.UnixMilli()
+	first := sort.Search(n, func(i int) bool { return timeAt(i) >= fromMs })
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	var result []exmo.Candle
+	for i := first; i < n; i++ {
+		if _, err := f.ReadAt(buf, int64(i)*candleRecordSize); err != nil {
+			return nil, err
+		}
+		c := decodeCandle(buf)
+		if c.T > toMs {
+			break
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// Put добавляет свечи, заменяя записи с тем же временем. Файл
+// переписывается целиком через временный файл.
+func (s *CandleStore) Put(pair string, resolution int, candles []exmo.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	path, err := s.candlesPath(pair, resolution)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, err := s.readAllLocked(pair, resolution)
+	if err != nil {
+		return err
+	}
+	byTime := make(map[int64]exmo.Candle, len(existing)+len(candles))
+	for _, c := range existing {
+		byTime[c.T] = c
+	}
+	for _, c := range candles {
+		byTime[c.T] = c
+	}
+	merged := make([]exmo.Candle, 0, len(byTime))
+	for _, c := range byTime {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].T < merged[j].T })
+
+	data := make([]byte, len(merged)*candleRecordSize)
+	for i, c := range merged {
+		encodeCandle(data[i*candleRecordSize:], c)
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+// Covered возвращает уже синхронизированные интервалы по возрастанию.
+func (s *CandleStore) Covered(pair string, resolution int) ([]TimeRange, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.coveredLocked(pair, resolution)
+}
+
+// MarkCovered отмечает интервал как синхронизированный и объединяет его с соседними.
+func (s *CandleStore) MarkCovered(pair string, resolution int, r TimeRange) error {
+	path, err := s.rangesPath(pair, resolution)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ranges, err := s.coveredLocked(pair, resolution)
+	if err != nil {
+		return err
+	}
+	step := time.Duration(resolution) * time.Minute
+	ranges = mergeRanges(append(ranges, r), step)
+
+	data, err := json.Marshal(ranges)
+	if err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+// Missing возвращает части интервала [from, to], которые еще не синхронизированы.
+func (s *CandleStore) Missing(pair string, resolution int, from, to time.Time) ([]TimeRange, error) {
+	covered, err := s.Covered(pair, resolution)
+	if err != nil {
+		return nil, err
+	}
+	step := time.Duration(resolution) * time.Minute
+	cursor := from
+	var missing []TimeRange
+	for _, r := range covered {
+		if r.To.Before(cursor) {
+			continue
+		}
+		if r.From.After(to) {
+			break
+		}
+		if r.From.After(cursor) {
+			missing = append(missing, TimeRange{From: cursor, To: r.From.Add(-step).In(from.Location())})
+		}
+		cursor = r.To.Add(step).In(from.Location())
+	}
+	if !cursor.After(to) {
+		missing = append(missing, TimeRange{From: cursor, To: to})
+	}
+	return missing, nil
+}
+
+// SyncResult - итог синхронизации.
+type SyncResult struct {
+	Requests int         `json:"requests"`
+	Candles  int         `json:"candles"`
+	Fetched  []TimeRange `json:"fetched"`
+}
+
+// Sync загружает через GetCandlesHistory только недостающие части интервала.
+// Интервал выравнивается по разрешению, а незакрытая текущая свеча не
+// отмечается как синхронизированная, чтобы позже загрузиться заново.
+func (s *CandleStore) Sync(ex exmo.Exchanger, pair string, resolution int, from, to time.Time) (SyncResult, error) {
+	if resolution <= 0 {
+		return SyncResult{}, fmt.Errorf("resolution must be positive, got %d", resolution)
+	}
+	step := time.Duration(resolution) * time.Minute
+	from, to = from.Truncate(step), to.Truncate(step)
+	if to.Before(from) {
+		return SyncResult{}, fmt.Errorf("invalid range: %s is after %s", from, to)
+	}
+	lastClosed := s.now().Truncate(step).Add(-step)
+
+	missing, err := s.Missing(pair, resolution, from, to)
+	if err != nil {
+		return SyncResult{}, err
+	}
+
+	var result SyncResult
+	chunk := step * maxCandlesPerRequest
+	for _, gap := range missing {
+		for start := gap.From; !start.After(gap.To); start = start.Add(chunk) {
+			end := start.Add(chunk - step)
+			if end.After(gap.To) {
+				end = gap.To
+			}
+			history, err := ex.GetCandlesHistory(pair, resolution, start, end)
+			if err != nil {
+				return result, err
+			}
+			result.Requests++
+			result.Candles += len(history.Candles)
+			if err := s.Put(pair, resolution, history.Candles); err != nil {
+				return result, err
+			}
+
+			closedEnd := end
+			if closedEnd.After(lastClosed) {
+				closedEnd = lastClosed
+			}
+			if !closedEnd.Before(start) {
+				if err := s.MarkCovered(pair, resolution, TimeRange{From: start, To: closedEnd}); err != nil {
+					return result, err
+				}
+			}
+			result.Fetched = append(result.Fetched, TimeRange{From: start, To: end})
+		}
+	}
+	return result, nil
+}
+
+func (s *CandleStore) readAllLocked(pair string, resolution int) ([]exmo.Candle, error) {
+	path, err := s.candlesPath(pair, resolution)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data)%candleRecordSize != 0 {
+		return nil, fmt.Errorf("corrupted candle file for %s/%d: size %d", pair, resolution, len(data))
+	}
+	candles := make([]exmo.Candle, len(data)/candleRecordSize)
+	for i := range candles {
+		candles[i] = decodeCandle(data[i*candleRecordSize:])
+	}
+	return candles, nil
+}
+
+func (s *CandleStore) coveredLocked(pair string, resolution int) ([]TimeRange, error) {
+	path, err := s.rangesPath(pair, resolution)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var ranges []TimeRange
+	if err := json.Unmarshal(data, &ranges); err != nil {
+		return nil, fmt.Errorf("corrupted ranges file for %s/%d: %w", pair, resolution, err)
+	}
+	return ranges, nil
+}
+
+// mergeRanges сортирует интервалы и объединяет пересекающиеся и соседние.
+func mergeRanges(ranges []TimeRange, step time.Duration) []TimeRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].From.Before(ranges[j].From) })
+	var merged []TimeRange
+	for _, r := range ranges {
+		if n := len(merged); n > 0 && !r.From.After(merged[n-1].To.Add(step)) {
+			if r.To.After(merged[n-1].To) {
+				merged[n-1].To = r.To
+			}
+			continue
+		}
+		merged = append(merged, TimeRange{From: r.From.UTC(), To: r.To.UTC()})
+	}
+	return merged
+}
+
+func encodeCandle(buf []byte, c exmo.Candle) {
+	binary.LittleEndian.PutUint64(buf[0:], uint64(c.T))
+	for i, v := range []float64{c.O, c.H, c.L, c.C, c.V} {
+		binary.LittleEndian.PutUint64(buf[8+i*8:], math.Float64bits(v))
+	}
+}
+
+func decodeCandle(buf []byte) exmo.Candle {
+	f := func(i int) float64 {
+		return math.Float64frombits(binary.LittleEndian.Uint64(buf[8+i*8:]))
+	}
+	return exmo.Candle{
+		T: int64(binary.LittleEndian.Uint64(buf[0:])),
+		O: f(0),
+		H: f(1),
+		L: f(2),
+		C: f(3),
+		V: f(4),
+	}
+}
+
+// StoreExchanger читает свечи из локального хранилища и догружает
+// недостающие интервалы через вложенный Exchanger.
+type StoreExchanger struct {
+	exmo.Exchanger
+	store *CandleStore
+}
+
+func NewStoreExchanger(next exmo.Exchanger, store *CandleStore) *StoreExchanger {
+	return &StoreExchanger{Exchanger: next, store: store}
+}
+
+func (s *StoreExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (exmo.CandlesHistory, error) {
+	if _, err := s.store.Sync(s.Exchanger, pair, period, start, end); err != nil {
+		return exmo.CandlesHistory{}, err
+	}
+	candles, err := s.store.Range(pair, period, start, end)
+	if err != nil {
+		return exmo.CandlesHistory{}, err
+	}
+	return exmo.CandlesHistory{Candles: candles}, nil
+}
+
+func (s *StoreExchanger) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	history, err := s.GetCandlesHistory(pair, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return history.ClosePrices(), nil
+}
@@ -0,0 +1,184 @@
+package candlestore
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+// candleSeries возвращает по свече на каждые step минут в интервале [from, to].
+func candleSeries(from, to time.Time, step int) []exmo.Candle {
+	var candles []exmo.Candle
+	for t := from; !t.After(to); t = t.Add(time.Duration(step) * time.Minute) {
+		candles = append(candles, exmo.Candle{T: t.UnixMilli(), C: float64(t.Unix())})
+	}
+	return candles
+}
+
+func TestCandleStore_PutRange(t *testing.T) {
+	store := NewCandleStore(t.TempDir())
+	base := time.Unix(1700000000, 0).Truncate(time.Hour)
+
+	candles, err := store.Range("BTC_USD", 60, base, base.Add(time.Hour))
+	require.NoError(t, err)
+	assert.Empty(t, candles)
+
+	require.NoError(t, store.Put("BTC_USD", 60, candleSeries(base.Add(2*time.Hour), base.Add(4*time.Hour), 60)))
+	require.NoError(t, store.Put("BTC_USD", 60, candleSeries(base, base.Add(2*time.Hour), 60)))
+
+	candles, err = store.Range("BTC_USD", 60, base.Add(time.Hour), base.Add(3*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 3)
+	assert.Equal(t, base.Add(time.Hour).UnixMilli(), candles[0].T)
+	assert.Equal(t, float64(base.Add(3*time.Hour).Unix()), candles[2].C)
+
+	all, err := store.Range("BTC_USD", 60, base, base.Add(10*time.Hour))
+	require.NoError(t, err)
+	assert.Len(t, all, 5)
+}
+
+func TestCandleStore_InvalidPair(t *testing.T) {
+	root := t.TempDir()
+	store := NewCandleStore(filepath.Join(root, "store"))
+	base := time.Unix(1700000000, 0).Truncate(time.Hour)
+
+	for _, pair := range []string{"", "../../tmp/evil_x", "BTC/USD", `BTC\USD`, ".."} {
+		_, err := store.Range(pair, 60, base, base)
+		assert.ErrorIs(t, err, exmo.ErrInvalidPair, pair)
+		err = store.Put(pair, 60, candleSeries(base, base, 60))
+		assert.ErrorIs(t, err, exmo.ErrInvalidPair, pair)
+		err = store.MarkCovered(pair, 60, TimeRange{From: base, To: base})
+		assert.ErrorIs(t, err, exmo.ErrInvalidPair, pair)
+	}
+
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "nothing is written outside the store")
+}
+
+func TestCandleStore_Missing(t *testing.T) {
+	store := NewCandleStore(t.TempDir())
+	base := time.Unix(1700000000, 0).Truncate(time.Hour)
+	at := func(h int) time.Time { return base.Add(time.Duration(h) * time.Hour) }
+
+	require.NoError(t, store.MarkCovered("BTC_USD", 60, TimeRange{From: at(2), To: at(4)}))
+	require.NoError(t, store.MarkCovered("BTC_USD", 60, TimeRange{From: at(8), To: at(9)}))
+	// Соседний интервал склеивается с уже покрытым
+	require.NoError(t, store.MarkCovered("BTC_USD", 60, TimeRange{From: at(5), To: at(6)}))
+
+	covered, err := store.Covered("BTC_USD", 60)
+	require.NoError(t, err)
+	require.Len(t, covered, 2)
+	assert.True(t, covered[0].To.Equal(at(6)))
+
+	missing, err := store.Missing("BTC_USD", 60, at(0), at(10))
+	require.NoError(t, err)
+	require.Len(t, missing, 3)
+	assert.True(t, missing[0].From.Equal(at(0)) && missing[0].To.Equal(at(1)))
+	assert.True(t, missing[1].From.Equal(at(7)) && missing[1].To.Equal(at(7)))
+	assert.True(t, missing[2].From.Equal(at(10)) && missing[2].To.Equal(at(10)))
+
+	missing, err = store.Missing("BTC_USD", 60, at(3), at(5))
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestCandleStore_Sync(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewCandleStore(t.TempDir())
+	now := time.Unix(1700000000, 0).Truncate(time.Hour).Add(30 * time.Minute)
+	store.now = func() time.Time { return now }
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+
+	from, to := now.Add(-5*time.Hour).Truncate(time.Hour), now.Add(-2*time.Hour).Truncate(time.Hour)
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 60, from, to).
+		Return(exmo.CandlesHistory{Candles: candleSeries(from, to, 60)}, nil)
+
+	result, err := store.Sync(mockExchanger, "BTC_USD", 60, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Requests)
+	assert.Equal(t, 4, result.Candles)
+
+	// Повторная синхронизация того же интервала не ходит в сеть,
+	// расширенная - запрашивает только новый хвост
+	result, err = store.Sync(mockExchanger, "BTC_USD", 60, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.Requests)
+
+	tail := now.Truncate(time.Hour)
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 60, to.Add(time.Hour), tail).
+		Return(exmo.CandlesHistory{Candles: candleSeries(to.Add(time.Hour), tail, 60)}, nil)
+	result, err = store.Sync(mockExchanger, "BTC_USD", 60, from, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Requests)
+
+	// Текущая свеча еще не закрыта и не считается синхронизированной
+	missing, err := store.Missing("BTC_USD", 60, from, tail)
+	require.NoError(t, err)
+	require.Len(t, missing, 1)
+	assert.True(t, missing[0].From.Equal(tail))
+
+	_, err = store.Sync(mockExchanger, "BTC_USD", 0, from, to)
+	assert.Error(t, err)
+}
+
+func TestCandleStore_SyncChunks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := NewCandleStore(t.TempDir())
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+
+	from := time.Unix(1600000000, 0).Truncate(time.Minute)
+	to := from.Add((maxCandlesPerRequest*2 + 10) * time.Minute)
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 1, gomock.Any(), gomock.Any()).
+		Return(exmo.CandlesHistory{}, nil).Times(3)
+
+	result, err := store.Sync(mockExchanger, "BTC_USD", 1, from, to)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Requests)
+	require.Len(t, result.Fetched, 3)
+	assert.True(t, result.Fetched[2].To.Equal(to))
+
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 1, gomock.Any(), gomock.Any()).
+		Return(exmo.CandlesHistory{}, errors.New("exchange error"))
+	_, err = store.Sync(mockExchanger, "BTC_USD", 1, to, to.Add(time.Hour))
+	assert.Error(t, err)
+}
+
+func TestStoreExchanger(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	ex := NewStoreExchanger(mockExchanger, NewCandleStore(t.TempDir()))
+
+	from := time.Unix(1600000000, 0).Truncate(30 * time.Minute)
+	to := from.Add(2 * time.Hour)
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 30, from, to).
+		Return(exmo.CandlesHistory{Candles: candleSeries(from, to, 30)}, nil).Times(1)
+
+	prices, err := ex.GetClosePrice("BTC_USD", 30, from, to)
+	require.NoError(t, err)
+	assert.Len(t, prices, 5)
+
+	// Второй запрос обслуживается из хранилища
+	history, err := ex.GetCandlesHistory("BTC_USD", 30, from.Add(time.Hour), to)
+	require.NoError(t, err)
+	assert.Len(t, history.Candles, 3)
+
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{}, nil)
+	_, err = ex.GetTicker()
+	assert.NoError(t, err)
+}
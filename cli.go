@@ -0,0 +1,885 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"golang.org/x/term"
+	"google.golang.org/grpc"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/candlestore"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/fsutil"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/timearg"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/server"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// Коды выхода CLI.
+const (
+	exitOK    = 0
+	exitError = 1 // ошибка запроса к бирже
+	exitUsage = 2 // неверные аргументы
+)
+
+// errUsage помечает ошибки аргументов, для которых печатается справка.
+var errUsage = errors.New("usage error")
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdout io.Writer, stderr io.Writer) error
+}
+
+func commands() []command {
+	return []command{
+		{"ticker", "текущие цены по парам", runTicker},
+		{"trades", "последние сделки по парам", runTrades},
+		{"orderbook", "стакан заявок по парам", runOrderBook},
+		{"currencies", "список валют биржи", runCurrencies},
+		{"candles", "свечи за интервал", runCandles},
+		{"indicator", "индикатор по ценам закрытия: indicator <sma|ema>", runIndicator},
+		{"sync", "догрузить свечи в локальное хранилище", runSync},
+		{"watch", "живая сводка по рынку в терминале", runWatch},
+		{"alert", "оповещения по правилам из настроек и флагов -rule", runAlert},
+		{"serve", "HTTP API с рыночными данными и индикаторами", runServe},
+	}
+}
+
+// globalConfig - настройки текущего запуска, из них берутся значения флагов по умолчанию.
+var globalConfig = DefaultConfig()
+
+// run выполняет команду CLI и возвращает код выхода. Перед командой можно
+// указать -config и -profile; без -config используется EXMO_CONFIG, а без
+// файла - настройки по умолчанию с переопределениями из окружения.
+func run(args []string, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("exmo", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { printUsage(stderr) }
+	configPath := global.String("config", os.Getenv("EXMO_CONFIG"), "файл настроек YAML")
+	profile := global.String("profile", "", "профиль из файла настроек")
+	otlpEndpoint := global.String("otlp", os.Getenv("EXMO_OTLP_ENDPOINT"), "адрес OTLP/HTTP коллектора для спанов, например "+telemetry.DefaultOTLPEndpoint)
+	metricsAddr := global.String("metrics", os.Getenv("EXMO_METRICS_ADDR"), "адрес для метрик Prometheus на /metrics, например :9100")
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	args = global.Args()
+
+	if *configPath != "" {
+		cfg, err := LoadConfig(*configPath, *profile, os.Getenv)
+		if err != nil {
+			fmt.Fprintf(stderr, "config: %v\n", err)
+			return exitUsage
+		}
+		prevConfig, prevExchanger := globalConfig, globalExchanger
+		globalConfig, globalExchanger = cfg, cfg.NewExchanger(os.Getenv)
+		defer func() { globalConfig, globalExchanger = prevConfig, prevExchanger }()
+	} else if *profile != "" {
+		fmt.Fprintln(stderr, "config: -profile requires -config or EXMO_CONFIG")
+		return exitUsage
+	} else {
+		cfg, err := EnvConfig(os.Getenv)
+		if err != nil {
+			fmt.Fprintf(stderr, "config: %v\n", err)
+			return exitUsage
+		}
+		// Без переопределений остается клиент, собранный при запуске
+		// (в тестах - подмененный)
+		if !reflect.DeepEqual(cfg, DefaultConfig()) {
+			prevConfig, prevExchanger := globalConfig, globalExchanger
+			globalConfig, globalExchanger = cfg, cfg.NewExchanger(os.Getenv)
+			defer func() { globalConfig, globalExchanger = prevConfig, prevExchanger }()
+		}
+	}
+
+	if *metricsAddr == "" {
+		*metricsAddr = globalConfig.MetricsAddr
+	}
+	if *metricsAddr != "" {
+		srv, err := telemetry.ServeMetrics(*metricsAddr, globalMetrics)
+		if err != nil {
+			fmt.Fprintf(stderr, "metrics: %v\n", err)
+			return exitError
+		}
+		defer srv.Close()
+	}
+
+	if *otlpEndpoint == "" {
+		*otlpEndpoint = globalConfig.Tracing.OTLPEndpoint
+	}
+	if *otlpEndpoint != "" {
+		var opts []telemetry.OTLPOption
+		if name := globalConfig.Tracing.ServiceName; name != "" {
+			opts = append(opts, telemetry.WithOTLPServiceName(name))
+		}
+		tracer := telemetry.NewTracer(telemetry.NewOTLPExporter(*otlpEndpoint, opts...), telemetry.WithTracerErrorHandler(func(err error) {
+			fmt.Fprintf(stderr, "tracing: %v\n", err)
+		}))
+		prevTracer, prevExchanger := globalTracer, globalExchanger
+		globalTracer, globalExchanger = tracer, exmo.NewTracingExchanger(globalExchanger, tracer)
+		defer func() {
+			if err := tracer.Shutdown(context.Background()); err != nil {
+				fmt.Fprintf(stderr, "tracing: %v\n", err)
+			}
+			globalTracer, globalExchanger = prevTracer, prevExchanger
+		}()
+	}
+
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		printUsage(stderr)
+		if len(args) == 0 {
+			return exitUsage
+		}
+		return exitOK
+	}
+
+	for _, cmd := range commands() {
+		if cmd.name != args[0] {
+			continue
+		}
+		err := cmd.run(args[1:], stdout, stderr)
+		switch {
+		case err == nil:
+			return exitOK
+		case errors.Is(err, flag.ErrHelp):
+			return exitOK
+		case errors.Is(err, errUsage):
+			fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+			return exitUsage
+		default:
+			fmt.Fprintf(stderr, "%s: %v\n", cmd.name, err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(stderr, "неизвестная команда %q\n", args[0])
+	printUsage(stderr)
+	return exitUsage
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "Использование: exmo [-config файл] [-profile имя] [-metrics адрес] [-otlp адрес] <команда> [флаги]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Команды:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands() {
+		fmt.Fprintf(tw, "  %s\t%s\n", cmd.name, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Флаги команды: exmo <команда> -h")
+}
+
+// cliFlags - общие флаги команд. Каждая команда регистрирует только нужные ей.
+type cliFlags struct {
+	fs         *flag.FlagSet
+	pair       string
+	resolution int
+	period     int
+	limit      int
+	from       string
+	to         string
+	output     string
+	outFile    string
+}
+
+// newCLIFlags создает набор флагов команды с флагами вывода. Справка
+// команды дополняется описанием полей результата.
+func newCLIFlags(name string, stderr io.Writer, columns []Column) *cliFlags {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	c := &cliFlags{fs: fs}
+	fs.StringVar(&c.output, "output", string(OutputTable), "формат вывода: table, json, csv или ndjson")
+	fs.StringVar(&c.outFile, "out", "", "файл для вывода вместо stdout")
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "Использование: exmo %s [флаги]\n", name)
+		fs.PrintDefaults()
+		if len(columns) > 0 {
+			fmt.Fprintln(stderr, "\nПоля результата:")
+			(&Result{Columns: columns}).WriteSchema(stderr)
+		}
+	}
+	return c
+}
+
+// defaultPair - первая пара из настроек.
+func defaultPair() string {
+	if len(globalConfig.Pairs) > 0 {
+		return globalConfig.Pairs[0]
+	}
+	return "BTC_USD"
+}
+
+func (c *cliFlags) withPair(def string) *cliFlags {
+	c.fs.StringVar(&c.pair, "pair", def, "торговые пары через запятую")
+	return c
+}
+
+func (c *cliFlags) withLimit(def int) *cliFlags {
+	c.fs.IntVar(&c.limit, "limit", def, "максимальное число записей")
+	return c
+}
+
+// withRange регистрирует разрешение свечей и интервал времени.
+func (c *cliFlags) withRange() *cliFlags {
+	c.fs.IntVar(&c.resolution, "resolution", 30, "разрешение свечей в минутах")
+	return c.withInterval()
+}
+
+func (c *cliFlags) withInterval() *cliFlags {
+	c.fs.StringVar(&c.from, "from", "-2d", "начало интервала: RFC3339 или смещение от текущего момента (-2d, -6h)")
+	c.fs.StringVar(&c.to, "to", "now", "конец интервала: RFC3339, смещение или now")
+	return c
+}
+
+func (c *cliFlags) withPeriod(def int) *cliFlags {
+	c.fs.IntVar(&c.period, "period", def, "период индикатора")
+	return c
+}
+
+// parse разбирает флаги. Лишние позиционные аргументы считаются ошибкой.
+func (c *cliFlags) parse(args []string) error {
+	if err := c.fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if c.fs.NArg() > 0 {
+		return fmt.Errorf("%w: unexpected arguments %v", errUsage, c.fs.Args())
+	}
+	if c.limit < 0 {
+		return fmt.Errorf("%w: limit must not be negative", errUsage)
+	}
+	if c.fs.Lookup("resolution") != nil && c.resolution <= 0 {
+		return fmt.Errorf("%w: resolution must be positive", errUsage)
+	}
+	if _, err := parseOutputFormat(c.output); err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	return nil
+}
+
+// write выводит результат в stdout или в файл из флага -out.
+func (c *cliFlags) write(stdout io.Writer, result *Result) error {
+	format, err := parseOutputFormat(c.output)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+	if c.outFile == "" {
+		return result.Write(stdout, format)
+	}
+
+	var buf bytes.Buffer
+	if err := result.Write(&buf, format); err != nil {
+		return err
+	}
+	return fsutil.WriteFileAtomic(c.outFile, buf.Bytes())
+}
+
+func (c *cliFlags) pairs() ([]string, error) {
+	var pairs []string
+	for _, p := range strings.Split(c.pair, ",") {
+		if p = strings.ToUpper(strings.TrimSpace(p)); p != "" {
+			pairs = append(pairs, p)
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("%w: -pair is required", errUsage)
+	}
+	return pairs, nil
+}
+
+// singlePair возвращает единственную пару для команд, работающих с одной парой.
+func (c *cliFlags) singlePair() (string, error) {
+	pairs, err := c.pairs()
+	if err != nil {
+		return "", err
+	}
+	if len(pairs) > 1 {
+		return "", fmt.Errorf("%w: exactly one pair expected, got %d", errUsage, len(pairs))
+	}
+	return pairs[0], nil
+}
+
+func (c *cliFlags) timeRange(now time.Time) (time.Time, time.Time, error) {
+	from, err := timearg.Parse(c.from, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -from: %v", errUsage, err)
+	}
+	to, err := timearg.Parse(c.to, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -to: %v", errUsage, err)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: -from %s is not before -to %s", errUsage, from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return from, to, nil
+}
+
+var tickerColumns = []Column{
+	{"pair", "PAIR", ColumnString, "торговая пара"},
+	{"buy_price", "BUY", ColumnNumber, "лучшая цена покупки"},
+	{"sell_price", "SELL", ColumnNumber, "лучшая цена продажи"},
+	{"last_trade", "LAST", ColumnNumber, "цена последней сделки"},
+	{"high", "HIGH", ColumnNumber, "максимум за 24 часа"},
+	{"low", "LOW", ColumnNumber, "минимум за 24 часа"},
+	{"avg", "AVG", ColumnNumber, "средняя цена за 24 часа"},
+	{"vol", "VOL", ColumnNumber, "объем за 24 часа в базовой валюте"},
+	{"vol_curr", "VOL CURR", ColumnNumber, "объем за 24 часа в валюте котировки"},
+	{"updated", "UPDATED", ColumnTime, "время обновления"},
+}
+
+func runTicker(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("ticker", stderr, tickerColumns).withPair("")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	ticker, err := globalExchanger.GetTicker()
+	if err != nil {
+		return err
+	}
+
+	pairs := make([]string, 0, len(ticker))
+	if f.pair == "" {
+		for pair := range ticker {
+			pairs = append(pairs, pair)
+		}
+		sort.Strings(pairs)
+	} else {
+		if pairs, err = f.pairs(); err != nil {
+			return err
+		}
+	}
+
+	result := &Result{Columns: tickerColumns}
+	for _, pair := range pairs {
+		v, ok := ticker[pair]
+		if !ok {
+			return fmt.Errorf("%w: %s", exmo.ErrInvalidPair, pair)
+		}
+		result.Add(pair, numberCell(v.BuyPrice), numberCell(v.SellPrice), numberCell(v.LastTrade),
+			numberCell(v.High), numberCell(v.Low), numberCell(v.Avg), numberCell(v.Vol), numberCell(v.VolCurr),
+			time.Unix(v.Updated, 0))
+	}
+	return f.write(stdout, result)
+}
+
+var tradesColumns = []Column{
+	{"pair", "PAIR", ColumnString, "торговая пара"},
+	{"trade_id", "ID", ColumnInt, "идентификатор сделки"},
+	{"time", "TIME", ColumnTime, "время сделки"},
+	{"type", "TYPE", ColumnString, "сторона тейкера: buy или sell"},
+	{"price", "PRICE", ColumnNumber, "цена"},
+	{"quantity", "QUANTITY", ColumnNumber, "количество в базовой валюте"},
+	{"amount", "AMOUNT", ColumnNumber, "сумма в валюте котировки"},
+}
+
+func runTrades(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("trades", stderr, tradesColumns).withPair(defaultPair()).withLimit(20)
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	pairs, err := f.pairs()
+	if err != nil {
+		return err
+	}
+	trades, err := globalExchanger.GetTrades(pairs...)
+	if err != nil {
+		return err
+	}
+
+	result := &Result{Columns: tradesColumns}
+	for _, pair := range pairs {
+		for i, t := range trades[pair] {
+			if f.limit > 0 && i >= f.limit {
+				break
+			}
+			result.Add(pair, t.TradeID, time.Unix(t.Date, 0), string(t.Type),
+				numberCell(t.Price), numberCell(t.Quantity), numberCell(t.Amount))
+		}
+	}
+	return f.write(stdout, result)
+}
+
+var orderBookColumns = []Column{
+	{"pair", "PAIR", ColumnString, "торговая пара"},
+	{"side", "SIDE", ColumnString, "ask - продажа, bid - покупка"},
+	{"price", "PRICE", ColumnNumber, "цена уровня"},
+	{"quantity", "QUANTITY", ColumnNumber, "количество на уровне"},
+	{"amount", "AMOUNT", ColumnNumber, "сумма уровня в валюте котировки"},
+}
+
+func runOrderBook(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("orderbook", stderr, orderBookColumns).withPair(defaultPair()).withLimit(10)
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	pairs, err := f.pairs()
+	if err != nil {
+		return err
+	}
+	book, err := globalExchanger.GetOrderBook(f.limit, pairs...)
+	if err != nil {
+		return err
+	}
+
+	result := &Result{Columns: orderBookColumns}
+	for _, pair := range pairs {
+		asks, err := book[pair].Asks()
+		if err != nil {
+			return err
+		}
+		bids, err := book[pair].Bids()
+		if err != nil {
+			return err
+		}
+		// Продажи выводим сверху вниз, чтобы лучшие цены сходились у спреда
+		for i := len(asks) - 1; i >= 0; i-- {
+			result.Add(pair, "ask", asks[i].Price, asks[i].Quantity, asks[i].Price*asks[i].Quantity)
+		}
+		for _, l := range bids {
+			result.Add(pair, "bid", l.Price, l.Quantity, l.Price*l.Quantity)
+		}
+	}
+	return f.write(stdout, result)
+}
+
+var currenciesColumns = []Column{
+	{"currency", "CURRENCY", ColumnString, "код валюты"},
+}
+
+func runCurrencies(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("currencies", stderr, currenciesColumns)
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	currencies, err := globalExchanger.GetCurrencies()
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(currencies))
+	for c := range currencies {
+		names = append(names, c)
+	}
+	sort.Strings(names)
+	result := &Result{Columns: currenciesColumns}
+	for _, c := range names {
+		result.Add(c)
+	}
+	return f.write(stdout, result)
+}
+
+var candlesColumns = []Column{
+	{"time", "TIME", ColumnTime, "время открытия свечи"},
+	{"open", "OPEN", ColumnNumber, "цена открытия"},
+	{"high", "HIGH", ColumnNumber, "максимум"},
+	{"low", "LOW", ColumnNumber, "минимум"},
+	{"close", "CLOSE", ColumnNumber, "цена закрытия"},
+	{"volume", "VOLUME", ColumnNumber, "объем"},
+}
+
+func runCandles(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("candles", stderr, candlesColumns).withPair(defaultPair()).withRange().withLimit(0)
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	pair, err := f.singlePair()
+	if err != nil {
+		return err
+	}
+	from, to, err := f.timeRange(time.Now())
+	if err != nil {
+		return err
+	}
+	history, err := globalExchanger.GetCandlesHistory(pair, f.resolution, from, to)
+	if err != nil {
+		return err
+	}
+
+	candles := history.Candles
+	// При ограничении показываем самые свежие свечи
+	if f.limit > 0 && len(candles) > f.limit {
+		candles = candles[len(candles)-f.limit:]
+	}
+	result := &Result{Columns: candlesColumns}
+	for _, c := range candles {
+		result.Add(c.Time(), c.O, c.H, c.L, c.C, c.V)
+	}
+	return f.write(stdout, result)
+}
+
+var indicatorColumns = []Column{
+	{"indicator", "INDICATOR", ColumnString, "название индикатора"},
+	{"pair", "PAIR", ColumnString, "торговая пара"},
+	{"index", "#", ColumnInt, "номер значения от начала ряда"},
+	{"time", "TIME", ColumnTime, "время открытия свечи значения"},
+	{"value", "VALUE", ColumnNumber, "значение индикатора"},
+}
+
+// candleSnapshot отвечает на GetClosePrice из уже загруженных свечей, чтобы
+// значения индикатора и их время брались из одного ответа биржи.
+type candleSnapshot struct {
+	exmo.Exchanger
+	history exmo.CandlesHistory
+}
+
+func (c candleSnapshot) GetClosePrice(string, int, time.Time, time.Time) ([]float64, error) {
+	return c.history.ClosePrices(), nil
+}
+
+// indicatorSeries загружает свечи один раз и считает по ним индикатор kind
+// (sma или ema) и время открытия свечи каждого значения. Ряд индикатора
+// короче ряда свечей (у SMA на period-1) и выровнен по его концу, поэтому
+// значениям соответствуют последние свечи.
+func indicatorSeries(kind, pair string, resolution, period int, from, to time.Time, opts ...indicator.IndicatorOption) ([]float64, []time.Time, error) {
+	history, err := globalExchanger.GetCandlesHistory(pair, resolution, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts = append(opts, indicator.WithIndicatorMetrics(globalMetrics), indicator.WithIndicatorTracer(globalTracer))
+	indicators := indicator.NewIndicator(candleSnapshot{Exchanger: globalExchanger, history: history}, opts...)
+	calc := indicators.SMA
+	if kind == "ema" {
+		calc = indicators.EMA
+	}
+	values, err := calc(pair, resolution, period, from, to)
+	if err != nil {
+		return nil, nil, err
+	}
+	candles := history.Candles
+	if len(candles) < len(values) {
+		return nil, nil, fmt.Errorf("%s: %d indicator values for %d candles", pair, len(values), len(candles))
+	}
+	candles = candles[len(candles)-len(values):]
+	times := make([]time.Time, len(values))
+	for i, c := range candles {
+		times[i] = c.Time()
+	}
+	return values, times, nil
+}
+
+func runIndicator(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "-help") {
+			fmt.Fprintln(stderr, "Использование: exmo indicator <sma|ema> [флаги]")
+			fmt.Fprintln(stderr, "Без имени считаются индикаторы из файла настроек.")
+			return flag.ErrHelp
+		}
+		if len(globalConfig.Indicators) > 0 {
+			return runConfiguredIndicators(args, stdout, stderr)
+		}
+		return fmt.Errorf("%w: indicator name is required: sma or ema", errUsage)
+	}
+	name := strings.ToLower(args[0])
+
+	f := newCLIFlags("indicator "+name, stderr, indicatorColumns).withPair(defaultPair()).withRange().withPeriod(5)
+	if err := f.parse(args[1:]); err != nil {
+		return err
+	}
+	if f.period <= 0 {
+		return fmt.Errorf("%w: period must be positive", errUsage)
+	}
+	pair, err := f.singlePair()
+	if err != nil {
+		return err
+	}
+	from, to, err := f.timeRange(time.Now())
+	if err != nil {
+		return err
+	}
+
+	if name != "sma" && name != "ema" {
+		return fmt.Errorf("%w: unknown indicator %q: want sma or ema", errUsage, name)
+	}
+
+	values, times, err := indicatorSeries(name, pair, f.resolution, f.period, from, to,
+		indicator.WithCalculateSMA(indicator.CalculateSMA),
+		indicator.WithCalculateEMA(indicator.CalculateEMA),
+	)
+	if err != nil {
+		return err
+	}
+	result := &Result{Columns: indicatorColumns}
+	for i, v := range values {
+		result.Add(strings.ToUpper(name), pair, i, times[i], v)
+	}
+	return f.write(stdout, result)
+}
+
+// runConfiguredIndicators считает все индикаторы из настроек за общий интервал.
+func runConfiguredIndicators(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("indicator", stderr, indicatorColumns).withInterval()
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	from, to, err := f.timeRange(time.Now())
+	if err != nil {
+		return err
+	}
+
+	result := &Result{Columns: indicatorColumns}
+	for _, ind := range globalConfig.Indicators {
+		values, times, err := indicatorSeries(ind.Kind, ind.Pair, ind.Resolution, ind.Period, from, to)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ind.Name, err)
+		}
+		for i, v := range values {
+			result.Add(ind.Name, ind.Pair, i, times[i], v)
+		}
+	}
+	return f.write(stdout, result)
+}
+
+var syncColumns = []Column{
+	{"pair", "PAIR", ColumnString, "торговая пара"},
+	{"resolution", "RESOLUTION", ColumnInt, "разрешение свечей в минутах"},
+	{"requests", "REQUESTS", ColumnInt, "число запросов к бирже"},
+	{"candles", "CANDLES", ColumnInt, "число загруженных свечей"},
+}
+
+// runSync догружает в локальное хранилище недостающие свечи за интервал.
+func runSync(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("sync", stderr, syncColumns).withPair(defaultPair()).withRange()
+	dir := f.fs.String("store", globalConfig.CandleStoreDir(), "каталог хранилища свечей")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	pair, err := f.singlePair()
+	if err != nil {
+		return err
+	}
+	from, to, err := f.timeRange(time.Now())
+	if err != nil {
+		return err
+	}
+
+	store := candlestore.NewCandleStore(*dir)
+	sync, err := store.Sync(globalConfig.NewClient(os.Getenv, nil), pair, f.resolution, from, to)
+	if err != nil {
+		return err
+	}
+	result := &Result{Columns: syncColumns}
+	result.Add(pair, f.resolution, sync.Requests, sync.Candles)
+	return f.write(stdout, result)
+}
+
+// runWatch показывает живую сводку. Если stdin - терминал, он переводится
+// в сырой режим, чтобы переключать пары одиночными клавишами.
+func runWatch(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("watch", stderr, nil).withPair(strings.Join(globalConfig.Pairs, ",")).withLimit(10).withPeriod(5)
+	f.fs.IntVar(&f.resolution, "resolution", 30, "разрешение свечей для индикаторов в минутах")
+	interval := f.fs.Duration("interval", 5*time.Second, "интервал обновления")
+	budget := f.fs.Int("budget", 60, "максимум запросов к бирже в минуту")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+	pairs, err := f.pairs()
+	if err != nil {
+		return err
+	}
+	if f.limit == 0 || f.period <= 0 {
+		return fmt.Errorf("%w: limit and period must be positive", errUsage)
+	}
+
+	stdin := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(stdin)
+	dashboard, err := NewDashboard(globalExchanger, pairs,
+		WithDashboardInterval(*interval),
+		WithDashboardBudget(*budget),
+		WithDashboardDepth(f.limit),
+		WithDashboardIndicator(f.resolution, f.period),
+		WithDashboardColor(interactive && os.Getenv("NO_COLOR") == ""),
+	)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUsage, err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	var keys chan string
+	out := stdout
+	if interactive {
+		state, err := term.MakeRaw(stdin)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(stdin, state)
+		// В сыром режиме перевод строки не возвращает каретку
+		out = crlfWriter{stdout}
+		fmt.Fprint(stdout, "\x1b[?25l")
+		defer fmt.Fprint(stdout, "\x1b[?25h\r\n")
+
+		keys = make(chan string)
+		go readKeys(os.Stdin, keys)
+	}
+	return dashboard.Run(ctx, out, keys)
+}
+
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	if _, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n"))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// stringsFlag - флаг, который можно указать несколько раз.
+type stringsFlag []string
+
+func (s *stringsFlag) String() string {
+	return strings.Join(*s, "; ")
+}
+
+func (s *stringsFlag) Set(v string) error {
+	*s = append(*s, v)
+	return nil
+}
+
+// runAlert проверяет правила оповещений по таймеру или один раз с -once.
+func runAlert(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("alert", stderr, nil)
+	var exprs stringsFlag
+	f.fs.Var(&exprs, "rule", "правило, например \"BTC_USD last_trade crosses 60000\"; можно повторять")
+	hysteresis := f.fs.Float64("hysteresis", 0, "гистерезис для правил из -rule")
+	cooldown := f.fs.Duration("cooldown", 0, "минимальная пауза между оповещениями правила из -rule")
+	interval := f.fs.Duration("interval", 30*time.Second, "интервал проверки")
+	statePath := f.fs.String("state", filepath.Join(globalConfig.StoreDir, "alerts.json"), "файл состояния правил, пустой - без сохранения")
+	webhook := f.fs.String("webhook", "", "URL для POST-оповещений")
+	file := f.fs.String("file", "", "файл для оповещений в формате NDJSON")
+	once := f.fs.Bool("once", false, "проверить правила один раз и выйти")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	rules, err := globalConfig.AlertRules()
+	if err != nil {
+		return err
+	}
+	for _, expr := range exprs {
+		r, err := NewAlertRule("", expr, *hysteresis, *cooldown)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		rules = append(rules, r)
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("%w: no rules: add -rule or alerts to the config", errUsage)
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("%w: interval must be positive", errUsage)
+	}
+
+	sinks := []AlertSink{NewWriterSink(stdout)}
+	if *webhook != "" {
+		sinks = append(sinks, NewWebhookSink(*webhook, nil))
+	}
+	if *file != "" {
+		sinks = append(sinks, NewFileSink(*file))
+	}
+	engine, err := NewAlertEngine(globalExchanger, rules, WithAlertSinks(sinks...), WithAlertState(*statePath))
+	if err != nil {
+		return err
+	}
+
+	if *once {
+		_, err := engine.Evaluate(context.Background())
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	engine.Run(ctx, *interval, func(err error) { fmt.Fprintf(stderr, "alert: %v\n", err) })
+	return nil
+}
+
+func runServe(args []string, stdout, stderr io.Writer) error {
+	f := newCLIFlags("serve", stderr, nil)
+	addr := f.fs.String("addr", ":8080", "адрес HTTP-сервера")
+	shutdownTimeout := f.fs.Duration("shutdown-timeout", 10*time.Second, "сколько ждать текущие запросы при остановке")
+	f.withPair(strings.Join(globalConfig.Pairs, ","))
+	stream := f.fs.Duration("stream-interval", 2*time.Second, "интервал опроса для потоков /v1/stream, /v1/ws и gRPC, 0 - без потоков")
+	grpcAddr := f.fs.String("grpc", "", "адрес gRPC-сервера, пустой - не запускать")
+	origins := f.fs.String("allowed-origins", "", "сайты через запятую, которым разрешен /v1/ws, * - любые; по умолчанию только тот же хост")
+	if err := f.parse(args); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	indicators := indicator.NewIndicator(globalExchanger, indicator.WithIndicatorMetrics(globalMetrics), indicator.WithIndicatorTracer(globalTracer))
+	var opts []server.ServerOption
+	grpcOpts := []server.GRPCOption{server.WithGRPCContext(ctx)}
+	if *stream > 0 {
+		pairs, err := f.pairs()
+		if err != nil {
+			return err
+		}
+		hubOpts := []server.HubOption{server.WithHubInterval(*stream)}
+		if *origins != "" {
+			hubOpts = append(hubOpts, server.WithHubAllowedOrigins(strings.Split(*origins, ",")...))
+		}
+		hub, err := server.NewHub(globalExchanger, pairs, hubOpts...)
+		if err != nil {
+			return fmt.Errorf("%w: %v", errUsage, err)
+		}
+		go hub.Run(ctx, func(err error) { fmt.Fprintf(stderr, "serve: %v\n", err) })
+		opts = append(opts, server.WithServerHub(hub))
+		grpcOpts = append(grpcOpts, server.WithGRPCHub(hub))
+	}
+	srv := server.NewServer(globalExchanger, indicators, opts...)
+
+	ln, err := net.Listen("tcp", *addr)
+	if err != nil {
+		return err
+	}
+	if *grpcAddr != "" {
+		gln, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			ln.Close()
+			return err
+		}
+		g := grpc.NewServer()
+		server.NewGRPCServer(globalExchanger, indicators, grpcOpts...).Register(g)
+		go g.Serve(gln)
+		defer func() {
+			// GracefulStop ждет все вызовы, поэтому ограничен -shutdown-timeout
+			stopped := make(chan struct{})
+			go func() {
+				g.GracefulStop()
+				close(stopped)
+			}()
+			select {
+			case <-stopped:
+			case <-time.After(*shutdownTimeout):
+				g.Stop()
+			}
+		}()
+		fmt.Fprintf(stderr, "serve: gRPC listening on %s\n", gln.Addr())
+	}
+	fmt.Fprintf(stderr, "serve: listening on %s\n", ln.Addr())
+	return srv.Serve(ctx, ln, *shutdownTimeout)
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func withExchanger(t *testing.T, ex exmo.Exchanger) {
+	original := globalExchanger
+	globalExchanger = ex
+	t.Cleanup(func() { globalExchanger = original })
+}
+
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun_Usage(t *testing.T) {
+	code, _, stderr := runCLI()
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "indicator")
+
+	code, _, stderr = runCLI("bogus")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `неизвестная команда "bogus"`)
+
+	code, _, _ = runCLI("candles", "-from", "tomorrow")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCLI("candles", "-from", "-1h", "-to", "-2h")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCLI("indicator", "rsi")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCLI("indicator")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCLI("trades", "extra")
+	assert.Equal(t, exitUsage, code)
+
+	code, _, _ = runCLI("ticker", "-h")
+	assert.Equal(t, exitOK, code)
+}
+
+func TestRun_Ticker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+
+	mock.EXPECT().GetTicker().Return(exmo.Ticker{
+		"BTC_USD": {BuyPrice: "100", SellPrice: "101", LastTrade: "100.5"},
+		"ETH_USD": {BuyPrice: "10", SellPrice: "11", LastTrade: "10.5"},
+	}, nil).Times(3)
+
+	code, stdout, _ := runCLI("ticker")
+	assert.Equal(t, exitOK, code)
+	assert.Regexp(t, `(?s)BTC_USD\s+100\s+101\s+100.5.*ETH_USD`, stdout)
+
+	code, stdout, _ = runCLI("ticker", "-pair", "eth_usd")
+	assert.Equal(t, exitOK, code)
+	assert.NotContains(t, stdout, "BTC_USD")
+	assert.Contains(t, stdout, "ETH_USD")
+
+	code, _, stderr := runCLI("ticker", "-pair", "XRP_USD")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "XRP_USD")
+}
+
+func TestRun_Candles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(2 * time.Hour)
+	mock.EXPECT().GetCandlesHistory("ETH_USD", 60, from, to).Return(exmo.CandlesHistory{Candles: []exmo.Candle{
+		{T: from.UnixMilli(), O: 1, H: 2, L: 0.5, C: 1.5, V: 10},
+		{T: from.Add(time.Hour).UnixMilli(), O: 1.5, H: 3, L: 1, C: 2.5, V: 20},
+	}}, nil)
+
+	code, stdout, _ := runCLI("candles", "-pair", "ETH_USD", "-resolution", "60",
+		"-from", from.Format(time.RFC3339), "-to", to.Format(time.RFC3339), "-limit", "1")
+	assert.Equal(t, exitOK, code)
+	assert.NotContains(t, stdout, "2024-03-01 00:00:00")
+	assert.Regexp(t, `2024-03-01 01:00:00\s+1.5\s+3\s+1\s+2.5\s+20`, stdout)
+}
+
+func TestRun_ExchangeError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+
+	mock.EXPECT().GetOrderBook(5, "BTC_USD").Return(nil, errors.New("boom"))
+
+	code, _, stderr := runCLI("orderbook", "-limit", "5")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "orderbook: boom")
+}
+
+func TestRun_IndicatorCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	mock.EXPECT().GetCandlesHistory("BTC_USD", 60, gomock.Any(), gomock.Any()).Return(exmo.CandlesHistory{Candles: []exmo.Candle{
+		{T: start.UnixMilli(), C: 1},
+		{T: start.Add(time.Hour).UnixMilli(), C: 2},
+		{T: start.Add(2 * time.Hour).UnixMilli(), C: 3},
+	}}, nil)
+
+	code, stdout, stderr := runCLI("indicator", "sma", "-period", "2", "-resolution", "60", "-output", "csv")
+	require.Equal(t, exitOK, code, stderr)
+	// Первое значение SMA(2) относится ко второй свече
+	assert.Equal(t, "indicator,pair,index,time,value\n"+
+		"SMA,BTC_USD,0,2024-01-01T01:00:00Z,1.5\n"+
+		"SMA,BTC_USD,1,2024-01-01T02:00:00Z,2.5\n", stdout)
+}
+
+func TestRun_Tracing(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+	mock.EXPECT().GetCandlesHistory("BTC_USD", 30, gomock.Any(), gomock.Any()).Return(exmo.CandlesHistory{Candles: make([]exmo.Candle, 3)}, nil)
+
+	var mu sync.Mutex
+	names := map[string]bool{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ResourceSpans []struct {
+				ScopeSpans []struct {
+					Spans []struct {
+						Name string `json:"name"`
+					} `json:"spans"`
+				} `json:"scopeSpans"`
+			} `json:"resourceSpans"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					names[s.Name] = true
+				}
+			}
+		}
+	}))
+	defer ts.Close()
+
+	code, _, stderr := runCLI("-otlp", ts.URL, "indicator", "sma", "-period", "2")
+	require.Equal(t, exitOK, code, stderr)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, map[string]bool{"Indicator.SMA": true, "Indicator.calculate": true, "Exchanger.GetCandlesHistory": true}, names)
+	assert.Nil(t, globalTracer)
+}
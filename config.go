@@ -0,0 +1,463 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/candlestore"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/paper"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// Config - настройки клиента и стратегий. Файл YAML содержит базовые
+// настройки на верхнем уровне и именованные профили, которые их
+// переопределяют:
+//
+//	default_profile: prod
+//	exchange:
+//	  base_url: https://api.exmo.com/v1
+//	  timeout: 10s
+//	pairs: [BTC_USD, ETH_USD]
+//	profiles:
+//	  sandbox:
+//	    exchange:
+//	      base_url: https://sandbox.example/v1
+//	  binance:
+//	    exchange:
+//	      venue: binance
+//	  paper:
+//	    exchange:
+//	      venue: paper
+//	      paper:
+//	        balances: {USD: 1000}
+//
+// Переменные окружения EXMO_* применяются поверх выбранного профиля.
+type Config struct {
+	Profile    string            `yaml:"-"`
+	Exchange   ExchangeConfig    `yaml:"exchange"`
+	Pairs      []string          `yaml:"pairs"`
+	Indicators []IndicatorConfig `yaml:"indicators"`
+	Alerts     []AlertConfig     `yaml:"alerts"`
+	StoreDir   string            `yaml:"store_dir"`
+	// MetricsAddr - адрес HTTP-листенера /metrics, пусто - не запускать
+	MetricsAddr string        `yaml:"metrics_addr"`
+	Tracing     TracingConfig `yaml:"tracing"`
+}
+
+// TracingConfig - экспорт спанов по OTLP/HTTP, пустой адрес - без трассировки.
+type TracingConfig struct {
+	OTLPEndpoint string `yaml:"otlp_endpoint"`
+	ServiceName  string `yaml:"service_name"`
+}
+
+// Биржи, поддерживаемые exchange.venue.
+const (
+	VenueExmo    = "exmo"
+	VenueBinance = "binance"
+	VenuePaper   = "paper" // бумажная торговля по рыночным данным Exmo
+)
+
+const defaultExmoURL = "https://api.exmo.com/v1"
+
+type ExchangeConfig struct {
+	// Venue - биржа: exmo, binance или paper. Пары во всех настройках задаются
+	// в канонической форме BTC_USD независимо от биржи
+	Venue   string        `yaml:"venue"`
+	BaseURL string        `yaml:"base_url"`
+	Timeout time.Duration `yaml:"timeout"`
+	// Ключи не хранятся в файле: указываются имена переменных окружения с ними
+	APIKeyEnv    string          `yaml:"api_key_env"`
+	APISecretEnv string          `yaml:"api_secret_env"`
+	Retry        RetryConfig     `yaml:"retry"`
+	RateLimit    RateLimitConfig `yaml:"rate_limit"`
+	Paper        PaperConfig     `yaml:"paper"`
+}
+
+type RetryConfig struct {
+	MaxAttempts int           `yaml:"max_attempts"`
+	Backoff     time.Duration `yaml:"backoff"`
+	MaxBackoff  time.Duration `yaml:"max_backoff"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	Burst             int `yaml:"burst"`
+}
+
+// PaperConfig - бумажная биржа для venue: paper. Балансы и ордера живут
+// в памяти процесса и не сохраняются между запусками.
+type PaperConfig struct {
+	Balances map[string]float64 `yaml:"balances"`
+	TakerFee float64            `yaml:"taker_fee"`
+	MakerFee float64            `yaml:"maker_fee"`
+}
+
+// IndicatorConfig - индикатор, который считает команда indicator без аргументов.
+type IndicatorConfig struct {
+	Name       string `yaml:"name"`
+	Kind       string `yaml:"kind"` // sma или ema
+	Pair       string `yaml:"pair"`
+	Resolution int    `yaml:"resolution"`
+	Period     int    `yaml:"period"`
+}
+
+// AlertConfig - правило оповещения, см. ParseAlertCondition.
+type AlertConfig struct {
+	Name       string        `yaml:"name"`
+	Rule       string        `yaml:"rule"`
+	Hysteresis float64       `yaml:"hysteresis"`
+	Cooldown   time.Duration `yaml:"cooldown"`
+}
+
+// AlertRules разбирает правила оповещений из настроек.
+func (c Config) AlertRules() ([]*AlertRule, error) {
+	rules := make([]*AlertRule, 0, len(c.Alerts))
+	for _, a := range c.Alerts {
+		r, err := NewAlertRule(a.Name, a.Rule, a.Hysteresis, a.Cooldown)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, r)
+	}
+	return rules, nil
+}
+
+// DefaultConfig возвращает настройки, которые используются без файла.
+func DefaultConfig() Config {
+	return Config{
+		Profile: "default",
+		Exchange: ExchangeConfig{
+			Venue:        VenueExmo,
+			BaseURL:      defaultExmoURL,
+			Timeout:      10 * time.Second,
+			APIKeyEnv:    "EXMO_API_KEY",
+			APISecretEnv: "EXMO_API_SECRET",
+			Retry:        RetryConfig{MaxAttempts: 3, Backoff: 500 * time.Millisecond, MaxBackoff: 5 * time.Second},
+			RateLimit:    RateLimitConfig{RequestsPerMinute: 600, Burst: 10},
+			Paper:        PaperConfig{TakerFee: 0.003, MakerFee: 0.003},
+		},
+		Pairs:    []string{"BTC_USD", "ETH_USD"},
+		StoreDir: defaultStoreDir(),
+	}
+}
+
+type configFile struct {
+	DefaultProfile string               `yaml:"default_profile"`
+	Profiles       map[string]yaml.Node `yaml:"profiles"`
+	Config         `yaml:",inline"`
+}
+
+// LoadConfig читает файл и выбирает профиль. Пустой profile означает
+// EXMO_PROFILE, затем default_profile из файла. getenv обычно os.Getenv.
+func LoadConfig(path, profile string, getenv func(string) string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	cfg, err := ParseConfig(data, profile, getenv)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// ParseConfig разбирает YAML, накладывает профиль и переменные окружения
+// и проверяет результат.
+func ParseConfig(data []byte, profile string, getenv func(string) string) (Config, error) {
+	file := configFile{Config: DefaultConfig()}
+	if err := decodeStrict(data, &file); err != nil {
+		return Config{}, err
+	}
+
+	if profile == "" {
+		profile = getenv("EXMO_PROFILE")
+	}
+	if profile == "" {
+		profile = file.DefaultProfile
+	}
+	cfg := file.Config
+	cfg.Profile = "default"
+	if profile != "" {
+		node, ok := file.Profiles[profile]
+		if !ok {
+			return Config{}, fmt.Errorf("unknown profile %q, available: %s", profile, strings.Join(profileNames(file.Profiles), ", "))
+		}
+		// Профиль декодируется поверх базовых настроек и меняет только заданные поля
+		raw, err := yaml.Marshal(&node)
+		if err != nil {
+			return Config{}, err
+		}
+		if err := decodeStrict(raw, &cfg); err != nil {
+			return Config{}, fmt.Errorf("profile %q: %w", profile, err)
+		}
+		cfg.Profile = profile
+	}
+
+	return cfg.withEnv(getenv)
+}
+
+// EnvConfig возвращает настройки по умолчанию с переопределениями из
+// окружения. Используется, когда файл настроек не задан.
+func EnvConfig(getenv func(string) string) (Config, error) {
+	return DefaultConfig().withEnv(getenv)
+}
+
+// withEnv накладывает переменные окружения и проверяет результат.
+func (c Config) withEnv(getenv func(string) string) (Config, error) {
+	if err := c.applyEnv(getenv); err != nil {
+		return Config{}, err
+	}
+	// Адрес Exmo по умолчанию не подходит другой бирже
+	if c.Exchange.Venue == VenueBinance && c.Exchange.BaseURL == defaultExmoURL {
+		c.Exchange.BaseURL = binance.DefaultBaseURL
+	}
+	if err := c.Validate(); err != nil {
+		return Config{}, fmt.Errorf("profile %q: %w", c.Profile, err)
+	}
+	return c, nil
+}
+
+func decodeStrict(data []byte, v interface{}) error {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	// Пустой файл допустим: остаются настройки по умолчанию
+	if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+func profileNames(profiles map[string]yaml.Node) []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyEnv применяет переопределения из окружения.
+func (c *Config) applyEnv(getenv func(string) string) error {
+	var errs []error
+	str := func(name string, dst *string) {
+		if v := getenv(name); v != "" {
+			*dst = v
+		}
+	}
+	dur := func(name string, dst *time.Duration) {
+		if v := getenv(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid duration %q", name, v))
+				return
+			}
+			*dst = d
+		}
+	}
+	num := func(name string, dst *int) {
+		if v := getenv(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: invalid integer %q", name, v))
+				return
+			}
+			*dst = n
+		}
+	}
+
+	str("EXMO_VENUE", &c.Exchange.Venue)
+	str("EXMO_BASE_URL", &c.Exchange.BaseURL)
+	dur("EXMO_TIMEOUT", &c.Exchange.Timeout)
+	num("EXMO_RETRY_MAX_ATTEMPTS", &c.Exchange.Retry.MaxAttempts)
+	dur("EXMO_RETRY_BACKOFF", &c.Exchange.Retry.Backoff)
+	dur("EXMO_RETRY_MAX_BACKOFF", &c.Exchange.Retry.MaxBackoff)
+	num("EXMO_RATE_LIMIT", &c.Exchange.RateLimit.RequestsPerMinute)
+	num("EXMO_RATE_BURST", &c.Exchange.RateLimit.Burst)
+	str("EXMO_STORE_DIR", &c.StoreDir)
+	str("EXMO_METRICS_ADDR", &c.MetricsAddr)
+	str("EXMO_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	if v := getenv("EXMO_PAIRS"); v != "" {
+		c.Pairs = strings.Split(v, ",")
+	}
+	return errors.Join(errs...)
+}
+
+// Validate проверяет настройки и перечисляет все найденные ошибки.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(field, format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf("%s: %s", field, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Exchange.Venue != VenueExmo && c.Exchange.Venue != VenueBinance && c.Exchange.Venue != VenuePaper {
+		fail("exchange.venue", "must be %s, %s or %s, got %q", VenueExmo, VenueBinance, VenuePaper, c.Exchange.Venue)
+	}
+	if u, err := url.Parse(c.Exchange.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		fail("exchange.base_url", "must be an absolute http(s) URL, got %q", c.Exchange.BaseURL)
+	}
+	if c.Exchange.Timeout < 0 {
+		fail("exchange.timeout", "must not be negative")
+	}
+	if c.Exchange.Retry.MaxAttempts < 0 {
+		fail("exchange.retry.max_attempts", "must not be negative")
+	}
+	if c.Exchange.Retry.Backoff < 0 || c.Exchange.Retry.MaxBackoff < 0 {
+		fail("exchange.retry", "backoff must not be negative")
+	}
+	if c.Exchange.RateLimit.RequestsPerMinute < 0 {
+		fail("exchange.rate_limit.requests_per_minute", "must not be negative")
+	}
+	if c.Exchange.RateLimit.Burst < 0 {
+		fail("exchange.rate_limit.burst", "must not be negative")
+	}
+	if f := c.Exchange.Paper.TakerFee; f < 0 || f >= 1 {
+		fail("exchange.paper.taker_fee", "must be in [0, 1), got %v", f)
+	}
+	if f := c.Exchange.Paper.MakerFee; f < 0 || f >= 1 {
+		fail("exchange.paper.maker_fee", "must be in [0, 1), got %v", f)
+	}
+	currencies := make([]string, 0, len(c.Exchange.Paper.Balances))
+	for currency, amount := range c.Exchange.Paper.Balances {
+		if amount < 0 {
+			currencies = append(currencies, currency)
+		}
+	}
+	sort.Strings(currencies)
+	for _, currency := range currencies {
+		fail("exchange.paper.balances."+currency, "must not be negative")
+	}
+	if len(c.Pairs) == 0 {
+		fail("pairs", "at least one pair is required")
+	}
+	for i, p := range c.Pairs {
+		if _, _, err := exmo.SplitPair(strings.TrimSpace(p)); err != nil {
+			fail(fmt.Sprintf("pairs[%d]", i), "invalid pair %q, want BASE_QUOTE", p)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i, ind := range c.Indicators {
+		field := fmt.Sprintf("indicators[%d]", i)
+		if ind.Name == "" {
+			fail(field+".name", "is required")
+		} else if names[ind.Name] {
+			fail(field+".name", "duplicate name %q", ind.Name)
+		}
+		names[ind.Name] = true
+		if ind.Kind != "sma" && ind.Kind != "ema" {
+			fail(field+".kind", "must be sma or ema, got %q", ind.Kind)
+		}
+		if _, _, err := exmo.SplitPair(ind.Pair); err != nil {
+			fail(field+".pair", "invalid pair %q, want BASE_QUOTE", ind.Pair)
+		}
+		if ind.Resolution <= 0 {
+			fail(field+".resolution", "must be positive")
+		}
+		if ind.Period <= 0 {
+			fail(field+".period", "must be positive")
+		}
+	}
+	alertNames := make(map[string]bool)
+	for i, a := range c.Alerts {
+		field := fmt.Sprintf("alerts[%d]", i)
+		r, err := NewAlertRule(a.Name, a.Rule, a.Hysteresis, a.Cooldown)
+		if err != nil {
+			fail(field+".rule", "%v", err)
+			continue
+		}
+		if alertNames[r.Name] {
+			fail(field+".name", "duplicate name %q", r.Name)
+		}
+		alertNames[r.Name] = true
+	}
+	return errors.Join(errs...)
+}
+
+// Credentials возвращает ключ и секрет из переменных, указанных в настройках.
+func (c Config) Credentials(getenv func(string) string) (key, secret string) {
+	if c.Exchange.APIKeyEnv != "" {
+		key = getenv(c.Exchange.APIKeyEnv)
+	}
+	if c.Exchange.APISecretEnv != "" {
+		secret = getenv(c.Exchange.APISecretEnv)
+	}
+	return key, secret
+}
+
+// ExmoOptions переводит настройки биржи в опции NewExmo.
+func (c Config) ExmoOptions(getenv func(string) string) []exmo.Option {
+	opts := []exmo.Option{
+		exmo.WithBaseURL(c.Exchange.BaseURL),
+		exmo.WithTimeout(c.Exchange.Timeout),
+		exmo.WithRetry(c.retryPolicy()),
+		exmo.WithRateLimit(c.Exchange.RateLimit.RequestsPerMinute, c.Exchange.RateLimit.Burst),
+	}
+	if key, secret := c.Credentials(getenv); key != "" && secret != "" {
+		opts = append(opts, exmo.WithAPIKey(key, secret))
+	}
+	return opts
+}
+
+func (c Config) retryPolicy() exmo.RetryPolicy {
+	return exmo.RetryPolicy{
+		MaxAttempts: c.Exchange.Retry.MaxAttempts,
+		Backoff:     c.Exchange.Retry.Backoff,
+		MaxBackoff:  c.Exchange.Retry.MaxBackoff,
+	}
+}
+
+// NewClient создает клиента биржи из exchange.venue без хранилища и кэша.
+// Повторы, лимит запросов и метрики m (nil - без метрик) действуют для обеих
+// бирж. Ключи API к Binance не применяются: адаптер работает только
+// с публичным API. Для paper возвращается клиент Exmo - источник рыночных
+// данных бумажной биржи.
+func (c Config) NewClient(getenv func(string) string, m *telemetry.Metrics) exmo.Exchanger {
+	if c.Exchange.Venue == VenueBinance {
+		return binance.NewClient(
+			binance.WithBaseURL(c.Exchange.BaseURL),
+			binance.WithTimeout(c.Exchange.Timeout),
+			binance.WithRetry(c.retryPolicy()),
+			binance.WithRateLimit(c.Exchange.RateLimit.RequestsPerMinute, c.Exchange.RateLimit.Burst),
+			binance.WithMetrics(m),
+		)
+	}
+	return exmo.NewExmo(append(c.ExmoOptions(getenv), exmo.WithMetrics(m))...)
+}
+
+// CandleStoreDir возвращает каталог свечей биржи. Свечи Exmo лежат прямо
+// в store_dir, как до появления других бирж, остальные - в подкаталогах.
+func (c Config) CandleStoreDir() string {
+	if c.Exchange.Venue == "" || c.Exchange.Venue == VenueExmo || c.Exchange.Venue == VenuePaper {
+		return c.StoreDir
+	}
+	return filepath.Join(c.StoreDir, c.Exchange.Venue)
+}
+
+// NewExchanger собирает клиента с локальным хранилищем свечей и кэшем.
+// Запросы к бирже учитываются в globalMetrics. Для paper поверх него
+// работает paper.PaperExchange с балансами и комиссиями из exchange.paper.
+func (c Config) NewExchanger(getenv func(string) string) exmo.Exchanger {
+	client := c.NewClient(getenv, globalMetrics)
+	ex := exmo.NewCachingExchanger(candlestore.NewStoreExchanger(client, candlestore.NewCandleStore(c.CandleStoreDir())))
+	if c.Exchange.Venue != VenuePaper {
+		return ex
+	}
+	opts := []paper.PaperOption{paper.WithPaperFees(c.Exchange.Paper.TakerFee, c.Exchange.Paper.MakerFee)}
+	for currency, amount := range c.Exchange.Paper.Balances {
+		opts = append(opts, paper.WithPaperBalance(currency, amount))
+	}
+	return paper.NewPaperExchange(ex, opts...)
+}
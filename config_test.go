@@ -0,0 +1,294 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance/binancetest"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/paper"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+const testConfigYAML = `
+default_profile: prod
+exchange:
+  timeout: 5s
+  retry:
+    max_attempts: 4
+    backoff: 100ms
+  rate_limit:
+    requests_per_minute: 120
+pairs: [BTC_USD, ETH_USD]
+indicators:
+  - name: btc-fast
+    kind: ema
+    pair: BTC_USD
+    resolution: 15
+    period: 9
+profiles:
+  prod: {}
+  sandbox:
+    exchange:
+      base_url: https://sandbox.example.com/v1
+      api_key_env: SANDBOX_KEY
+      api_secret_env: SANDBOX_SECRET
+    pairs: [LTC_USD]
+`
+
+func envMap(m map[string]string) func(string) string {
+	return func(k string) string { return m[k] }
+}
+
+func TestParseConfig_Profiles(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfigYAML), "", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "prod", cfg.Profile)
+	assert.Equal(t, "https://api.exmo.com/v1", cfg.Exchange.BaseURL)
+	assert.Equal(t, 5*time.Second, cfg.Exchange.Timeout)
+	assert.Equal(t, 4, cfg.Exchange.Retry.MaxAttempts)
+	// Не заданные в файле поля сохраняют значения по умолчанию
+	assert.Equal(t, 5*time.Second, cfg.Exchange.Retry.MaxBackoff)
+	assert.Equal(t, []string{"BTC_USD", "ETH_USD"}, cfg.Pairs)
+	require.Len(t, cfg.Indicators, 1)
+	assert.Equal(t, 9, cfg.Indicators[0].Period)
+
+	cfg, err = ParseConfig([]byte(testConfigYAML), "", envMap(map[string]string{"EXMO_PROFILE": "sandbox"}))
+	require.NoError(t, err)
+	assert.Equal(t, "sandbox", cfg.Profile)
+	assert.Equal(t, "https://sandbox.example.com/v1", cfg.Exchange.BaseURL)
+	assert.Equal(t, 5*time.Second, cfg.Exchange.Timeout)
+	assert.Equal(t, []string{"LTC_USD"}, cfg.Pairs)
+
+	key, secret := cfg.Credentials(envMap(map[string]string{"SANDBOX_KEY": "k", "SANDBOX_SECRET": "s"}))
+	assert.Equal(t, "k", key)
+	assert.Equal(t, "s", secret)
+
+	_, err = ParseConfig([]byte(testConfigYAML), "staging", envMap(nil))
+	assert.EqualError(t, err, `unknown profile "staging", available: prod, sandbox`)
+}
+
+func TestParseConfig_Env(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfigYAML), "prod", envMap(map[string]string{
+		"EXMO_BASE_URL":           "http://localhost:8080",
+		"EXMO_TIMEOUT":            "1m",
+		"EXMO_RETRY_MAX_ATTEMPTS": "1",
+		"EXMO_RETRY_MAX_BACKOFF":  "2s",
+		"EXMO_RATE_LIMIT":         "0",
+		"EXMO_PAIRS":              "XRP_USD",
+	}))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8080", cfg.Exchange.BaseURL)
+	assert.Equal(t, time.Minute, cfg.Exchange.Timeout)
+	assert.Equal(t, 1, cfg.Exchange.Retry.MaxAttempts)
+	assert.Equal(t, 2*time.Second, cfg.Exchange.Retry.MaxBackoff)
+	assert.Equal(t, 0, cfg.Exchange.RateLimit.RequestsPerMinute)
+	assert.Equal(t, []string{"XRP_USD"}, cfg.Pairs)
+
+	_, err = ParseConfig(nil, "", envMap(map[string]string{"EXMO_TIMEOUT": "soon"}))
+	assert.EqualError(t, err, `EXMO_TIMEOUT: invalid duration "soon"`)
+}
+
+func TestParseConfig_Validation(t *testing.T) {
+	cfg, err := ParseConfig(nil, "", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, DefaultConfig(), cfg)
+
+	_, err = ParseConfig([]byte(`
+exchange:
+  base_url: api.exmo.com
+  retry:
+    max_attempts: -1
+pairs: [BTCUSD]
+indicators:
+  - name: a
+    kind: rsi
+    pair: BTC_USD
+    resolution: 0
+    period: 5
+`), "", envMap(nil))
+	require.Error(t, err)
+	for _, want := range []string{
+		`exchange.base_url: must be an absolute http(s) URL, got "api.exmo.com"`,
+		"exchange.retry.max_attempts: must not be negative",
+		`pairs[0]: invalid pair "BTCUSD"`,
+		`indicators[0].kind: must be sma or ema, got "rsi"`,
+		"indicators[0].resolution: must be positive",
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+
+	_, err = ParseConfig([]byte("exchange:\n  base_ulr: http://x\n"), "", envMap(nil))
+	assert.ErrorContains(t, err, "field base_ulr not found")
+
+	_, err = ParseConfig([]byte("profiles:\n  dev:\n    pairs: [x]\n"), "dev", envMap(nil))
+	assert.ErrorContains(t, err, `profile "dev": pairs[0]`)
+}
+
+func TestParseConfig_Venue(t *testing.T) {
+	cfg, err := ParseConfig(nil, "", envMap(map[string]string{"EXMO_VENUE": "binance", "EXMO_STORE_DIR": "/tmp/store"}))
+	require.NoError(t, err)
+	assert.Equal(t, VenueBinance, cfg.Exchange.Venue)
+	assert.Equal(t, binance.DefaultBaseURL, cfg.Exchange.BaseURL)
+	assert.Equal(t, filepath.Join("/tmp/store", "binance"), cfg.CandleStoreDir())
+	assert.IsType(t, &binance.Client{}, cfg.NewClient(envMap(nil), nil))
+
+	// Явно заданный адрес сохраняется
+	cfg, err = ParseConfig([]byte("exchange:\n  venue: binance\n  base_url: http://localhost:9000\n"), "", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:9000", cfg.Exchange.BaseURL)
+
+	cfg = DefaultConfig()
+	assert.Equal(t, cfg.StoreDir, cfg.CandleStoreDir())
+	assert.IsType(t, &exmo.Exmo{}, cfg.NewClient(envMap(nil), nil))
+
+	_, err = ParseConfig([]byte("exchange:\n  venue: kraken\n"), "", envMap(nil))
+	assert.ErrorContains(t, err, `exchange.venue: must be exmo, binance or paper, got "kraken"`)
+}
+
+func TestConfig_NewExchanger_Paper(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.AddOrder("BTC_USD", exmo.Sell, 100, 1)
+
+	cfg, err := ParseConfig([]byte(`
+store_dir: `+filepath.Join(t.TempDir(), "store")+`
+exchange:
+  venue: paper
+  base_url: `+fake.URL()+`
+  paper:
+    balances: {USD: 1000}
+    taker_fee: 0
+`), "", envMap(nil))
+	require.NoError(t, err)
+	assert.Equal(t, cfg.StoreDir, cfg.CandleStoreDir(), "свечи берутся у Exmo")
+	assert.IsType(t, &exmo.Exmo{}, cfg.NewClient(envMap(nil), nil))
+
+	ex := cfg.NewExchanger(envMap(nil))
+	require.IsType(t, &paper.PaperExchange{}, ex)
+	trader := ex.(*paper.PaperExchange)
+	_, err = trader.CreateOrder("BTC_USD", 1, 0, exmo.OrderMarketBuy)
+	require.NoError(t, err)
+	info, err := trader.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "1", info.Balances["BTC"])
+	assert.Equal(t, "900", info.Balances["USD"])
+	assert.Zero(t, fake.Requests("/order_create"), "ордера не уходят на биржу")
+
+	_, err = ParseConfig([]byte("exchange:\n  venue: paper\n  paper:\n    balances: {USD: -1}\n    maker_fee: 1\n"), "", envMap(nil))
+	assert.ErrorContains(t, err, "exchange.paper.maker_fee: must be in [0, 1), got 1")
+	assert.ErrorContains(t, err, "exchange.paper.balances.USD: must not be negative")
+}
+
+func TestConfig_NewClient_BinancePolicy(t *testing.T) {
+	fake := binancetest.NewFakeBinance()
+	defer fake.Close()
+	fake.Fail("/api/v3/exchangeInfo", http.StatusBadGateway, 0, "")
+
+	cfg, err := ParseConfig([]byte(`
+exchange:
+  venue: binance
+  base_url: `+fake.URL()+`
+  retry:
+    max_attempts: 3
+    backoff: 1ms
+`), "", envMap(nil))
+	require.NoError(t, err)
+
+	m := telemetry.NewMetrics()
+	_, err = cfg.NewClient(envMap(nil), m).GetTicker()
+	require.Error(t, err)
+	assert.Equal(t, 3, fake.Requests("/api/v3/exchangeInfo"), "retry settings apply to binance")
+	var b strings.Builder
+	require.NoError(t, m.WritePrometheus(&b))
+	assert.Contains(t, b.String(), `exmo_retries_total{endpoint="/api/v3/exchangeInfo"} 2`)
+}
+
+func TestRun_Config(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetCurrencies("BTC", "USD")
+
+	path := filepath.Join(t.TempDir(), "exmo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+store_dir: `+filepath.Join(t.TempDir(), "store")+`
+profiles:
+  local:
+    exchange:
+      base_url: `+fake.URL()+`
+`), 0o644))
+
+	original := globalExchanger
+	code, stdout, stderr := runCLI("-config", path, "-profile", "local", "currencies", "-output", "csv")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "currency\nBTC\nUSD\n", stdout)
+	assert.Equal(t, 1, fake.Requests("/currency"))
+	// Настройки действуют только на время запуска
+	assert.Equal(t, original, globalExchanger)
+
+	code, _, stderr = runCLI("-config", path, "-profile", "missing", "currencies")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `unknown profile "missing"`)
+
+	code, _, _ = runCLI("-profile", "local", "currencies")
+	assert.Equal(t, exitUsage, code)
+}
+
+func TestRun_EnvWithoutConfig(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetCurrencies("BTC", "USD")
+
+	t.Setenv("EXMO_CONFIG", "")
+	t.Setenv("EXMO_BASE_URL", fake.URL())
+	t.Setenv("EXMO_STORE_DIR", t.TempDir())
+
+	original := globalExchanger
+	code, stdout, stderr := runCLI("currencies", "-output", "csv")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Equal(t, "currency\nBTC\nUSD\n", stdout)
+	assert.Equal(t, 1, fake.Requests("/currency"))
+	assert.Equal(t, original, globalExchanger)
+
+	t.Setenv("EXMO_TIMEOUT", "soon")
+	code, _, stderr = runCLI("currencies")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, `EXMO_TIMEOUT: invalid duration "soon"`)
+}
+
+func TestRun_Venue(t *testing.T) {
+	fake := binancetest.NewFakeBinance()
+	defer fake.Close()
+	fake.AddSymbol("BTC", "USDT")
+	fake.SetTicker("BTCUSDT", binancetest.FakeTicker{Bid: 50000, Ask: 50010, Last: 50005})
+	fake.SetBook("BTCUSDT", []exmo.BookLevel{{Price: 50000, Quantity: 1}}, []exmo.BookLevel{{Price: 50010, Quantity: 2}})
+
+	// Стратегии и пары те же, что для Exmo: меняется только профиль
+	path := filepath.Join(t.TempDir(), "exmo.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+store_dir: `+filepath.Join(t.TempDir(), "store")+`
+pairs: [BTC_USD]
+profiles:
+  binance:
+    exchange:
+      venue: binance
+      base_url: `+fake.URL()+`
+`), 0o644))
+
+	code, stdout, stderr := runCLI("-config", path, "-profile", "binance", "ticker", "-pair", "BTC_USD", "-output", "csv")
+	assert.Equal(t, exitOK, code, stderr)
+	assert.Contains(t, stdout, "BTC_USD,50000,50010,50005")
+
+	code, _, stderr = runCLI("-config", path, "-profile", "binance", "ticker", "-pair", "XRP_USD")
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, stderr, "invalid pair: XRP_USD")
+}
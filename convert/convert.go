@@ -0,0 +1,226 @@
+// Package convert строит граф курсов по тикеру биржи и находит лучший
+// курс обмена между валютами, в том числе через промежуточные пары.
+package convert
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// ConversionHop - один шаг пути конвертации. Side - сторона сделки в паре:
+// продажа базовой валюты идет по bid (buy_price), покупка - по ask (sell_price).
+type ConversionHop struct {
+	Pair  string    `json:"pair"`
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Side  exmo.Type `json:"side"`
+	Price float64   `json:"price"`
+	Rate  float64   `json:"rate"`
+}
+
+// ConversionQuote - итоговый курс и путь, по которому он получен.
+type ConversionQuote struct {
+	From string          `json:"from"`
+	To   string          `json:"to"`
+	Rate float64         `json:"rate"`
+	Path []ConversionHop `json:"path"`
+}
+
+// String объясняет, какие пары и курсы использованы.
+func (q ConversionQuote) String() string {
+	if len(q.Path) == 0 {
+		return fmt.Sprintf("%s -> %s: 1 (same currency)", q.From, q.To)
+	}
+	steps := make([]string, len(q.Path))
+	for i, h := range q.Path {
+		steps[i] = fmt.Sprintf("%s %s %s @ %s", h.Side, h.Pair, h.From+"->"+h.To, formatFloat(h.Price))
+	}
+	return fmt.Sprintf("%s -> %s: %s via %s", q.From, q.To, formatFloat(q.Rate), strings.Join(steps, ", "))
+}
+
+type conversionEdge struct {
+	to    string
+	pair  string
+	side  exmo.Type
+	price float64
+	rate  float64
+}
+
+// ConversionGraph строит граф валют из пар тикера и находит лучший курс
+// между любыми двумя валютами с ограничением на число шагов.
+type ConversionGraph struct {
+	mu      sync.RWMutex
+	edges   map[string][]conversionEdge
+	maxHops int
+	updated time.Time
+}
+
+type ConversionOption func(*ConversionGraph)
+
+// WithMaxHops ограничивает длину пути конвертации.
+func WithMaxHops(n int) ConversionOption {
+	return func(g *ConversionGraph) {
+		g.maxHops = n
+	}
+}
+
+func NewConversionGraph(ticker exmo.Ticker, opts ...ConversionOption) *ConversionGraph {
+	g := &ConversionGraph{maxHops: 3}
+	for _, opt := range opts {
+		opt(g)
+	}
+	g.Update(ticker)
+	return g
+}
+
+// Update перестраивает граф по новому тикеру. Если у пары нет bid или ask,
+// используется цена последней сделки.
+func (g *ConversionGraph) Update(ticker exmo.Ticker) {
+	edges := make(map[string][]conversionEdge)
+	for pair, value := range ticker {
+		base, quote, err := exmo.SplitPair(pair)
+		if err != nil {
+			continue
+		}
+		last := parsePrice(value.LastTrade)
+		bid := parsePrice(value.BuyPrice)
+		if bid == 0 {
+			bid = last
+		}
+		ask := parsePrice(value.SellPrice)
+		if ask == 0 {
+			ask = last
+		}
+		if bid > 0 {
+			edges[base] = append(edges[base], conversionEdge{to: quote, pair: pair, side: exmo.Sell, price: bid, rate: bid})
+		}
+		if ask > 0 {
+			edges[quote] = append(edges[quote], conversionEdge{to: base, pair: pair, side: exmo.Buy, price: ask, rate: 1 / ask})
+		}
+	}
+	for _, list := range edges {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].to != list[j].to {
+				return list[i].to < list[j].to
+			}
+			return list[i].pair < list[j].pair
+		})
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.edges = edges
+	g.updated = time.Now()
+}
+
+// Refresh загружает свежий тикер с биржи и перестраивает граф.
+func (g *ConversionGraph) Refresh(ex exmo.Exchanger) error {
+	ticker, err := ex.GetTicker()
+	if err != nil {
+		return err
+	}
+	g.Update(ticker)
+	return nil
+}
+
+// Updated возвращает время последнего обновления графа.
+func (g *ConversionGraph) Updated() time.Time {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.updated
+}
+
+// Currencies возвращает отсортированный список валют, присутствующих в графе.
+func (g *ConversionGraph) Currencies() []string {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	seen := make(map[string]struct{})
+	for from, list := range g.edges {
+		seen[from] = struct{}{}
+		for _, e := range list {
+			seen[e.to] = struct{}{}
+		}
+	}
+	result := make([]string, 0, len(seen))
+	for c := range seen {
+		result = append(result, c)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// Quote ищет путь с максимальным итоговым курсом from -> to. При равных
+// курсах выбирается более короткий путь.
+func (g *ConversionGraph) Quote(from, to string) (ConversionQuote, error) {
+	if from == to {
+		return ConversionQuote{From: from, To: to, Rate: 1}, nil
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	best := ConversionQuote{From: from, To: to}
+	visited := map[string]bool{from: true}
+	var path []ConversionHop
+
+	var walk func(node string, rate float64)
+	walk = func(node string, rate float64) {
+		if node == to {
+			if rate > best.Rate || (rate == best.Rate && len(path) < len(best.Path)) {
+				best.Rate = rate
+				best.Path = append([]ConversionHop(nil), path...)
+			}
+			return
+		}
+		if len(path) >= g.maxHops {
+			return
+		}
+		for _, e := range g.edges[node] {
+			if visited[e.to] {
+				continue
+			}
+			visited[e.to] = true
+			path = append(path, ConversionHop{Pair: e.pair, From: node, To: e.to, Side: e.side, Price: e.price, Rate: e.rate})
+			walk(e.to, rate*e.rate)
+			path = path[:len(path)-1]
+			visited[e.to] = false
+		}
+	}
+	walk(from, 1)
+
+	if best.Path == nil {
+		return ConversionQuote{}, fmt.Errorf("no conversion path from %s to %s within %d hops", from, to, g.maxHops)
+	}
+	return best, nil
+}
+
+// Convert переводит сумму из одной валюты в другую по лучшему пути.
+func (g *ConversionGraph) Convert(amount float64, from, to string) (float64, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	q, err := g.Quote(from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * q.Rate, nil
+}
+
+func parsePrice(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
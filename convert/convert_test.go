@@ -0,0 +1,110 @@
+package convert
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func conversionTicker() exmo.Ticker {
+	return exmo.Ticker{
+		"BTC_USDT": {BuyPrice: "60000", SellPrice: "60100", LastTrade: "60050"},
+		"ETH_BTC":  {BuyPrice: "0.05", SellPrice: "0.051", LastTrade: "0.0505"},
+		"USDT_RUB": {BuyPrice: "90", SellPrice: "91", LastTrade: "90.5"},
+		"USD_RUB":  {BuyPrice: "89", SellPrice: "92", LastTrade: "90"},
+		"BTC_USD":  {LastTrade: "59000"},
+	}
+}
+
+func TestConversionGraph_Quote(t *testing.T) {
+	g := NewConversionGraph(conversionTicker())
+
+	t.Run("direct pair uses bid", func(t *testing.T) {
+		q, err := g.Quote("BTC", "USDT")
+		require.NoError(t, err)
+		assert.Equal(t, 60000.0, q.Rate)
+		require.Len(t, q.Path, 1)
+		assert.Equal(t, exmo.Sell, q.Path[0].Side)
+	})
+
+	t.Run("inverse pair uses ask", func(t *testing.T) {
+		q, err := g.Quote("USDT", "BTC")
+		require.NoError(t, err)
+		assert.InDelta(t, 1/60100.0, q.Rate, 1e-15)
+		assert.Equal(t, exmo.Buy, q.Path[0].Side)
+	})
+
+	t.Run("multi hop", func(t *testing.T) {
+		q, err := g.Quote("ETH", "RUB")
+		require.NoError(t, err)
+		assert.InDelta(t, 0.05*60000*90, q.Rate, 1e-6)
+		require.Len(t, q.Path, 3)
+		assert.Equal(t, []string{"ETH_BTC", "BTC_USDT", "USDT_RUB"}, []string{q.Path[0].Pair, q.Path[1].Pair, q.Path[2].Pair})
+		assert.Contains(t, q.String(), "sell ETH_BTC ETH->BTC @ 0.05")
+	})
+
+	t.Run("best of several paths", func(t *testing.T) {
+		// Через USDT выгоднее, чем через BTC_USD по последней сделке
+		q, err := g.Quote("BTC", "RUB")
+		require.NoError(t, err)
+		assert.InDelta(t, 60000*90.0, q.Rate, 1e-6)
+	})
+
+	t.Run("same currency", func(t *testing.T) {
+		q, err := g.Quote("BTC", "BTC")
+		require.NoError(t, err)
+		assert.Equal(t, 1.0, q.Rate)
+		assert.Empty(t, q.Path)
+	})
+
+	t.Run("max hops", func(t *testing.T) {
+		short := NewConversionGraph(conversionTicker(), WithMaxHops(2))
+		_, err := short.Quote("ETH", "RUB")
+		assert.Error(t, err)
+	})
+
+	t.Run("unknown currency", func(t *testing.T) {
+		_, err := g.Quote("DOGE", "USD")
+		assert.Error(t, err)
+	})
+}
+
+func TestConversionGraph_Convert(t *testing.T) {
+	g := NewConversionGraph(conversionTicker())
+
+	v, err := g.Convert(2, "BTC", "USDT")
+	assert.NoError(t, err)
+	assert.Equal(t, 120000.0, v)
+
+	v, err = g.Convert(0, "DOGE", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 0.0, v)
+
+	assert.Contains(t, g.Currencies(), "RUB")
+}
+
+func TestConversionGraph_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	g := NewConversionGraph(nil)
+	_, err := g.Quote("BTC", "USD")
+	assert.Error(t, err)
+
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {BuyPrice: "100", SellPrice: "101"}}, nil)
+	require.NoError(t, g.Refresh(mockExchanger))
+	q, err := g.Quote("BTC", "USD")
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, q.Rate)
+	assert.False(t, g.Updated().IsZero())
+
+	mockExchanger.EXPECT().GetTicker().Return(nil, errors.New("exchange error"))
+	assert.Error(t, g.Refresh(mockExchanger))
+}
@@ -0,0 +1,475 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+)
+
+// Dashboard - живая сводка по рынку для терминала: лидеры изменения по
+// тикеру, стакан и сделки выбранной пары, текущие значения индикаторов.
+type Dashboard struct {
+	ex          exmo.Exchanger
+	pairs       []string
+	interval    time.Duration
+	budget      int
+	depth       int
+	tradesLimit int
+	moversLimit int
+	resolution  int
+	period      int
+	color       bool
+	now         func() time.Time
+
+	mu      sync.Mutex
+	current int
+}
+
+// DashboardView - данные одного обновления сводки.
+type DashboardView struct {
+	Pair       string
+	Index      int
+	Total      int
+	Updated    time.Time
+	Interval   time.Duration
+	Movers     []Mover
+	Asks       []exmo.BookLevel
+	Bids       []exmo.BookLevel
+	Trades     []exmo.Pair
+	Resolution int
+	Period     int
+	SMA        float64 // NaN, если данных недостаточно
+	EMA        float64
+	Errors     []string
+}
+
+// Mover - изменение цены пары относительно средней за 24 часа.
+type Mover struct {
+	Pair   string
+	Last   float64
+	Avg    float64
+	Change float64 // в процентах
+}
+
+type DashboardOption func(*Dashboard)
+
+// WithDashboardInterval задает желаемый интервал обновления.
+func WithDashboardInterval(d time.Duration) DashboardOption {
+	return func(db *Dashboard) {
+		db.interval = d
+	}
+}
+
+// WithDashboardBudget ограничивает число запросов к бирже в минуту.
+// Интервал обновления увеличивается, если иначе бюджет будет превышен.
+func WithDashboardBudget(perMinute int) DashboardOption {
+	return func(db *Dashboard) {
+		db.budget = perMinute
+	}
+}
+
+func WithDashboardDepth(depth int) DashboardOption {
+	return func(db *Dashboard) {
+		db.depth = depth
+	}
+}
+
+func WithDashboardIndicator(resolution, period int) DashboardOption {
+	return func(db *Dashboard) {
+		db.resolution = resolution
+		db.period = period
+	}
+}
+
+// WithDashboardColor включает ANSI-цвета.
+func WithDashboardColor(color bool) DashboardOption {
+	return func(db *Dashboard) {
+		db.color = color
+	}
+}
+
+func WithDashboardClock(now func() time.Time) DashboardOption {
+	return func(db *Dashboard) {
+		db.now = now
+	}
+}
+
+func NewDashboard(ex exmo.Exchanger, pairs []string, opts ...DashboardOption) (*Dashboard, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("at least one pair is required")
+	}
+	db := &Dashboard{
+		ex:          ex,
+		pairs:       pairs,
+		interval:    5 * time.Second,
+		budget:      60,
+		depth:       10,
+		tradesLimit: 10,
+		moversLimit: 8,
+		resolution:  30,
+		period:      5,
+		color:       true,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(db)
+	}
+	if db.depth <= 0 || db.resolution <= 0 || db.period <= 0 {
+		return nil, fmt.Errorf("depth, resolution and period must be positive")
+	}
+	return db, nil
+}
+
+// RequestsPerRefresh - число запросов к бирже за одно обновление:
+// тикер, стакан, сделки и свечи для индикаторов.
+func (d *Dashboard) RequestsPerRefresh() int {
+	return 4
+}
+
+// Interval возвращает интервал обновления с учетом бюджета запросов.
+func (d *Dashboard) Interval() time.Duration {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.intervalLocked()
+}
+
+func (d *Dashboard) intervalLocked() time.Duration {
+	if minimal := d.minGap(); minimal > d.interval {
+		return minimal
+	}
+	return d.interval
+}
+
+// Pair возвращает выбранную пару.
+func (d *Dashboard) Pair() string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.pairs[d.current]
+}
+
+// Next и Prev переключают пару по кругу.
+func (d *Dashboard) Next() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.current = (d.current + 1) % len(d.pairs)
+}
+
+func (d *Dashboard) Prev() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.current = (d.current + len(d.pairs) - 1) % len(d.pairs)
+}
+
+// Select выбирает пару по номеру с нуля. Номера вне списка игнорируются.
+func (d *Dashboard) Select(i int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if i >= 0 && i < len(d.pairs) {
+		d.current = i
+	}
+}
+
+// AdjustInterval меняет желаемый интервал, но не ниже одной секунды.
+func (d *Dashboard) AdjustInterval(delta time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.interval += delta
+	if d.interval < time.Second {
+		d.interval = time.Second
+	}
+}
+
+// Refresh запрашивает данные для выбранной пары. Ошибка одного раздела
+// не мешает показать остальные и попадает в DashboardView.Errors.
+func (d *Dashboard) Refresh() DashboardView {
+	d.mu.Lock()
+	view := DashboardView{
+		Pair:       d.pairs[d.current],
+		Index:      d.current,
+		Total:      len(d.pairs),
+		Updated:    d.now(),
+		Interval:   d.intervalLocked(),
+		Resolution: d.resolution,
+		Period:     d.period,
+		SMA:        math.NaN(),
+		EMA:        math.NaN(),
+	}
+	d.mu.Unlock()
+
+	fail := func(section string, err error) {
+		view.Errors = append(view.Errors, fmt.Sprintf("%s: %v", section, err))
+	}
+
+	if ticker, err := d.ex.GetTicker(); err != nil {
+		fail("ticker", err)
+	} else {
+		view.Movers = topMovers(ticker, d.moversLimit)
+	}
+
+	if book, err := d.ex.GetOrderBook(d.depth, view.Pair); err != nil {
+		fail("order book", err)
+	} else {
+		var askErr, bidErr error
+		view.Asks, askErr = book[view.Pair].Asks()
+		view.Bids, bidErr = book[view.Pair].Bids()
+		if askErr != nil || bidErr != nil {
+			fail("order book", fmt.Errorf("asks: %v, bids: %v", askErr, bidErr))
+		}
+	}
+
+	if trades, err := d.ex.GetTrades(view.Pair); err != nil {
+		fail("trades", err)
+	} else {
+		view.Trades = trades[view.Pair]
+		if len(view.Trades) > d.tradesLimit {
+			view.Trades = view.Trades[:d.tradesLimit]
+		}
+	}
+
+	// Берем свечи с запасом, чтобы EMA успела сойтись
+	span := time.Duration(d.resolution*d.period*3) * time.Minute
+	if closes, err := d.ex.GetClosePrice(view.Pair, d.resolution, view.Updated.Add(-span), view.Updated); err != nil {
+		fail("indicators", err)
+	} else {
+		if sma := indicator.CalculateSMA(closes, d.period); len(sma) > 0 {
+			view.SMA = sma[len(sma)-1]
+		}
+		if ema := indicator.CalculateEMA(closes, d.period); len(ema) > 0 {
+			view.EMA = ema[len(ema)-1]
+		}
+	}
+	return view
+}
+
+// topMovers сортирует пары по модулю изменения последней цены к средней за 24 часа.
+func topMovers(ticker exmo.Ticker, limit int) []Mover {
+	movers := make([]Mover, 0, len(ticker))
+	for pair, v := range ticker {
+		last, avg := parsePrice(v.LastTrade), parsePrice(v.Avg)
+		if last == 0 || avg == 0 {
+			continue
+		}
+		movers = append(movers, Mover{Pair: pair, Last: last, Avg: avg, Change: (last - avg) / avg * 100})
+	}
+	sort.Slice(movers, func(i, j int) bool {
+		ci, cj := math.Abs(movers[i].Change), math.Abs(movers[j].Change)
+		if ci != cj {
+			return ci > cj
+		}
+		return movers[i].Pair < movers[j].Pair
+	})
+	if limit > 0 && len(movers) > limit {
+		movers = movers[:limit]
+	}
+	return movers
+}
+
+// ANSI-последовательности для отрисовки.
+const (
+	ansiClear = "\x1b[H\x1b[2J"
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiDim   = "\x1b[2m"
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+)
+
+// Render отрисовывает сводку, начиная с очистки экрана.
+func (d *Dashboard) Render(w io.Writer, view DashboardView) error {
+	paint := func(code, s string) string {
+		if !d.color {
+			return s
+		}
+		return code + s + ansiReset
+	}
+	signed := func(v float64, s string) string {
+		switch {
+		case v > 0:
+			return paint(ansiGreen, s)
+		case v < 0:
+			return paint(ansiRed, s)
+		}
+		return s
+	}
+
+	var b strings.Builder
+	b.WriteString(ansiClear)
+	fmt.Fprintf(&b, "%s  [%d/%d]  %s  обновление каждые %s\n\n",
+		paint(ansiBold, "EXMO "+view.Pair), view.Index+1, view.Total,
+		view.Updated.Format("15:04:05"), view.Interval)
+
+	b.WriteString(paint(ansiBold, "Изменение к средней за 24 ч") + "\n")
+	for _, m := range view.Movers {
+		change := fmt.Sprintf("%+7.2f%%", m.Change)
+		fmt.Fprintf(&b, "  %-12s %16s %s\n", m.Pair, humanNumber(m.Last), signed(m.Change, change))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", paint(ansiBold, "Стакан"))
+	fmt.Fprintf(&b, "  %16s %16s\n", "PRICE", "QUANTITY")
+	for i := len(view.Asks) - 1; i >= 0; i-- {
+		l := view.Asks[i]
+		fmt.Fprintf(&b, "  %s %16s\n", paint(ansiRed, fmt.Sprintf("%16s", humanNumber(l.Price))), humanNumber(l.Quantity))
+	}
+	if len(view.Asks) > 0 && len(view.Bids) > 0 {
+		spread := view.Asks[0].Price - view.Bids[0].Price
+		fmt.Fprintf(&b, "  %s\n", paint(ansiDim, fmt.Sprintf("спред %s (%.3f%%)", humanNumber(spread), spread/view.Asks[0].Price*100)))
+	}
+	for _, l := range view.Bids {
+		fmt.Fprintf(&b, "  %s %16s\n", paint(ansiGreen, fmt.Sprintf("%16s", humanNumber(l.Price))), humanNumber(l.Quantity))
+	}
+
+	fmt.Fprintf(&b, "\n%s\n", paint(ansiBold, "Последние сделки"))
+	for _, t := range view.Trades {
+		side := fmt.Sprintf("%-4s", t.Type)
+		if t.Type == exmo.Buy {
+			side = paint(ansiGreen, side)
+		} else {
+			side = paint(ansiRed, side)
+		}
+		fmt.Fprintf(&b, "  %s %s %16s %16s\n", time.Unix(t.Date, 0).In(view.Updated.Location()).Format("15:04:05"),
+			side, humanNumber(parsePrice(t.Price)), humanNumber(parsePrice(t.Quantity)))
+	}
+
+	fmt.Fprintf(&b, "\n%s  SMA %s  EMA %s\n",
+		paint(ansiBold, fmt.Sprintf("Индикаторы (%d мин, период %d)", view.Resolution, view.Period)),
+		indicatorValue(view.SMA), indicatorValue(view.EMA))
+
+	for _, e := range view.Errors {
+		fmt.Fprintf(&b, "%s\n", paint(ansiRed, "ошибка "+e))
+	}
+	fmt.Fprintf(&b, "\n%s\n", paint(ansiDim, "n/→ следующая  p/← предыдущая  1-9 выбор пары  +/- интервал  r обновить  q выход"))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func indicatorValue(v float64) string {
+	if math.IsNaN(v) {
+		return "-"
+	}
+	return humanNumber(v)
+}
+
+// Клавиши, распознаваемые сводкой.
+const (
+	keyNext    = "next"
+	keyPrev    = "prev"
+	keyRefresh = "refresh"
+	keyFaster  = "faster"
+	keySlower  = "slower"
+	keyQuit    = "quit"
+)
+
+// readKeys читает нажатия из терминала в сыром режиме и переводит их в команды.
+// Стрелки приходят как ESC [ C и ESC [ D.
+func readKeys(r io.Reader, keys chan<- string) {
+	defer close(keys)
+	buf := make([]byte, 16)
+	for {
+		n, err := r.Read(buf)
+		for i := 0; i < n; i++ {
+			c := buf[i]
+			if c == 0x1b && i+2 < n && buf[i+1] == '[' {
+				switch buf[i+2] {
+				case 'C':
+					keys <- keyNext
+				case 'D':
+					keys <- keyPrev
+				}
+				i += 2
+				continue
+			}
+			switch {
+			case c == 'n' || c == 'l' || c == '\t':
+				keys <- keyNext
+			case c == 'p' || c == 'h':
+				keys <- keyPrev
+			case c == 'r' || c == ' ':
+				keys <- keyRefresh
+			case c == '+' || c == '=':
+				keys <- keySlower
+			case c == '-':
+				keys <- keyFaster
+			case c == 'q' || c == 3: // 3 - Ctrl-C в сыром режиме
+				keys <- keyQuit
+			case c >= '1' && c <= '9':
+				keys <- string(c)
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// minGap - минимальный промежуток между обновлениями по бюджету запросов.
+func (d *Dashboard) minGap() time.Duration {
+	if d.budget <= 0 {
+		return 0
+	}
+	return time.Duration(d.RequestsPerRefresh()) * time.Minute / time.Duration(d.budget)
+}
+
+// Run обновляет сводку по таймеру и по нажатиям клавиш до отмены контекста
+// или команды выхода. Канал keys может быть nil. Обновления по клавишам
+// тоже укладываются в бюджет запросов: слишком частое нажатие откладывает
+// обновление до момента, когда бюджет это позволит.
+func (d *Dashboard) Run(ctx context.Context, w io.Writer, keys <-chan string) error {
+	last := d.now()
+	if err := d.Render(w, d.Refresh()); err != nil {
+		return err
+	}
+	timer := time.NewTimer(d.Interval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		case key, ok := <-keys:
+			if !ok {
+				keys = nil
+				continue
+			}
+			switch {
+			case key == keyQuit:
+				return nil
+			case key == keyNext:
+				d.Next()
+			case key == keyPrev:
+				d.Prev()
+			case key == keySlower:
+				d.AdjustInterval(time.Second)
+			case key == keyFaster:
+				d.AdjustInterval(-time.Second)
+			case len(key) == 1 && key[0] >= '1' && key[0] <= '9':
+				d.Select(int(key[0] - '1'))
+			}
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+			if wait := last.Add(d.minGap()).Sub(d.now()); wait > 0 {
+				timer.Reset(wait)
+				continue
+			}
+		}
+
+		last = d.now()
+		if err := d.Render(w, d.Refresh()); err != nil {
+			return err
+		}
+		timer.Reset(d.Interval())
+	}
+}
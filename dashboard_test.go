@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+)
+
+func TestTopMovers(t *testing.T) {
+	ticker := exmo.Ticker{
+		"BTC_USD": {LastTrade: "105", Avg: "100"},
+		"ETH_USD": {LastTrade: "90", Avg: "100"},
+		"XRP_USD": {LastTrade: "1", Avg: "1"},
+		"NEW_USD": {LastTrade: "1", Avg: ""},
+	}
+	movers := topMovers(ticker, 2)
+	require.Len(t, movers, 2)
+	assert.Equal(t, "ETH_USD", movers[0].Pair)
+	assert.InDelta(t, -10, movers[0].Change, 1e-9)
+	assert.Equal(t, "BTC_USD", movers[1].Pair)
+}
+
+func TestDashboard_Interval(t *testing.T) {
+	db, err := NewDashboard(nil, []string{"BTC_USD"}, WithDashboardInterval(time.Second), WithDashboardBudget(30))
+	require.NoError(t, err)
+	// 4 запроса при 30 в минуту - не чаще раза в 8 секунд
+	assert.Equal(t, 8*time.Second, db.Interval())
+
+	db.AdjustInterval(10 * time.Second)
+	assert.Equal(t, 11*time.Second, db.Interval())
+
+	_, err = NewDashboard(nil, nil)
+	assert.Error(t, err)
+}
+
+func TestDashboard_Navigation(t *testing.T) {
+	db, err := NewDashboard(nil, []string{"A_B", "C_D", "E_F"})
+	require.NoError(t, err)
+
+	db.Prev()
+	assert.Equal(t, "E_F", db.Pair())
+	db.Next()
+	assert.Equal(t, "A_B", db.Pair())
+	db.Select(1)
+	assert.Equal(t, "C_D", db.Pair())
+	db.Select(7)
+	assert.Equal(t, "C_D", db.Pair())
+}
+
+func TestDashboard_RefreshAndRender(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	mock.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "102", Avg: "100"}}, nil)
+	mock.EXPECT().GetOrderBook(2, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {
+		Ask: [][]string{{"101", "1", "101"}, {"102", "2", "204"}},
+		Bid: [][]string{{"99", "3", "297"}},
+	}}, nil)
+	mock.EXPECT().GetTrades("BTC_USD").Return(nil, errors.New("timeout"))
+	mock.EXPECT().GetClosePrice("BTC_USD", 60, now.Add(-9*time.Hour), now).Return([]float64{1, 2, 3, 4}, nil)
+
+	db, err := NewDashboard(mock, []string{"BTC_USD"},
+		WithDashboardDepth(2), WithDashboardIndicator(60, 3),
+		WithDashboardColor(false), WithDashboardClock(func() time.Time { return now }))
+	require.NoError(t, err)
+
+	view := db.Refresh()
+	assert.Len(t, view.Asks, 2)
+	assert.InDelta(t, 3, view.SMA, 1e-9)
+	assert.False(t, math.IsNaN(view.EMA))
+	assert.Equal(t, []string{"trades: timeout"}, view.Errors)
+
+	var buf bytes.Buffer
+	require.NoError(t, db.Render(&buf, view))
+	out := buf.String()
+	assert.True(t, strings.HasPrefix(out, ansiClear))
+	assert.NotContains(t, out, ansiGreen)
+	assert.Contains(t, out, "EXMO BTC_USD")
+	assert.Contains(t, out, "+2.00%")
+	assert.Contains(t, out, "спред 2 (1.980%)")
+	assert.Contains(t, out, "ошибка trades: timeout")
+	// Продажи идут сверху по убыванию цены
+	assert.Less(t, strings.Index(out, "102"), strings.Index(out, "101"))
+}
+
+func TestReadKeys(t *testing.T) {
+	keys := make(chan string, 16)
+	readKeys(strings.NewReader("n\x1b[Dx3+q"), keys)
+
+	var got []string
+	for k := range keys {
+		got = append(got, k)
+	}
+	assert.Equal(t, []string{keyNext, keyPrev, "3", keySlower, keyQuit}, got)
+}
+
+func TestDashboard_Run(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.AddOrder("BTC_USD", exmo.Sell, 101, 1)
+	fake.AddOrder("ETH_USD", exmo.Sell, 11, 1)
+	fake.SetCandles("BTC_USD", nil)
+	fake.SetCandles("ETH_USD", nil)
+
+	db, err := NewDashboard(fake.Client(), []string{"BTC_USD", "ETH_USD"},
+		WithDashboardColor(false), WithDashboardBudget(0), WithDashboardInterval(time.Hour))
+	require.NoError(t, err)
+
+	keys := make(chan string)
+	var buf bytes.Buffer
+	done := make(chan error)
+	go func() { done <- db.Run(context.Background(), &buf, keys) }()
+
+	keys <- keyNext
+	keys <- keyQuit
+	require.NoError(t, <-done)
+
+	frames := strings.Split(buf.String(), ansiClear)
+	require.Len(t, frames, 3)
+	assert.Contains(t, frames[1], "EXMO BTC_USD  [1/2]")
+	assert.Contains(t, frames[2], "EXMO ETH_USD  [2/2]")
+	assert.Equal(t, 2, fake.Requests("/ticker"))
+}
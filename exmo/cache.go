@@ -0,0 +1,304 @@
+package exmo
+
+import (
+	"container/list"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheTTLs задает время жизни ответов по методам. Нулевое значение
+// отключает кэширование метода. Свечи по полностью закрытому интервалу
+// не меняются и хранятся без срока, Candles относится к открытым интервалам.
+type CacheTTLs struct {
+	Ticker       time.Duration
+	Trades       time.Duration
+	OrderBook    time.Duration
+	Currencies   time.Duration
+	PairSettings time.Duration
+	Candles      time.Duration
+}
+
+// DefaultCacheTTLs - сроки жизни кэша по умолчанию.
+var DefaultCacheTTLs = CacheTTLs{
+	Ticker:       5 * time.Second,
+	Trades:       5 * time.Second,
+	OrderBook:    2 * time.Second,
+	Currencies:   6 * time.Hour,
+	PairSettings: time.Hour,
+	Candles:      time.Minute,
+}
+
+// DefaultCacheMaxEntries - число ответов в кэше по умолчанию.
+const DefaultCacheMaxEntries = 1024
+
+// cacheSweepInterval - как часто из кэша удаляются истекшие ответы.
+const cacheSweepInterval = time.Minute
+
+// CacheStats - счетчики по одному методу.
+type CacheStats struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+	Shared uint64 `json:"shared"`
+	Errors uint64 `json:"errors"`
+}
+
+type cacheEntry struct {
+	key     string
+	value   interface{}
+	expires time.Time // нулевое время - без срока
+}
+
+type cacheCall struct {
+	done  chan struct{}
+	value interface{}
+	err   error
+}
+
+// CachingExchanger кэширует ответы другого Exchanger и склеивает одинаковые
+// одновременные запросы в один. Закэшированные значения отдаются всем
+// вызывающим как есть, изменять их нельзя. Истекшие ответы периодически
+// удаляются, а при превышении maxEntries вытесняются давно не читанные.
+type CachingExchanger struct {
+	next       Exchanger
+	ttls       CacheTTLs
+	maxEntries int
+	now        func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*list.Element // значения - *cacheEntry
+	lru       *list.List               // в начале - последние прочитанные
+	lastSweep time.Time
+	inflight  map[string]*cacheCall
+	stats     map[string]*CacheStats
+}
+
+// CacheOption настраивает CachingExchanger.
+type CacheOption func(*CachingExchanger)
+
+// WithCacheTTLs задает сроки жизни по методам, нулевой срок отключает кэш метода.
+func WithCacheTTLs(ttls CacheTTLs) CacheOption {
+	return func(c *CachingExchanger) {
+		c.ttls = ttls
+	}
+}
+
+// WithCacheMaxEntries ограничивает число ответов в кэше, 0 - без ограничения.
+func WithCacheMaxEntries(n int) CacheOption {
+	return func(c *CachingExchanger) {
+		c.maxEntries = n
+	}
+}
+
+// WithCacheClock подменяет часы, например в тестах.
+func WithCacheClock(now func() time.Time) CacheOption {
+	return func(c *CachingExchanger) {
+		c.now = now
+	}
+}
+
+// NewCachingExchanger оборачивает next кэшем со сроками DefaultCacheTTLs.
+func NewCachingExchanger(next Exchanger, opts ...CacheOption) *CachingExchanger {
+	c := &CachingExchanger{
+		next:       next,
+		ttls:       DefaultCacheTTLs,
+		maxEntries: DefaultCacheMaxEntries,
+		now:        time.Now,
+		entries:    make(map[string]*list.Element),
+		lru:        list.New(),
+		inflight:   make(map[string]*cacheCall),
+		stats:      make(map[string]*CacheStats),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.lastSweep = c.now()
+	return c
+}
+
+func (c *CachingExchanger) GetTicker() (Ticker, error) {
+	return cached(c, "GetTicker", "", c.ttls.Ticker, c.next.GetTicker)
+}
+
+func (c *CachingExchanger) GetTrades(pairs ...string) (Trades, error) {
+	return cached(c, "GetTrades", strings.Join(pairs, ","), c.ttls.Trades, func() (Trades, error) {
+		return c.next.GetTrades(pairs...)
+	})
+}
+
+func (c *CachingExchanger) GetOrderBook(limit int, pairs ...string) (OrderBook, error) {
+	key := fmt.Sprintf("%d|%s", limit, strings.Join(pairs, ","))
+	return cached(c, "GetOrderBook", key, c.ttls.OrderBook, func() (OrderBook, error) {
+		return c.next.GetOrderBook(limit, pairs...)
+	})
+}
+
+func (c *CachingExchanger) GetCurrencies() (Currencies, error) {
+	return cached(c, "GetCurrencies", "", c.ttls.Currencies, c.next.GetCurrencies)
+}
+
+func (c *CachingExchanger) GetPairSettings() (PairSettings, error) {
+	return cached(c, "GetPairSettings", "", c.ttls.PairSettings, c.next.GetPairSettings)
+}
+
+func (c *CachingExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (CandlesHistory, error) {
+	key := candlesKey(pair, period, start, end)
+	return cached(c, "GetCandlesHistory", key, c.candlesTTL(period, end), func() (CandlesHistory, error) {
+		return c.next.GetCandlesHistory(pair, period, start, end)
+	})
+}
+
+func (c *CachingExchanger) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	key := candlesKey(pair, resolution, start, end)
+	return cached(c, "GetClosePrice", key, c.candlesTTL(resolution, end), func() ([]float64, error) {
+		return c.next.GetClosePrice(pair, resolution, start, end)
+	})
+}
+
+// Stats возвращает копию счетчиков по методам.
+func (c *CachingExchanger) Stats() map[string]CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string]CacheStats, len(c.stats))
+	for method, s := range c.stats {
+		result[method] = *s
+	}
+	return result
+}
+
+// Len возвращает число ответов в кэше, включая еще не удаленные истекшие.
+func (c *CachingExchanger) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Purge удаляет все закэшированные ответы.
+func (c *CachingExchanger) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+}
+
+func (e *cacheEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+func (c *CachingExchanger) removeLocked(el *list.Element) {
+	delete(c.entries, el.Value.(*cacheEntry).key)
+	c.lru.Remove(el)
+}
+
+// storeLocked сохраняет ответ, раз в cacheSweepInterval удаляет истекшие
+// и вытесняет давно не читанные сверх maxEntries.
+func (c *CachingExchanger) storeLocked(entry *cacheEntry) {
+	if el, ok := c.entries[entry.key]; ok {
+		c.removeLocked(el)
+	}
+	c.entries[entry.key] = c.lru.PushFront(entry)
+
+	now := c.now()
+	if now.Sub(c.lastSweep) >= cacheSweepInterval {
+		c.lastSweep = now
+		for el := c.lru.Front(); el != nil; {
+			next := el.Next()
+			if el.Value.(*cacheEntry).expired(now) {
+				c.removeLocked(el)
+			}
+			el = next
+		}
+	}
+	for c.maxEntries > 0 && c.lru.Len() > c.maxEntries {
+		c.removeLocked(c.lru.Back())
+	}
+}
+
+// candlesTTL возвращает -1 (без срока), если последняя свеча интервала уже закрыта.
+func (c *CachingExchanger) candlesTTL(resolution int, end time.Time) time.Duration {
+	if c.ttls.Candles == 0 {
+		return 0
+	}
+	if resolution > 0 && !end.Add(time.Duration(resolution)*time.Minute).After(c.now()) {
+		return -1
+	}
+	return c.ttls.Candles
+}
+
+func candlesKey(pair string, resolution int, start, end time.Time) string {
+	return fmt.Sprintf("%s|%d|%d|%d", pair, resolution, start.UnixNano(), end.UnixNano())
+}
+
+func (c *CachingExchanger) statsLocked(method string) *CacheStats {
+	s := c.stats[method]
+	if s == nil {
+		s = &CacheStats{}
+		c.stats[method] = s
+	}
+	return s
+}
+
+// cached отдает значение из кэша, присоединяется к уже идущему запросу
+// или выполняет fetch. Ошибки не кэшируются. Если fetch паникует, ожидающие
+// получают ошибку, а паника идет дальше.
+func cached[T any](c *CachingExchanger, method, key string, ttl time.Duration, fetch func() (T, error)) (T, error) {
+	full := method + "|" + key
+
+	c.mu.Lock()
+	stats := c.statsLocked(method)
+	if el, ok := c.entries[full]; ok {
+		e := el.Value.(*cacheEntry)
+		if !e.expired(c.now()) {
+			stats.Hits++
+			c.lru.MoveToFront(el)
+			c.mu.Unlock()
+			return e.value.(T), nil
+		}
+		c.removeLocked(el)
+	}
+	if call, ok := c.inflight[full]; ok {
+		stats.Shared++
+		c.mu.Unlock()
+		<-call.done
+		if call.err != nil {
+			var zero T
+			return zero, call.err
+		}
+		return call.value.(T), nil
+	}
+	call := &cacheCall{done: make(chan struct{})}
+	c.inflight[full] = call
+	stats.Misses++
+	c.mu.Unlock()
+
+	var (
+		value T
+		err   error
+		done  bool
+	)
+	defer func() {
+		c.mu.Lock()
+		delete(c.inflight, full)
+		if !done {
+			err = fmt.Errorf("%s: request panicked", method)
+		}
+		call.value, call.err = value, err
+		if err != nil {
+			stats.Errors++
+		} else if ttl != 0 {
+			entry := &cacheEntry{key: full, value: value}
+			if ttl > 0 {
+				entry.expires = c.now().Add(ttl)
+			}
+			c.storeLocked(entry)
+		}
+		c.mu.Unlock()
+		close(call.done)
+	}()
+
+	value, err = fetch()
+	done = true
+	return value, err
+}
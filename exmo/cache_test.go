@@ -0,0 +1,220 @@
+package exmo_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func TestCachingExchanger_TTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger, exmo.WithCacheClock(func() time.Time { return now }))
+
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "1"}}, nil)
+	for i := 0; i < 3; i++ {
+		ticker, err := cache.GetTicker()
+		require.NoError(t, err)
+		assert.Equal(t, "1", ticker["BTC_USD"].LastTrade)
+	}
+
+	now = now.Add(exmo.DefaultCacheTTLs.Ticker)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "2"}}, nil)
+	ticker, err := cache.GetTicker()
+	require.NoError(t, err)
+	assert.Equal(t, "2", ticker["BTC_USD"].LastTrade)
+
+	stats := cache.Stats()["GetTicker"]
+	assert.Equal(t, exmo.CacheStats{Hits: 2, Misses: 2}, stats)
+}
+
+func TestCachingExchanger_Keys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger)
+
+	mockExchanger.EXPECT().GetOrderBook(10, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {}}, nil)
+	mockExchanger.EXPECT().GetOrderBook(20, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {}}, nil)
+	mockExchanger.EXPECT().GetTrades("BTC_USD", "ETH_USD").Return(exmo.Trades{}, nil)
+	mockExchanger.EXPECT().GetCurrencies().Return(exmo.Currencies{"BTC": {}}, nil)
+	mockExchanger.EXPECT().GetPairSettings().Return(exmo.PairSettings{}, nil)
+
+	for i := 0; i < 2; i++ {
+		_, err := cache.GetOrderBook(10, "BTC_USD")
+		require.NoError(t, err)
+		_, err = cache.GetOrderBook(20, "BTC_USD")
+		require.NoError(t, err)
+		_, err = cache.GetTrades("BTC_USD", "ETH_USD")
+		require.NoError(t, err)
+		_, err = cache.GetCurrencies()
+		require.NoError(t, err)
+		_, err = cache.GetPairSettings()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, uint64(2), cache.Stats()["GetOrderBook"].Hits)
+}
+
+func TestCachingExchanger_Candles(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger, exmo.WithCacheClock(func() time.Time { return now }))
+
+	closedFrom, closedTo := now.Add(-48*time.Hour), now.Add(-24*time.Hour)
+	openFrom, openTo := now.Add(-time.Hour), now
+
+	mockExchanger.EXPECT().GetClosePrice("BTC_USD", 30, closedFrom, closedTo).Return([]float64{1, 2}, nil)
+	mockExchanger.EXPECT().GetClosePrice("BTC_USD", 30, openFrom, openTo).Return([]float64{3}, nil).Times(2)
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 30, closedFrom, closedTo).Return(exmo.CandlesHistory{}, nil)
+
+	_, err := cache.GetClosePrice("BTC_USD", 30, closedFrom, closedTo)
+	require.NoError(t, err)
+	_, err = cache.GetClosePrice("BTC_USD", 30, openFrom, openTo)
+	require.NoError(t, err)
+	_, err = cache.GetCandlesHistory("BTC_USD", 30, closedFrom, closedTo)
+	require.NoError(t, err)
+
+	// Через сутки открытый интервал истек, закрытый остался в кэше
+	now = now.Add(24 * time.Hour)
+	prices, err := cache.GetClosePrice("BTC_USD", 30, closedFrom, closedTo)
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 2}, prices)
+	_, err = cache.GetClosePrice("BTC_USD", 30, openFrom, openTo)
+	require.NoError(t, err)
+	_, err = cache.GetCandlesHistory("BTC_USD", 30, closedFrom, closedTo)
+	require.NoError(t, err)
+}
+
+func TestCachingExchanger_ErrorsNotCached(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger)
+
+	mockExchanger.EXPECT().GetTicker().Return(nil, errors.New("exchange error"))
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{}, nil)
+
+	_, err := cache.GetTicker()
+	assert.Error(t, err)
+	_, err = cache.GetTicker()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), cache.Stats()["GetTicker"].Errors)
+}
+
+func TestCachingExchanger_DisabledTTL(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger, exmo.WithCacheTTLs(exmo.CacheTTLs{}))
+
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{}, nil).Times(2)
+	_, _ = cache.GetTicker()
+	_, _ = cache.GetTicker()
+}
+
+func TestCachingExchanger_SingleFlight(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger)
+
+	from, to := time.Now().Add(-time.Hour), time.Now()
+	release := make(chan struct{})
+	mockExchanger.EXPECT().GetClosePrice("BTC_USD", 30, from, to).DoAndReturn(
+		func(string, int, time.Time, time.Time) ([]float64, error) {
+			<-release
+			return []float64{1, 2, 3}, nil
+		},
+	).Times(1)
+
+	const callers = 5
+	var wg sync.WaitGroup
+	results := make([][]float64, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = cache.GetClosePrice("BTC_USD", 30, from, to)
+		}(i)
+	}
+
+	// Ждем, пока все вызывающие присоединятся к запросу
+	require.Eventually(t, func() bool {
+		return cache.Stats()["GetClosePrice"].Shared == callers-1
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	for _, r := range results {
+		assert.Equal(t, []float64{1, 2, 3}, r)
+	}
+}
+
+func TestCachingExchanger_Bounded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger,
+		exmo.WithCacheMaxEntries(2),
+		exmo.WithCacheClock(func() time.Time { return now }),
+	)
+
+	mockExchanger.EXPECT().GetOrderBook(gomock.Any(), "BTC_USD").Return(exmo.OrderBook{}, nil).Times(4)
+	for _, limit := range []int{10, 20, 10, 30} {
+		_, err := cache.GetOrderBook(limit, "BTC_USD")
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, cache.Len())
+	// 10 читали позже 20, поэтому вытеснен 20
+	_, err := cache.GetOrderBook(10, "BTC_USD")
+	require.NoError(t, err)
+	_, err = cache.GetOrderBook(20, "BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, exmo.CacheStats{Hits: 2, Misses: 4}, cache.Stats()["GetOrderBook"])
+
+	// Истекшие ответы удаляются при очередной записи после интервала очистки
+	now = now.Add(time.Hour)
+	mockExchanger.EXPECT().GetCurrencies().Return(exmo.Currencies{}, nil)
+	_, err = cache.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCachingExchanger_FetchPanic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	cache := exmo.NewCachingExchanger(mockExchanger)
+
+	mockExchanger.EXPECT().GetTicker().DoAndReturn(func() (exmo.Ticker, error) {
+		panic("boom")
+	})
+	assert.PanicsWithValue(t, "boom", func() { _, _ = cache.GetTicker() })
+
+	// Запрос не остался висеть: следующий вызов идет к бирже
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{}, nil)
+	_, err := cache.GetTicker()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), cache.Stats()["GetTicker"].Errors)
+}
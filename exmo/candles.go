@@ -0,0 +1,77 @@
+package exmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+func UnmarshalCandlesHistory(data []byte) (CandlesHistory, error) {
+	var r CandlesHistory
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *CandlesHistory) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// CandlesHistory - ответ /candles_history.
+type CandlesHistory struct {
+	Candles []Candle `json:"candles"`
+}
+
+// Candle - свеча, T - время открытия в миллисекундах.
+type Candle struct {
+	T int64   `json:"t"`
+	O float64 `json:"o"`
+	C float64 `json:"c"`
+	H float64 `json:"h"`
+	L float64 `json:"l"`
+	V float64 `json:"v"`
+}
+
+// UnmarshalJSON принимает как объект {"t":..,"o":..}, так и массив [t, o, h, l, c, v].
+func (c *Candle) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '[' {
+		var row []float64
+		if err := json.Unmarshal(data, &row); err != nil {
+			return err
+		}
+		if len(row) < 6 {
+			return fmt.Errorf("candle array has %d fields, want 6", len(row))
+		}
+		*c = Candle{T: int64(row[0]), O: row[1], H: row[2], L: row[3], C: row[4], V: row[5]}
+		return nil
+	}
+	type plain Candle
+	return json.Unmarshal(data, (*plain)(c))
+}
+
+// Time возвращает время открытия свечи.
+func (c Candle) Time() time.Time {
+	return time.UnixMilli(c.T)
+}
+
+// ClosePrices возвращает цены закрытия свечей.
+func (h CandlesHistory) ClosePrices() []float64 {
+	prices := make([]float64, len(h.Candles))
+	for i, c := range h.Candles {
+		prices[i] = c.C
+	}
+	return prices
+}
+
+// resolutionParam переводит разрешение в минутах в значение параметра Exmo.
+func resolutionParam(minutes int) string {
+	switch minutes {
+	case 24 * 60:
+		return "D"
+	case 7 * 24 * 60:
+		return "W"
+	case 30 * 24 * 60:
+		return "M"
+	}
+	return strconv.Itoa(minutes)
+}
@@ -0,0 +1,50 @@
+package exmo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCandlesHistoryMarshaling(t *testing.T) {
+	t.Run("object candles", func(t *testing.T) {
+		data := []byte(`{"candles":[{"t":1640995200000,"o":50000,"c":50500,"h":51000,"l":49000,"v":10}]}`)
+		history, err := UnmarshalCandlesHistory(data)
+
+		require.NoError(t, err)
+		require.Len(t, history.Candles, 1)
+		assert.Equal(t, Candle{T: 1640995200000, O: 50000, C: 50500, H: 51000, L: 49000, V: 10}, history.Candles[0])
+		assert.Equal(t, time.UnixMilli(1640995200000), history.Candles[0].Time())
+	})
+
+	t.Run("array candles", func(t *testing.T) {
+		data := []byte(`{"candles":[[1640995200000,50000,51000,49000,50500,10]]}`)
+		history, err := UnmarshalCandlesHistory(data)
+
+		require.NoError(t, err)
+		assert.Equal(t, []float64{50500}, history.ClosePrices())
+		assert.Equal(t, 51000.0, history.Candles[0].H)
+	})
+
+	t.Run("short array", func(t *testing.T) {
+		_, err := UnmarshalCandlesHistory([]byte(`{"candles":[[1,2,3]]}`))
+		assert.Error(t, err)
+	})
+
+	t.Run("marshal", func(t *testing.T) {
+		history := CandlesHistory{Candles: []Candle{{T: 1, C: 2}}}
+		data, err := history.Marshal()
+
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"t":1`)
+	})
+}
+
+func TestResolutionParam(t *testing.T) {
+	assert.Equal(t, "30", resolutionParam(30))
+	assert.Equal(t, "D", resolutionParam(1440))
+	assert.Equal(t, "W", resolutionParam(10080))
+	assert.Equal(t, "M", resolutionParam(43200))
+}
@@ -0,0 +1,235 @@
+package exmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// Exmo - клиент публичного и приватного API Exmo. Создается через NewExmo,
+// поведение настраивается опциями With*.
+type Exmo struct {
+	client  *http.Client
+	url     string
+	key     string
+	secret  string
+	nonce   int64
+	retry   RetryPolicy
+	limiter *RateLimiter
+	sleep   func(time.Duration)
+	metrics *telemetry.Metrics
+
+	logger      *slog.Logger
+	debugBodies bool
+}
+// Currencies - множество кодов валют из /currency.
+type Currencies map[string]struct{}
+
+func (e *Exmo) GetCurrencies() (Currencies, error) {
+	var currencies []string
+	if err := e.get("/currency", nil, &currencies); err != nil {
+		return nil, err
+	}
+
+	result := make(Currencies)
+	for _, c := range currencies {
+		result[c] = struct{}{}
+	}
+	return result, nil
+}
+
+// Option настраивает клиента Exmo.
+type Option = func(*Exmo)
+
+// NewExmo создает клиента с адресом https://api.exmo.com/v1, без повторов,
+// ограничителя частоты и журнала. Возвращаемое значение - *Exmo.
+func NewExmo(opts ...Option) Exchanger {
+	exmo := &Exmo{
+		client: &http.Client{},
+		url:    "https://api.exmo.com/v1",
+		sleep:  time.Sleep,
+	}
+	for _, opt := range opts {
+		opt(exmo)
+	}
+	return exmo
+}
+
+// get выполняет GET-запрос и декодирует ответ в v. Ответы с кодом, отличным
+// от 200, и тела с "result":false превращаются в *APIError. Временные
+// ошибки повторяются по RetryPolicy.
+func (e *Exmo) get(endpoint string, query url.Values, v interface{}) error {
+	u := e.url + endpoint
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequest(http.MethodGet, u, nil)
+		if err != nil {
+			return err
+		}
+		err = e.do(req, endpoint, attempt, v)
+		if err == nil || attempt >= e.retry.MaxAttempts || !retryable(err) {
+			return err
+		}
+		e.metrics.Retried(endpoint)
+		e.sleep(e.retry.Delay(attempt))
+	}
+}
+
+// decodeError - ответ получен, но не разобран. Такие ошибки не повторяются.
+type decodeError struct {
+	endpoint string
+	err      error
+}
+
+func (e *decodeError) Error() string {
+	return fmt.Sprintf("exmo %s: decode response: %v", e.endpoint, e.err)
+}
+
+func (e *decodeError) Unwrap() error {
+	return e.err
+}
+
+// do выполняет запрос. attempt - номер попытки, попадает в журнал и ошибки.
+func (e *Exmo) do(req *http.Request, endpoint string, attempt int, v interface{}) (err error) {
+	if e.limiter != nil {
+		if wait := e.limiter.Reserve(); wait > 0 {
+			e.metrics.LimiterWaited(wait)
+			e.sleep(wait)
+		}
+	}
+	e.logRequest(req, endpoint, attempt)
+	start := time.Now()
+	resp, err := e.client.Do(req)
+	if err != nil {
+		e.metrics.ObserveRequest(endpoint, 0, time.Since(start))
+		e.logResponse(req, endpoint, attempt, 0, nil, time.Since(start), err)
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	latency := time.Since(start)
+	e.metrics.ObserveRequest(endpoint, resp.StatusCode, latency)
+	defer func() {
+		e.logResponse(req, endpoint, attempt, resp.StatusCode, body, latency, err)
+	}()
+	if err != nil {
+		return fmt.Errorf("exmo %s %s: read response: %w", req.Method, endpoint, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return withRequest(newStatusError(endpoint, resp.StatusCode, body), req, attempt)
+	}
+	if apiErr := parseErrorBody(endpoint, resp.StatusCode, body); apiErr != nil {
+		return withRequest(apiErr, req, attempt)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		e.metrics.DecodeFailed(endpoint)
+		return &decodeError{endpoint: endpoint, err: err}
+	}
+	return nil
+}
+
+// withRequest дополняет ошибку биржи методом запроса и номером попытки.
+func withRequest(apiErr *APIError, req *http.Request, attempt int) *APIError {
+	apiErr.Method = req.Method
+	apiErr.Attempt = attempt
+	return apiErr
+}
+
+func (e *Exmo) GetOrderBook(limit int, pairs ...string) (OrderBook, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("at least one pair is required")
+	}
+
+	query := url.Values{}
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("pair", strings.Join(pairs, ","))
+
+	var orderBook OrderBook
+	if err := e.get("/order_book", query, &orderBook); err != nil {
+		return nil, err
+	}
+
+	// Проверяем, что получили данные хотя бы для одной пары
+	if len(orderBook) == 0 {
+		return nil, errors.New("empty order book response")
+	}
+
+	return orderBook, nil
+}
+
+func (e *Exmo) GetTicker() (Ticker, error) {
+	var ticker Ticker
+	if err := e.get("/ticker", nil, &ticker); err != nil {
+		return nil, err
+	}
+	return ticker, nil
+}
+
+func (e *Exmo) GetTrades(pairs ...string) (Trades, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("at least one pair is required")
+	}
+
+	query := url.Values{}
+	query.Set("pair", strings.Join(pairs, ","))
+
+	var trades Trades
+	if err := e.get("/trades", query, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func (e *Exmo) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	history, err := e.GetCandlesHistory(pair, resolution, start, end)
+	if err != nil {
+		return nil, err
+	}
+	return history.ClosePrices(), nil
+}
+
+func (e *Exmo) GetCandlesHistory(pair string, period int, start, end time.Time) (CandlesHistory, error) {
+	query := url.Values{}
+	query.Set("symbol", pair)
+	query.Set("resolution", resolutionParam(period))
+	query.Set("from", strconv.FormatInt(start.Unix(), 10))
+	query.Set("to", strconv.FormatInt(end.Unix(), 10))
+
+	var history CandlesHistory
+	if err := e.get("/candles_history", query, &history); err != nil {
+		return CandlesHistory{}, err
+	}
+	return history, nil
+}
+
+func (e *Exmo) GetPairSettings() (PairSettings, error) {
+	var settings PairSettings
+	if err := e.get("/pair_settings", nil, &settings); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// Exchanger - публичные рыночные данные биржи. Его реализуют клиент Exmo,
+// кэш, трассировка и моки из exmomock.
+type Exchanger interface {
+	GetTicker() (Ticker, error)
+	GetTrades(pairs ...string) (Trades, error)
+	GetOrderBook(limit int, pairs ...string) (OrderBook, error)
+	GetCurrencies() (Currencies, error)
+	GetCandlesHistory(pair string, period int, start, end time.Time) (CandlesHistory, error)
+	GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error)
+	GetPairSettings() (PairSettings, error)
+}
@@ -0,0 +1,116 @@
+package exmo
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// WithLogger включает журнал запросов клиента: метод, URL, статус, время,
+// размер ответа и номер попытки. Успешные ответы пишутся с уровнем Info,
+// ошибки - с уровнем Warn.
+func WithLogger(logger *slog.Logger) func(*Exmo) {
+	return func(e *Exmo) {
+		e.logger = logger
+	}
+}
+
+// WithDebugBodies дополнительно пишет с уровнем Debug заголовки и тела
+// запросов и ответов. Ключ API, подпись и nonce в них скрываются.
+func WithDebugBodies(enabled bool) func(*Exmo) {
+	return func(e *Exmo) {
+		e.debugBodies = enabled
+	}
+}
+
+const redacted = "[REDACTED]"
+
+// Секретные поля в формах (nonce=...) и JSON ("key":"...").
+var (
+	secretFormField = regexp.MustCompile(`(?i)\b(nonce|key|secret|sign)=[^&\s]*`)
+	secretJSONField = regexp.MustCompile(`(?i)"(nonce|key|api_key|secret|api_secret|sign)"\s*:\s*("[^"]*"|\d+)`)
+	secretHeaders   = map[string]bool{"Key": true, "Sign": true, "Authorization": true}
+)
+
+// redact скрывает ключ и секрет клиента, где бы они ни встретились,
+// а также значения секретных полей форм и JSON.
+func (e *Exmo) redact(s string) string {
+	for _, secret := range []string{e.key, e.secret} {
+		if secret != "" {
+			s = strings.ReplaceAll(s, secret, redacted)
+		}
+	}
+	s = secretFormField.ReplaceAllString(s, "$1="+redacted)
+	return secretJSONField.ReplaceAllString(s, `"$1":"`+redacted+`"`)
+}
+
+func (e *Exmo) redactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for name := range h {
+		if secretHeaders[name] {
+			out[name] = redacted
+			continue
+		}
+		out[name] = e.redact(h.Get(name))
+	}
+	return out
+}
+
+// logRequest пишет тело запроса в отладочном режиме.
+func (e *Exmo) logRequest(req *http.Request, endpoint string, attempt int) {
+	if e.logger == nil || !e.debugBodies || !e.logger.Enabled(context.Background(), slog.LevelDebug) {
+		return
+	}
+	var body string
+	if req.GetBody != nil {
+		if rc, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			body = string(data)
+		}
+	}
+	e.logger.Debug("exmo request body",
+		slog.String("method", req.Method),
+		slog.String("url", e.redact(req.URL.String())),
+		slog.String("endpoint", endpoint),
+		slog.Int("attempt", attempt),
+		slog.Any("headers", e.redactHeaders(req.Header)),
+		slog.String("body", e.redact(body)),
+	)
+}
+
+// logResponse пишет итог запроса. status 0 - ответ не получен.
+func (e *Exmo) logResponse(req *http.Request, endpoint string, attempt, status int, body []byte, latency time.Duration, err error) {
+	if e.logger == nil {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("url", e.redact(req.URL.String())),
+		slog.String("endpoint", endpoint),
+		slog.Int("status", status),
+		slog.Duration("latency", latency),
+		slog.Int("bytes", len(body)),
+		slog.Int("attempt", attempt),
+	}
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", e.redact(err.Error())))
+	}
+	ctx := context.Background()
+	e.logger.LogAttrs(ctx, level, "exmo request", attrs...)
+
+	if e.debugBodies && status != 0 {
+		e.logger.LogAttrs(ctx, slog.LevelDebug, "exmo response body",
+			slog.String("method", req.Method),
+			slog.String("endpoint", endpoint),
+			slog.Int("attempt", attempt),
+			slog.String("body", e.redact(string(body))),
+		)
+	}
+}
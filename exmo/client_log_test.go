@@ -0,0 +1,121 @@
+package exmo_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+)
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &r), line)
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestExmo_LogRetries(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetTicker(exmo.Ticker{"BTC_USD": {LastTrade: "100"}})
+	fake.Inject("/ticker", exmotest.Fault{Status: http.StatusBadGateway, Times: 2})
+
+	var buf bytes.Buffer
+	client := fake.Client(
+		exmo.WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))),
+		exmo.WithRetry(exmo.RetryPolicy{MaxAttempts: 3}),
+		exmo.WithSleep(func(time.Duration) {}),
+	)
+	_, err := client.GetTicker()
+	require.NoError(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 3)
+	for i, r := range records {
+		assert.Equal(t, "exmo request", r["msg"])
+		assert.Equal(t, "GET", r["method"])
+		assert.Equal(t, fake.URL()+"/ticker", r["url"])
+		assert.Equal(t, float64(i+1), r["attempt"])
+		assert.Contains(t, r, "latency")
+	}
+	assert.Equal(t, "WARN", records[0]["level"])
+	assert.Equal(t, float64(502), records[0]["status"])
+	assert.Contains(t, records[0]["error"], "exmo GET /ticker: server returned non-200 status 502")
+	assert.Equal(t, "INFO", records[2]["level"])
+	assert.Equal(t, float64(200), records[2]["status"])
+	assert.Positive(t, records[2]["bytes"])
+}
+
+func TestExmo_ErrorCarriesRequest(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.Inject("/ticker", exmotest.Fault{Status: http.StatusInternalServerError})
+
+	client := fake.Client(
+		exmo.WithRetry(exmo.RetryPolicy{MaxAttempts: 2}),
+		exmo.WithSleep(func(time.Duration) {}),
+	)
+	_, err := client.GetTicker()
+	assert.EqualError(t, err, "exmo GET /ticker: server returned non-200 status 500 (attempt 2)")
+}
+
+func TestExmo_DebugBodiesRedacted(t *testing.T) {
+	const key, secret = "K-public-123", "S-secret-456"
+	fake := exmotest.NewFakeExmo(exmotest.WithFakeCredentials(key, secret))
+	defer fake.Close()
+	fake.SetBalance("USD", 100)
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := fake.Client(exmo.WithAPIKey(key, secret), exmo.WithLogger(logger), exmo.WithDebugBodies(true))
+	_, err := client.GetUserInfo()
+	require.NoError(t, err)
+
+	out := buf.String()
+	nonce := strconv.FormatInt(client.Nonce(), 10)
+	assert.NotContains(t, out, key)
+	assert.NotContains(t, out, secret)
+	assert.NotContains(t, out, nonce)
+	assert.NotContains(t, out, exmo.SignBody(secret, "nonce="+nonce))
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 3)
+	assert.Equal(t, "exmo request body", records[0]["msg"])
+	assert.Equal(t, "nonce=[REDACTED]", records[0]["body"])
+	assert.Equal(t, map[string]interface{}{
+		"Content-Type": "application/x-www-form-urlencoded",
+		"Key":          "[REDACTED]",
+		"Sign":         "[REDACTED]",
+	}, records[0]["headers"])
+	assert.Equal(t, "exmo request", records[1]["msg"])
+	assert.Equal(t, "exmo response body", records[2]["msg"])
+	assert.Contains(t, records[2]["body"], `"USD"`)
+}
+
+func TestExmo_Redact(t *testing.T) {
+	e := exmo.NewExmo(exmo.WithAPIKey("abc", "xyz")).(*exmo.Exmo)
+	assert.Equal(t, "nonce=[REDACTED]&pair=BTC_USD", e.Redact("nonce=123&pair=BTC_USD"))
+	assert.Equal(t, `{"nonce":"[REDACTED]","Key":"[REDACTED]","x":1}`, e.Redact(`{"nonce":99,"Key":"k","x":1}`))
+	assert.Equal(t, "token [REDACTED] and [REDACTED]", e.Redact("token abc and xyz"))
+
+	// Без логгера журнал не пишется и ничего не ломается
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetCurrencies("BTC")
+	_, err := fake.Client(exmo.WithDebugBodies(true)).GetCurrencies()
+	assert.NoError(t, err)
+}
@@ -0,0 +1,127 @@
+package exmo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// RetryPolicy задает повтор публичных запросов при временных ошибках:
+// сетевых сбоях, ответах 5xx и превышении лимита запросов. Приватные
+// запросы не повторяются, чтобы не выставить ордер дважды.
+type RetryPolicy struct {
+	MaxAttempts int           // всего попыток, 0 и 1 - без повторов
+	Backoff     time.Duration // пауза перед второй попыткой, дальше удваивается
+	MaxBackoff  time.Duration // верхняя граница паузы, 0 - без ограничения
+}
+
+// Delay возвращает паузу перед попыткой attempt (с единицы).
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := time.Duration(float64(p.Backoff) * math.Pow(2, float64(attempt-1)))
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос после ошибки.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) {
+		return true
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError
+	}
+	// Ошибка декодирования ответа - не временная
+	var decodeErr *decodeError
+	return !errors.As(err, &decodeErr)
+}
+
+// RateLimiter - маркерная корзина: perMinute запросов в минуту с запасом burst.
+// Общая для клиентов Exmo и Binance, безопасна для конкурентного вызова.
+type RateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+	now      func() time.Time
+}
+
+func NewRateLimiter(perMinute, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		interval: time.Minute / time.Duration(perMinute),
+		burst:    float64(burst),
+		tokens:   float64(burst),
+		now:      time.Now,
+	}
+}
+
+// Reserve занимает маркер и возвращает, сколько нужно подождать до запроса.
+func (l *RateLimiter) Reserve() time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if !l.last.IsZero() {
+		l.tokens = math.Min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	}
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens * float64(l.interval))
+}
+
+// WithBaseURL задает адрес API, например адрес тестового стенда.
+func WithBaseURL(u string) func(*Exmo) {
+	return func(e *Exmo) {
+		e.url = u
+	}
+}
+
+// WithTimeout ограничивает время одного HTTP-запроса.
+func WithTimeout(d time.Duration) func(*Exmo) {
+	return func(e *Exmo) {
+		e.client.Timeout = d
+	}
+}
+
+// WithRetry включает повторы временных ошибок: сетевых сбоев, 429 и 5xx.
+func WithRetry(policy RetryPolicy) func(*Exmo) {
+	return func(e *Exmo) {
+		e.retry = policy
+	}
+}
+
+// WithRateLimit ограничивает частоту запросов клиента. Запросы сверх
+// лимита ждут своей очереди, а не получают ошибку.
+func WithRateLimit(perMinute, burst int) func(*Exmo) {
+	return func(e *Exmo) {
+		if perMinute <= 0 {
+			e.limiter = nil
+			return
+		}
+		e.limiter = NewRateLimiter(perMinute, burst)
+	}
+}
+
+// WithMetrics подключает сбор метрик запросов клиента.
+func WithMetrics(m *telemetry.Metrics) func(*Exmo) {
+	return func(e *Exmo) {
+		e.metrics = m
+	}
+}
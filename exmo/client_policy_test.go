@@ -0,0 +1,29 @@
+package exmo
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 200*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 300*time.Millisecond, p.Delay(3))
+}
+
+func TestRateLimiter(t *testing.T) {
+	now := time.Unix(0, 0)
+	l := NewRateLimiter(60, 2)
+	l.now = func() time.Time { return now }
+
+	assert.Zero(t, l.Reserve())
+	assert.Zero(t, l.Reserve())
+	assert.Equal(t, time.Second, l.Reserve())
+	assert.Equal(t, 2*time.Second, l.Reserve())
+
+	now = now.Add(10 * time.Second)
+	assert.Zero(t, l.Reserve())
+}
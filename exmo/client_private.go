@@ -0,0 +1,126 @@
+package exmo
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// WithAPIKey задает ключ и секрет для приватных методов API.
+func WithAPIKey(key, secret string) func(*Exmo) {
+	return func(e *Exmo) {
+		e.key = key
+		e.secret = secret
+	}
+}
+
+// post выполняет подписанный запрос к приватному API. Тело подписывается
+// HMAC-SHA512 секретом, nonce растет от запроса к запросу.
+func (e *Exmo) post(endpoint string, form url.Values, v interface{}) error {
+	if e.key == "" || e.secret == "" {
+		return fmt.Errorf("exmo %s: %w: api key is not configured", endpoint, ErrAuth)
+	}
+	if form == nil {
+		form = url.Values{}
+	}
+	form.Set("nonce", strconv.FormatInt(e.nextNonce(), 10))
+	body := form.Encode()
+
+	req, err := http.NewRequest(http.MethodPost, e.url+endpoint, strings.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Key", e.key)
+	req.Header.Set("Sign", signBody(e.secret, body))
+	return e.do(req, endpoint, 1, v)
+}
+
+func (e *Exmo) nextNonce() int64 {
+	for {
+		prev := atomic.LoadInt64(&e.nonce)
+		next := time.Now().UnixNano()
+		if next <= prev {
+			next = prev + 1
+		}
+		if atomic.CompareAndSwapInt64(&e.nonce, prev, next) {
+			return next
+		}
+	}
+}
+
+func signBody(secret, body string) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// formatFloat пишет число без экспоненты и лишних нулей, как его ждет API.
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func (e *Exmo) CreateOrder(pair string, quantity, price float64, orderType OrderType) (int64, error) {
+	form := url.Values{}
+	form.Set("pair", pair)
+	form.Set("quantity", formatFloat(quantity))
+	form.Set("price", formatFloat(price))
+	form.Set("type", string(orderType))
+
+	var resp struct {
+		OrderID int64 `json:"order_id"`
+	}
+	if err := e.post("/order_create", form, &resp); err != nil {
+		return 0, err
+	}
+	return resp.OrderID, nil
+}
+
+func (e *Exmo) CancelOrder(orderID int64) error {
+	form := url.Values{}
+	form.Set("order_id", strconv.FormatInt(orderID, 10))
+
+	var resp struct{}
+	return e.post("/order_cancel", form, &resp)
+}
+
+func (e *Exmo) GetOpenOrders() (OpenOrders, error) {
+	var orders OpenOrders
+	if err := e.post("/user_open_orders", nil, &orders); err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (e *Exmo) GetUserTrades(limit int, pairs ...string) (UserTrades, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("at least one pair is required")
+	}
+
+	form := url.Values{}
+	form.Set("pair", strings.Join(pairs, ","))
+	form.Set("limit", strconv.Itoa(limit))
+	form.Set("offset", "0")
+
+	var trades UserTrades
+	if err := e.post("/user_trades", form, &trades); err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+func (e *Exmo) GetUserInfo() (UserInfo, error) {
+	var info UserInfo
+	if err := e.post("/user_info", nil, &info); err != nil {
+		return UserInfo{}, err
+	}
+	return info, nil
+}
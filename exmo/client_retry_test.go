@@ -0,0 +1,68 @@
+package exmo_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+)
+
+func TestExmo_Retry(t *testing.T) {
+	fake := exmotest.NewFakeExmo(exmotest.WithFakeCredentials("key", "secret"))
+	defer fake.Close()
+	fake.SetCurrencies("BTC")
+
+	var sleeps []time.Duration
+	client := fake.Client(
+		exmo.WithRetry(exmo.RetryPolicy{MaxAttempts: 3, Backoff: time.Second}),
+		exmo.WithAPIKey("key", "secret"),
+		exmo.WithSleep(func(d time.Duration) { sleeps = append(sleeps, d) }),
+	)
+
+	fake.Inject("/currency", exmotest.Fault{Status: 502, Times: 1})
+	fake.Inject("/currency", exmotest.Fault{Status: 429, Body: "Too Many Requests", Times: 1})
+	_, err := client.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, 3, fake.Requests("/currency"))
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, sleeps)
+
+	// Ошибки клиента и неразборчивые ответы не повторяются
+	fake.Inject("/currency", exmotest.Fault{Error: "Error 40005: Authorization error", Times: 1})
+	_, err = client.GetCurrencies()
+	assert.True(t, errors.Is(err, exmo.ErrAuth))
+	fake.Inject("/currency", exmotest.Fault{Malformed: true, Times: 1})
+	_, err = client.GetCurrencies()
+	assert.ErrorContains(t, err, "decode response")
+	assert.Equal(t, 5, fake.Requests("/currency"))
+
+	// Попытки исчерпаны
+	fake.Inject("/currency", exmotest.Fault{Status: 503, Times: 3})
+	_, err = client.GetCurrencies()
+	assert.Error(t, err)
+	assert.Equal(t, 8, fake.Requests("/currency"))
+
+	// Приватные запросы не повторяются
+	fake.Inject("/user_info", exmotest.Fault{Status: 502, Times: 1})
+	_, err = client.GetUserInfo()
+	assert.Error(t, err)
+	assert.Equal(t, 1, fake.Requests("/user_info"))
+}
+
+func TestExmo_RateLimit(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetCurrencies("BTC")
+
+	var waited time.Duration
+	client := fake.Client(exmo.WithRateLimit(60, 1), exmo.WithSleep(func(d time.Duration) { waited += d }))
+	for i := 0; i < 3; i++ {
+		_, err := client.GetCurrencies()
+		require.NoError(t, err)
+	}
+	assert.Greater(t, waited, time.Second)
+}
@@ -1,12 +1,14 @@
-package main
+package exmo
 
 import (
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestExmo_GetTicker(t *testing.T) {
@@ -245,3 +247,97 @@ func TestExmo_GetOrderBook(t *testing.T) {
     })
 }
 
+func TestExmo_GetPairSettings(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/pair_settings", r.URL.Path)
+			w.Write([]byte(pairSettingsJSON))
+		}))
+		defer ts.Close()
+
+		client := NewExmo(func(e *Exmo) { e.url = ts.URL })
+		settings, err := client.GetPairSettings()
+
+		assert.NoError(t, err)
+		assert.Equal(t, 2, settings["BTC_USD"].PricePrecision)
+		assert.Equal(t, 1000.0, settings["BTC_USD"].MaxQuantity)
+	})
+
+	t.Run("server error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		client := NewExmo(func(e *Exmo) { e.url = ts.URL })
+		_, err := client.GetPairSettings()
+		assert.Error(t, err)
+	})
+}
+
+func TestExmo_ErrorBodies(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/order_book", "/trades":
+			w.Write([]byte(`{"result":false,"error":"Error 50304: Incorrect pair"}`))
+		case "/ticker":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"result":false,"error":"Error 40016: Maintenance work in progress"}`))
+		}
+	}))
+	defer ts.Close()
+
+	client := NewExmo(func(e *Exmo) { e.url = ts.URL })
+	calls := map[string]func() error{
+		"/ticker":          func() error { _, err := client.GetTicker(); return err },
+		"/trades":          func() error { _, err := client.GetTrades("BTC_XXX"); return err },
+		"/order_book":      func() error { _, err := client.GetOrderBook(10, "BTC_XXX"); return err },
+		"/currency":        func() error { _, err := client.GetCurrencies(); return err },
+		"/candles_history": func() error { _, err := client.GetClosePrice("BTC_USD", 60, time.Now(), time.Now()); return err },
+		"/pair_settings":   func() error { _, err := client.GetPairSettings(); return err },
+	}
+
+	for endpoint, call := range calls {
+		t.Run(endpoint, func(t *testing.T) {
+			err := call()
+
+			var apiErr *APIError
+			require.True(t, errors.As(err, &apiErr), "got %v", err)
+			assert.Equal(t, endpoint, apiErr.Endpoint)
+			switch endpoint {
+			case "/ticker":
+				assert.ErrorIs(t, err, ErrRateLimited)
+				assert.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+			case "/trades", "/order_book":
+				assert.ErrorIs(t, err, ErrInvalidPair)
+				assert.Equal(t, 50304, apiErr.Code)
+			default:
+				assert.Equal(t, 40016, apiErr.Code)
+				assert.NotErrorIs(t, err, ErrAuth)
+			}
+		})
+	}
+}
+
+func TestExmo_GetCandlesHistory_Query(t *testing.T) {
+	from := time.Unix(1700000000, 0)
+	to := from.Add(time.Hour)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/candles_history", r.URL.Path)
+		assert.Equal(t, "BTC_USD", r.URL.Query().Get("symbol"))
+		assert.Equal(t, "30", r.URL.Query().Get("resolution"))
+		assert.Equal(t, "1700000000", r.URL.Query().Get("from"))
+		assert.Equal(t, "1700003600", r.URL.Query().Get("to"))
+		w.Write([]byte(`{"candles":[{"t":1700000000000,"o":1,"c":2,"h":3,"l":0.5,"v":10}]}`))
+	}))
+	defer ts.Close()
+
+	client := NewExmo(func(e *Exmo) { e.url = ts.URL })
+	history, err := client.GetCandlesHistory("BTC_USD", 30, from, to)
+
+	require.NoError(t, err)
+	require.Len(t, history.Candles, 1)
+	assert.Equal(t, 2.0, history.Candles[0].C)
+}
@@ -0,0 +1,30 @@
+// Package exmo - клиент API биржи Exmo и модели рыночных данных.
+//
+// Клиент создается через NewExmo и настраивается опциями:
+//
+//	client := exmo.NewExmo(
+//		exmo.WithTimeout(10*time.Second),
+//		exmo.WithRetry(exmo.RetryPolicy{MaxAttempts: 3}),
+//		exmo.WithRateLimit(180, 10),
+//	)
+//	ticker, err := client.GetTicker()
+//
+// Публичные данные описывает интерфейс Exchanger, приватные методы - Trader
+// (нужен WithAPIKey). Ошибки биржи - *APIError, их категории проверяются
+// через errors.Is: ErrRateLimited, ErrInvalidPair, ErrAuth.
+//
+// Exchanger можно обернуть кэшем (NewCachingExchanger) и трассировкой
+// (NewTracingExchanger). Для тестов есть поддельный сервер exmotest.FakeExmo и
+// моки из пакета exmomock.
+//
+// # Версии
+//
+// Модуль лежит в подкаталоге репозитория github.com/Bobakek/GOLANG, поэтому
+// тег версии начинается с пути модуля внутри репозитория:
+//
+//	git tag go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/v0.3.0
+//	go get github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1@v0.3.0
+//
+// Версии следуют semver: пока мажорная версия 0, API может меняться в любом
+// минорном релизе. Для v2 и старше к пути модуля добавляется суффикс /v2.
+package exmo
@@ -0,0 +1,127 @@
+package exmo
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Ошибки-категории для проверки через errors.Is.
+var (
+	ErrRateLimited = errors.New("rate limit exceeded")
+	ErrInvalidPair = errors.New("invalid pair")
+	ErrAuth        = errors.New("authentication failed")
+)
+
+// APIError - ошибка, которую вернула биржа: либо HTTP-статус не 200,
+// либо тело вида {"result":false,"error":"..."}.
+type APIError struct {
+	Endpoint   string
+	StatusCode int
+	Code       int
+	Message    string
+	// Method и Attempt заполняет клиент, чтобы по ошибке был виден запрос
+	Method  string
+	Attempt int
+}
+
+func (e *APIError) Error() string {
+	where := e.Endpoint
+	if e.Method != "" {
+		where = e.Method + " " + e.Endpoint
+	}
+	msg := fmt.Sprintf("exmo %s: %s", where, e.Message)
+	if e.Code != 0 {
+		msg = fmt.Sprintf("exmo %s: error %d: %s (status %d)", where, e.Code, e.Message, e.StatusCode)
+	}
+	if e.Attempt > 1 {
+		msg += fmt.Sprintf(" (attempt %d)", e.Attempt)
+	}
+	return msg
+}
+
+// Is сопоставляет ошибку с ErrRateLimited, ErrInvalidPair и ErrAuth.
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.StatusCode == http.StatusTooManyRequests ||
+			containsAny(e.Message, "rate limit", "too many requests")
+	case ErrAuth:
+		return e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden ||
+			exmoAuthCodes[e.Code] ||
+			containsAny(e.Message, "authoriz", "api key", "signature", "nonce")
+	case ErrInvalidPair:
+		return containsAny(e.Message, "incorrect pair", "invalid pair", "pair not found", "wrong pair", "unknown pair")
+	}
+	return false
+}
+
+// Коды Exmo, означающие проблемы с ключом, подписью или nonce.
+var exmoAuthCodes = map[int]bool{
+	40003: true,
+	40004: true,
+	40005: true,
+	40009: true,
+	40017: true,
+	40032: true,
+	40034: true,
+}
+
+var exmoErrorCode = regexp.MustCompile(`^Error (\d+):\s*`)
+
+// newStatusError строит ошибку для ответа с кодом, отличным от 200.
+// Если тело содержит описание ошибки Exmo, оно попадает в сообщение.
+func newStatusError(endpoint string, status int, body []byte) *APIError {
+	if apiErr := parseErrorBody(endpoint, status, body); apiErr != nil {
+		return apiErr
+	}
+	return &APIError{
+		Endpoint:   endpoint,
+		StatusCode: status,
+		Message:    fmt.Sprintf("server returned non-200 status %d", status),
+	}
+}
+
+// parseErrorBody распознает тело {"result":false,"error":"Error 40005: ..."}.
+// Для остальных тел возвращает nil.
+func parseErrorBody(endpoint string, status int, body []byte) *APIError {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 || body[0] != '{' {
+		return nil
+	}
+	var envelope struct {
+		Result *bool  `json:"result"`
+		Error  string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil
+	}
+	if envelope.Error == "" && (envelope.Result == nil || *envelope.Result) {
+		return nil
+	}
+
+	apiErr := &APIError{Endpoint: endpoint, StatusCode: status, Message: envelope.Error}
+	if m := exmoErrorCode.FindStringSubmatch(envelope.Error); m != nil {
+		apiErr.Code, _ = strconv.Atoi(m[1])
+		apiErr.Message = envelope.Error[len(m[0]):]
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = "request failed without error message"
+	}
+	return apiErr
+}
+
+func containsAny(s string, substrs ...string) bool {
+	s = strings.ToLower(s)
+	for _, sub := range substrs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
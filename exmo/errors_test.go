@@ -0,0 +1,82 @@
+package exmo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseErrorBody(t *testing.T) {
+	testCases := []struct {
+		name    string
+		body    string
+		wantNil bool
+		code    int
+		message string
+	}{
+		{name: "exmo error with code", body: `{"result":false,"error":"Error 40005: Authorization error"}`, code: 40005, message: "Authorization error"},
+		{name: "error without code", body: `{"result":false,"error":"Incorrect pair"}`, message: "Incorrect pair"},
+		{name: "result false without message", body: `{"result":false}`, message: "request failed without error message"},
+		{name: "successful result", body: `{"result":true,"error":""}`, wantNil: true},
+		{name: "regular object", body: `{"BTC_USD":{"buy_price":"1"}}`, wantNil: true},
+		{name: "array", body: `["BTC"]`, wantNil: true},
+		{name: "invalid json", body: `{invalid`, wantNil: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			apiErr := parseErrorBody("/ticker", http.StatusOK, []byte(tc.body))
+			if tc.wantNil {
+				assert.Nil(t, apiErr)
+				return
+			}
+			require.NotNil(t, apiErr)
+			assert.Equal(t, "/ticker", apiErr.Endpoint)
+			assert.Equal(t, tc.code, apiErr.Code)
+			assert.Equal(t, tc.message, apiErr.Message)
+		})
+	}
+}
+
+func TestAPIError_Is(t *testing.T) {
+	testCases := []struct {
+		name   string
+		err    *APIError
+		target error
+		want   bool
+	}{
+		{name: "429 is rate limit", err: &APIError{StatusCode: 429}, target: ErrRateLimited, want: true},
+		{name: "rate limit message", err: &APIError{StatusCode: 200, Message: "Rate limit exceeded"}, target: ErrRateLimited, want: true},
+		{name: "401 is auth", err: &APIError{StatusCode: 401}, target: ErrAuth, want: true},
+		{name: "auth code", err: &APIError{StatusCode: 200, Code: 40017, Message: "Wrong api key"}, target: ErrAuth, want: true},
+		{name: "invalid pair", err: &APIError{StatusCode: 200, Message: "Incorrect pair"}, target: ErrInvalidPair, want: true},
+		{name: "server error is not auth", err: &APIError{StatusCode: 500}, target: ErrAuth},
+		{name: "unrelated target", err: &APIError{StatusCode: 429}, target: errors.New("other")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			wrapped := fmt.Errorf("wrapped: %w", tc.err)
+			assert.Equal(t, tc.want, errors.Is(wrapped, tc.target))
+
+			var apiErr *APIError
+			assert.True(t, errors.As(wrapped, &apiErr))
+		})
+	}
+}
+
+func TestAPIError_Error(t *testing.T) {
+	err := &APIError{Endpoint: "/ticker", StatusCode: 200, Code: 40005, Message: "Authorization error"}
+	assert.Equal(t, "exmo /ticker: error 40005: Authorization error (status 200)", err.Error())
+
+	err = newStatusError("/trades", http.StatusBadGateway, []byte("<html>"))
+	assert.Equal(t, "exmo /trades: server returned non-200 status 502", err.Error())
+
+	err = newStatusError("/trades", http.StatusTooManyRequests, []byte(`{"result":false,"error":"Too many requests"}`))
+	assert.Equal(t, "Too many requests", err.Message)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
@@ -1,10 +1,11 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: client.go
+// Source: ../client.go
 
-// Package main is a generated GoMock package.
-package main
+// Package exmomock is a generated GoMock package.
+package exmomock
 
 import (
+	exmo "github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
 	reflect "reflect"
 	time "time"
 
@@ -35,10 +36,10 @@ func (m *MockExchanger) EXPECT() *MockExchangerMockRecorder {
 }
 
 // GetCandlesHistory mocks base method.
-func (m *MockExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (CandlesHistory, error) {
+func (m *MockExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (exmo.CandlesHistory, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCandlesHistory", pair, period, start, end)
-	ret0, _ := ret[0].(CandlesHistory)
+	ret0, _ := ret[0].(exmo.CandlesHistory)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -65,10 +66,10 @@ func (mr *MockExchangerMockRecorder) GetClosePrice(pair, resolution, start, end
 }
 
 // GetCurrencies mocks base method.
-func (m *MockExchanger) GetCurrencies() (Currencies, error) {
+func (m *MockExchanger) GetCurrencies() (exmo.Currencies, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetCurrencies")
-	ret0, _ := ret[0].(Currencies)
+	ret0, _ := ret[0].(exmo.Currencies)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -80,14 +81,14 @@ func (mr *MockExchangerMockRecorder) GetCurrencies() *gomock.Call {
 }
 
 // GetOrderBook mocks base method.
-func (m *MockExchanger) GetOrderBook(limit int, pairs ...string) (OrderBook, error) {
+func (m *MockExchanger) GetOrderBook(limit int, pairs ...string) (exmo.OrderBook, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{limit}
 	for _, a := range pairs {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "GetOrderBook", varargs...)
-	ret0, _ := ret[0].(OrderBook)
+	ret0, _ := ret[0].(exmo.OrderBook)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -99,11 +100,26 @@ func (mr *MockExchangerMockRecorder) GetOrderBook(limit interface{}, pairs ...in
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderBook", reflect.TypeOf((*MockExchanger)(nil).GetOrderBook), varargs...)
 }
 
+// GetPairSettings mocks base method.
+func (m *MockExchanger) GetPairSettings() (exmo.PairSettings, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPairSettings")
+	ret0, _ := ret[0].(exmo.PairSettings)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPairSettings indicates an expected call of GetPairSettings.
+func (mr *MockExchangerMockRecorder) GetPairSettings() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPairSettings", reflect.TypeOf((*MockExchanger)(nil).GetPairSettings))
+}
+
 // GetTicker mocks base method.
-func (m *MockExchanger) GetTicker() (Ticker, error) {
+func (m *MockExchanger) GetTicker() (exmo.Ticker, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetTicker")
-	ret0, _ := ret[0].(Ticker)
+	ret0, _ := ret[0].(exmo.Ticker)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
@@ -115,14 +131,14 @@ func (mr *MockExchangerMockRecorder) GetTicker() *gomock.Call {
 }
 
 // GetTrades mocks base method.
-func (m *MockExchanger) GetTrades(pairs ...string) (Trades, error) {
+func (m *MockExchanger) GetTrades(pairs ...string) (exmo.Trades, error) {
 	m.ctrl.T.Helper()
 	varargs := []interface{}{}
 	for _, a := range pairs {
 		varargs = append(varargs, a)
 	}
 	ret := m.ctrl.Call(m, "GetTrades", varargs...)
-	ret0, _ := ret[0].(Trades)
+	ret0, _ := ret[0].(exmo.Trades)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
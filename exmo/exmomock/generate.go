@@ -0,0 +1,5 @@
+// Package exmomock содержит gomock-моки интерфейсов пакета exmo.
+package exmomock
+
+//go:generate mockgen -source=../client.go -destination=client.go -package=exmomock
+//go:generate mockgen -source=../orders.go -destination=orders.go -package=exmomock
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../orders.go
+
+// Package exmomock is a generated GoMock package.
+package exmomock
+
+import (
+	exmo "github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTrader is a mock of Trader interface.
+type MockTrader struct {
+	ctrl     *gomock.Controller
+	recorder *MockTraderMockRecorder
+}
+
+// MockTraderMockRecorder is the mock recorder for MockTrader.
+type MockTraderMockRecorder struct {
+	mock *MockTrader
+}
+
+// NewMockTrader creates a new mock instance.
+func NewMockTrader(ctrl *gomock.Controller) *MockTrader {
+	mock := &MockTrader{ctrl: ctrl}
+	mock.recorder = &MockTraderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTrader) EXPECT() *MockTraderMockRecorder {
+	return m.recorder
+}
+
+// CancelOrder mocks base method.
+func (m *MockTrader) CancelOrder(orderID int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockTraderMockRecorder) CancelOrder(orderID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockTrader)(nil).CancelOrder), orderID)
+}
+
+// CreateOrder mocks base method.
+func (m *MockTrader) CreateOrder(pair string, quantity, price float64, orderType exmo.OrderType) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", pair, quantity, price, orderType)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockTraderMockRecorder) CreateOrder(pair, quantity, price, orderType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockTrader)(nil).CreateOrder), pair, quantity, price, orderType)
+}
+
+// GetOpenOrders mocks base method.
+func (m *MockTrader) GetOpenOrders() (exmo.OpenOrders, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOpenOrders")
+	ret0, _ := ret[0].(exmo.OpenOrders)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOpenOrders indicates an expected call of GetOpenOrders.
+func (mr *MockTraderMockRecorder) GetOpenOrders() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOpenOrders", reflect.TypeOf((*MockTrader)(nil).GetOpenOrders))
+}
+
+// GetUserInfo mocks base method.
+func (m *MockTrader) GetUserInfo() (exmo.UserInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserInfo")
+	ret0, _ := ret[0].(exmo.UserInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserInfo indicates an expected call of GetUserInfo.
+func (mr *MockTraderMockRecorder) GetUserInfo() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserInfo", reflect.TypeOf((*MockTrader)(nil).GetUserInfo))
+}
+
+// GetUserTrades mocks base method.
+func (m *MockTrader) GetUserTrades(limit int, pairs ...string) (exmo.UserTrades, error) {
+	m.ctrl.T.Helper()
+	varargs := []interface{}{limit}
+	for _, a := range pairs {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "GetUserTrades", varargs...)
+	ret0, _ := ret[0].(exmo.UserTrades)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserTrades indicates an expected call of GetUserTrades.
+func (mr *MockTraderMockRecorder) GetUserTrades(limit interface{}, pairs ...interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]interface{}{limit}, pairs...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserTrades", reflect.TypeOf((*MockTrader)(nil).GetUserTrades), varargs...)
+}
@@ -0,0 +1,9 @@
+// Package exmotest - поддельный сервер Exmo для тестов. Он вынесен из
+// пакета exmo, чтобы программы, импортирующие клиент, не тянули
+// net/http/httptest.
+//
+//	fake := exmotest.NewFakeExmo()
+//	defer fake.Close()
+//	fake.AddOrder("BTC_USD", exmo.Sell, 100, 1)
+//	book, err := fake.Client().GetOrderBook(10, "BTC_USD")
+package exmotest
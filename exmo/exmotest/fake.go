@@ -0,0 +1,753 @@
+package exmotest
+
+import (
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// Fault описывает сбой, который FakeExmo вносит в ответы эндпоинта.
+// Поля применяются вместе: сначала задержка, затем подмена ответа.
+type Fault struct {
+	Latency   time.Duration // задержка перед ответом
+	Status    int           // HTTP-статус ответа, например 429 или 502
+	Body      string        // сырое тело ответа
+	Malformed bool          // отдать обрезанный JSON
+	Error     string        // тело {"result":false,"error":Error}
+	Times     int           // сколько запросов затронуть, 0 - все
+}
+
+// fakeEpsilon - остаток ордера, который считается нулевым.
+const fakeEpsilon = 1e-12
+
+// FakeExmo - поддельный сервер Exmo в том же процессе для интеграционных
+// тестов. Публичные данные задаются сеттерами, ордера пользователя
+// исполняются встроенным движком против ордеров, выставленных AddOrder.
+type FakeExmo struct {
+	server *httptest.Server
+
+	mu           sync.Mutex
+	ticker       exmo.Ticker
+	currencies   []string
+	candles      map[string][]exmo.Candle
+	pairSettings exmo.PairSettings
+	books        map[string]*fakeBook
+	publicTrades map[string][]exmo.Pair
+	faults       map[string][]*Fault
+	requests     map[string]int
+
+	key        string
+	secret     string
+	lastNonce  int64
+	balances   map[string]float64
+	reserved   map[string]float64
+	userTrades []exmo.UserTrade
+	nextID     int64
+	now        func() time.Time
+}
+
+type fakeOrder struct {
+	id       int64
+	user     bool
+	pair     string
+	side     exmo.Type
+	market   bool
+	price    float64
+	quantity float64
+	created  time.Time
+}
+
+// fakeBook хранит заявки: bids по убыванию цены, asks по возрастанию,
+// при равной цене - в порядке поступления.
+type fakeBook struct {
+	bids []*fakeOrder
+	asks []*fakeOrder
+}
+
+// FakeExmoOption настраивает FakeExmo.
+type FakeExmoOption func(*FakeExmo)
+
+// WithFakeCredentials включает проверку ключа, подписи и nonce приватных запросов.
+func WithFakeCredentials(key, secret string) FakeExmoOption {
+	return func(f *FakeExmo) {
+		f.key = key
+		f.secret = secret
+	}
+}
+
+// WithFakeClock подменяет часы сервера для времени сделок и ордеров.
+func WithFakeClock(now func() time.Time) FakeExmoOption {
+	return func(f *FakeExmo) {
+		f.now = now
+	}
+}
+
+// NewFakeExmo запускает сервер. Его нужно закрыть через Close.
+func NewFakeExmo(opts ...FakeExmoOption) *FakeExmo {
+	f := &FakeExmo{
+		ticker:       make(exmo.Ticker),
+		candles:      make(map[string][]exmo.Candle),
+		pairSettings: make(exmo.PairSettings),
+		books:        make(map[string]*fakeBook),
+		publicTrades: make(map[string][]exmo.Pair),
+		faults:       make(map[string][]*Fault),
+		requests:     make(map[string]int),
+		balances:     make(map[string]float64),
+		reserved:     make(map[string]float64),
+		nextID:       1,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	mux := http.NewServeMux()
+	for endpoint, h := range map[string]func(url.Values) (interface{}, error){
+		"/ticker":           f.handleTicker,
+		"/trades":           f.handleTrades,
+		"/order_book":       f.handleOrderBook,
+		"/currency":         f.handleCurrency,
+		"/candles_history":  f.handleCandles,
+		"/pair_settings":    f.handlePairSettings,
+		"/order_create":     f.handleOrderCreate,
+		"/order_cancel":     f.handleOrderCancel,
+		"/user_open_orders": f.handleOpenOrders,
+		"/user_trades":      f.handleUserTrades,
+		"/user_info":        f.handleUserInfo,
+	} {
+		mux.HandleFunc(endpoint, f.wrap(endpoint, h))
+	}
+	f.server = httptest.NewServer(mux)
+	return f
+}
+
+// URL возвращает адрес сервера для exmo.WithBaseURL.
+func (f *FakeExmo) URL() string {
+	return f.server.URL
+}
+
+func (f *FakeExmo) Close() {
+	f.server.Close()
+}
+
+// Client создает клиента Exmo, направленного на этот сервер.
+func (f *FakeExmo) Client(opts ...exmo.Option) *exmo.Exmo {
+	all := append([]exmo.Option{exmo.WithBaseURL(f.server.URL)}, opts...)
+	return exmo.NewExmo(all...).(*exmo.Exmo)
+}
+
+// Inject добавляет сбой для эндпоинта, например "/ticker".
+func (f *FakeExmo) Inject(endpoint string, fault Fault) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults[endpoint] = append(f.faults[endpoint], &fault)
+}
+
+// ClearFaults убирает все сбои.
+func (f *FakeExmo) ClearFaults() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.faults = make(map[string][]*Fault)
+}
+
+// Requests возвращает число запросов к эндпоинту, включая запросы со сбоями.
+func (f *FakeExmo) Requests(endpoint string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.requests[endpoint]
+}
+
+// SetTicker задает значения тикера. Для пар со стаканом, отсутствующих
+// в тикере, значения вычисляются по стакану.
+func (f *FakeExmo) SetTicker(ticker exmo.Ticker) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ticker = ticker
+}
+
+func (f *FakeExmo) SetCurrencies(currencies ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.currencies = currencies
+}
+
+func (f *FakeExmo) SetCandles(pair string, candles []exmo.Candle) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.candles[pair] = candles
+}
+
+func (f *FakeExmo) SetPairSettings(settings exmo.PairSettings) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pairSettings = settings
+}
+
+// SetBalance задает свободный баланс пользователя.
+func (f *FakeExmo) SetBalance(currency string, amount float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[currency] = amount
+}
+
+// AddOrder выставляет заявку стороннего участника с неограниченным балансом.
+// Если она пересекает заявки пользователя, они исполняются.
+func (f *FakeExmo) AddOrder(pair string, side exmo.Type, price, quantity float64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	order := &fakeOrder{id: f.nextID, pair: pair, side: side, price: price, quantity: quantity, created: f.now()}
+	f.nextID++
+	f.matchLocked(order)
+}
+
+func (f *FakeExmo) wrap(endpoint string, h func(url.Values) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		f.requests[endpoint]++
+		fault := f.takeFaultLocked(endpoint)
+		f.mu.Unlock()
+
+		if fault != nil {
+			if fault.Latency > 0 {
+				select {
+				case <-time.After(fault.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.Status != 0 || fault.Body != "" || fault.Malformed || fault.Error != "" {
+				writeFault(w, fault)
+				return
+			}
+		}
+
+		if err := r.ParseForm(); err != nil {
+			writeFakeError(w, "Error 40002: "+err.Error())
+			return
+		}
+		if r.Method == http.MethodPost {
+			if err := f.checkAuth(r); err != nil {
+				writeFakeError(w, err.Error())
+				return
+			}
+		}
+
+		result, err := h(r.Form)
+		if err != nil {
+			writeFakeError(w, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
+
+func (f *FakeExmo) takeFaultLocked(endpoint string) *Fault {
+	list := f.faults[endpoint]
+	if len(list) == 0 {
+		return nil
+	}
+	fault := list[0]
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			f.faults[endpoint] = list[1:]
+		}
+	}
+	copied := *fault
+	return &copied
+}
+
+func writeFault(w http.ResponseWriter, fault *Fault) {
+	status := fault.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	var body string
+	switch {
+	case fault.Body != "":
+		body = fault.Body
+	case fault.Error != "":
+		data, _ := json.Marshal(map[string]interface{}{"result": false, "error": fault.Error})
+		body = string(data)
+	case fault.Malformed:
+		body = `{"BTC_USD":{"buy_price":`
+	}
+	w.WriteHeader(status)
+	w.Write([]byte(body))
+}
+
+func writeFakeError(w http.ResponseWriter, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"result": false, "error": message})
+}
+
+func (f *FakeExmo) checkAuth(r *http.Request) error {
+	if f.key == "" {
+		return nil
+	}
+	if r.Header.Get("Key") != f.key {
+		return fmt.Errorf("Error 40017: Wrong API Key")
+	}
+	if !hmac.Equal([]byte(r.Header.Get("Sign")), []byte(signBody(f.secret, r.PostForm.Encode()))) {
+		return fmt.Errorf("Error 40005: Authorization error, incorrect signature")
+	}
+	nonce, err := strconv.ParseInt(r.PostForm.Get("nonce"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("Error 40004: Authorization error, incorrect nonce")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if nonce <= f.lastNonce {
+		return fmt.Errorf("Error 40009: The nonce parameter is less or equal than what was used before %d", f.lastNonce)
+	}
+	f.lastNonce = nonce
+	return nil
+}
+
+func (f *FakeExmo) handleTicker(url.Values) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make(exmo.Ticker, len(f.ticker)+len(f.books))
+	for pair, book := range f.books {
+		v := exmo.TickerValue{Updated: f.now().Unix()}
+		if len(book.bids) > 0 {
+			v.BuyPrice = formatFloat(book.bids[0].price)
+		}
+		if len(book.asks) > 0 {
+			v.SellPrice = formatFloat(book.asks[0].price)
+		}
+		if trades := f.publicTrades[pair]; len(trades) > 0 {
+			v.LastTrade = trades[0].Price
+		}
+		result[pair] = v
+	}
+	for pair, v := range f.ticker {
+		result[pair] = v
+	}
+	return result, nil
+}
+
+func (f *FakeExmo) handleTrades(form url.Values) (interface{}, error) {
+	pairs, err := f.pairsParam(form)
+	if err != nil {
+		return nil, err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make(exmo.Trades, len(pairs))
+	for _, pair := range pairs {
+		result[pair] = append([]exmo.Pair{}, f.publicTrades[pair]...)
+	}
+	return result, nil
+}
+
+func (f *FakeExmo) handleOrderBook(form url.Values) (interface{}, error) {
+	pairs, err := f.pairsParam(form)
+	if err != nil {
+		return nil, err
+	}
+	limit, _ := strconv.Atoi(form.Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make(exmo.OrderBook, len(pairs))
+	for _, pair := range pairs {
+		var p exmo.OrderBookPair
+		book := f.books[pair]
+		if book == nil {
+			book = &fakeBook{}
+		}
+		p.Ask, p.AskQuantity, p.AskAmount = aggregateLevels(book.asks, limit)
+		p.Bid, p.BidQuantity, p.BidAmount = aggregateLevels(book.bids, limit)
+		if len(p.Ask) > 0 {
+			p.AskTop = p.Ask[0][0]
+		}
+		if len(p.Bid) > 0 {
+			p.BidTop = p.Bid[0][0]
+		}
+		result[pair] = p
+	}
+	return result, nil
+}
+
+// aggregateLevels сворачивает заявки в уровни [цена, количество, сумма].
+func aggregateLevels(orders []*fakeOrder, limit int) ([][]string, string, string) {
+	levels := [][]string{}
+	var totalQty, totalAmount, qty float64
+	for i, o := range orders {
+		qty += o.quantity
+		totalQty += o.quantity
+		totalAmount += o.quantity * o.price
+		if i+1 < len(orders) && orders[i+1].price == o.price {
+			continue
+		}
+		if len(levels) < limit {
+			levels = append(levels, []string{formatFloat(o.price), formatFloat(qty), formatFloat(qty * o.price)})
+		}
+		qty = 0
+	}
+	return levels, formatFloat(totalQty), formatFloat(totalAmount)
+}
+
+func (f *FakeExmo) handleCurrency(url.Values) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string{}, f.currencies...), nil
+}
+
+func (f *FakeExmo) handleCandles(form url.Values) (interface{}, error) {
+	pair := form.Get("symbol")
+	from, errFrom := strconv.ParseInt(form.Get("from"), 10, 64)
+	to, errTo := strconv.ParseInt(form.Get("to"), 10, 64)
+	if pair == "" || errFrom != nil || errTo != nil {
+		return nil, fmt.Errorf("Error 40002: symbol, from and to are required")
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	candles, ok := f.candles[pair]
+	if !ok {
+		return nil, fmt.Errorf("Error 50304: Incorrect pair %s", pair)
+	}
+	result := exmo.CandlesHistory{Candles: []exmo.Candle{}}
+	for _, c := range candles {
+		if c.T >= from*1000 && c.T <= to*1000 {
+			result.Candles = append(result.Candles, c)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeExmo) handlePairSettings(url.Values) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.pairSettings, nil
+}
+
+func (f *FakeExmo) handleOrderCreate(form url.Values) (interface{}, error) {
+	pair := form.Get("pair")
+	base, quote, err := exmo.SplitPair(pair)
+	if err != nil {
+		return nil, fmt.Errorf("Error 50304: Incorrect pair %s", pair)
+	}
+	quantity, err := strconv.ParseFloat(form.Get("quantity"), 64)
+	if err != nil || quantity <= 0 {
+		return nil, fmt.Errorf("Error 50052: Incorrect quantity")
+	}
+	price, _ := strconv.ParseFloat(form.Get("price"), 64)
+	orderType := exmo.OrderType(form.Get("type"))
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if setting, ok := f.pairSettings[pair]; ok && !orderType.IsMarket() {
+		if err := setting.Validate(quantity, price); err != nil {
+			return nil, fmt.Errorf("Error 50277: %v", err)
+		}
+	}
+
+	order := &fakeOrder{id: f.nextID, user: true, pair: pair, side: orderType.Side(), price: price, quantity: quantity, created: f.now()}
+	switch orderType {
+	case exmo.OrderBuy:
+		if f.balances[quote] < quantity*price {
+			return nil, fmt.Errorf("Error 50052: Insufficient funds")
+		}
+		f.balances[quote] -= quantity * price
+		f.reserved[quote] += quantity * price
+	case exmo.OrderSell:
+		if f.balances[base] < quantity {
+			return nil, fmt.Errorf("Error 50052: Insufficient funds")
+		}
+		f.balances[base] -= quantity
+		f.reserved[base] += quantity
+	case exmo.OrderMarketBuy, exmo.OrderMarketSell:
+		// Рыночная заявка исполняется по любой цене и не остается в стакане
+		order.market = true
+	default:
+		return nil, fmt.Errorf("Error 50054: Incorrect order type %q", orderType)
+	}
+	f.nextID++
+	f.matchLocked(order)
+	return map[string]interface{}{"result": true, "error": "", "order_id": order.id}, nil
+}
+
+func (f *FakeExmo) handleOrderCancel(form url.Values) (interface{}, error) {
+	id, err := strconv.ParseInt(form.Get("order_id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("Error 50173: Order %q not found", form.Get("order_id"))
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, book := range f.books {
+		for _, side := range []*[]*fakeOrder{&book.bids, &book.asks} {
+			for i, o := range *side {
+				if o.id != id || !o.user {
+					continue
+				}
+				f.releaseLocked(o, o.quantity)
+				*side = append((*side)[:i], (*side)[i+1:]...)
+				return map[string]interface{}{"result": true, "error": ""}, nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("Error 50173: Order %d not found", id)
+}
+
+func (f *FakeExmo) handleOpenOrders(url.Values) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make(exmo.OpenOrders)
+	for pair, book := range f.books {
+		for _, side := range [][]*fakeOrder{book.bids, book.asks} {
+			for _, o := range side {
+				if !o.user {
+					continue
+				}
+				orderType := exmo.OrderBuy
+				if o.side == exmo.Sell {
+					orderType = exmo.OrderSell
+				}
+				result[pair] = append(result[pair], exmo.OpenOrder{
+					OrderID:  o.id,
+					Created:  o.created.Unix(),
+					Type:     orderType,
+					Pair:     pair,
+					Price:    formatFloat(o.price),
+					Quantity: formatFloat(o.quantity),
+					Amount:   formatFloat(o.price * o.quantity),
+				})
+			}
+		}
+		sort.Slice(result[pair], func(i, j int) bool { return result[pair][i].OrderID < result[pair][j].OrderID })
+	}
+	return result, nil
+}
+
+func (f *FakeExmo) handleUserTrades(form url.Values) (interface{}, error) {
+	pairs, err := f.pairsParam(form)
+	if err != nil {
+		return nil, err
+	}
+	limit, _ := strconv.Atoi(form.Get("limit"))
+	if limit <= 0 {
+		limit = 100
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	result := make(exmo.UserTrades, len(pairs))
+	for _, pair := range pairs {
+		result[pair] = []exmo.UserTrade{}
+	}
+	for i := len(f.userTrades) - 1; i >= 0; i-- {
+		t := f.userTrades[i]
+		if list, ok := result[t.Pair]; ok && len(list) < limit {
+			result[t.Pair] = append(list, t)
+		}
+	}
+	return result, nil
+}
+
+func (f *FakeExmo) handleUserInfo(url.Values) (interface{}, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	info := exmo.UserInfo{
+		UID:        1,
+		ServerDate: f.now().Unix(),
+		Balances:   make(map[string]string),
+		Reserved:   make(map[string]string),
+	}
+	for c, v := range f.balances {
+		info.Balances[c] = formatFloat(v)
+	}
+	for c, v := range f.reserved {
+		info.Reserved[c] = formatFloat(v)
+	}
+	return info, nil
+}
+
+func (f *FakeExmo) pairsParam(form url.Values) ([]string, error) {
+	raw := form.Get("pair")
+	if raw == "" {
+		return nil, fmt.Errorf("Error 40002: pair is required")
+	}
+	pairs := strings.Split(raw, ",")
+	for _, pair := range pairs {
+		if _, _, err := exmo.SplitPair(pair); err != nil {
+			return nil, fmt.Errorf("Error 50304: Incorrect pair %s", pair)
+		}
+	}
+	return pairs, nil
+}
+
+// matchLocked исполняет заявку против противоположной стороны стакана
+// по ценам стоящих заявок. Остаток лимитной заявки встает в стакан.
+func (f *FakeExmo) matchLocked(order *fakeOrder) {
+	book := f.books[order.pair]
+	if book == nil {
+		book = &fakeBook{}
+		f.books[order.pair] = book
+	}
+
+	opposite := &book.asks
+	crosses := func(resting *fakeOrder) bool { return order.market || resting.price <= order.price }
+	if order.side == exmo.Sell {
+		opposite = &book.bids
+		crosses = func(resting *fakeOrder) bool { return order.market || resting.price >= order.price }
+	}
+
+	for order.quantity > fakeEpsilon && len(*opposite) > 0 {
+		resting := (*opposite)[0]
+		if !crosses(resting) {
+			break
+		}
+		qty := minFloat(resting.quantity, order.quantity)
+		if order.user && order.market {
+			qty = f.affordableLocked(order, resting.price, qty)
+			if qty <= fakeEpsilon {
+				break
+			}
+		}
+		f.tradeLocked(order, resting, resting.price, qty)
+		resting.quantity -= qty
+		order.quantity -= qty
+		if resting.quantity <= fakeEpsilon {
+			*opposite = (*opposite)[1:]
+		}
+	}
+
+	if order.market || order.quantity <= fakeEpsilon {
+		return
+	}
+	if order.side == exmo.Buy {
+		book.bids = insertOrder(book.bids, order, func(a, b float64) bool { return a > b })
+	} else {
+		book.asks = insertOrder(book.asks, order, func(a, b float64) bool { return a < b })
+	}
+}
+
+// affordableLocked ограничивает рыночное исполнение свободным балансом пользователя.
+func (f *FakeExmo) affordableLocked(order *fakeOrder, price, qty float64) float64 {
+	base, quote, _ := exmo.SplitPair(order.pair)
+	if order.side == exmo.Buy {
+		return minFloat(qty, f.balances[quote]/price)
+	}
+	return minFloat(qty, f.balances[base])
+}
+
+func (f *FakeExmo) tradeLocked(taker, maker *fakeOrder, price, qty float64) {
+	now := f.now()
+	side := taker.side
+	trade := exmo.Pair{
+		TradeID:  f.nextID,
+		Date:     now.Unix(),
+		Type:     side,
+		Quantity: formatFloat(qty),
+		Price:    formatFloat(price),
+		Amount:   formatFloat(qty * price),
+	}
+	f.nextID++
+	f.publicTrades[taker.pair] = append([]exmo.Pair{trade}, f.publicTrades[taker.pair]...)
+
+	for _, o := range []*fakeOrder{taker, maker} {
+		if !o.user {
+			continue
+		}
+		execType := "taker"
+		if o == maker {
+			execType = "maker"
+		}
+		f.settleLocked(o, price, qty)
+		f.userTrades = append(f.userTrades, exmo.UserTrade{
+			TradeID:  trade.TradeID,
+			Date:     trade.Date,
+			Type:     o.side,
+			Pair:     o.pair,
+			OrderID:  o.id,
+			Quantity: trade.Quantity,
+			Price:    trade.Price,
+			Amount:   trade.Amount,
+			ExecType: execType,
+		})
+	}
+}
+
+// settleLocked проводит исполнение заявки пользователя по балансам.
+func (f *FakeExmo) settleLocked(o *fakeOrder, price, qty float64) {
+	base, quote, _ := exmo.SplitPair(o.pair)
+	if !o.market {
+		f.releaseLocked(o, qty)
+	}
+	if o.side == exmo.Buy {
+		f.balances[quote] -= price * qty
+		f.balances[base] += qty
+	} else {
+		f.balances[base] -= qty
+		f.balances[quote] += price * qty
+	}
+}
+
+func (f *FakeExmo) releaseLocked(o *fakeOrder, qty float64) {
+	base, quote, _ := exmo.SplitPair(o.pair)
+	currency, amount := base, qty
+	if o.side == exmo.Buy {
+		currency, amount = quote, qty*o.price
+	}
+	f.reserved[currency] -= amount
+	f.balances[currency] += amount
+	if f.reserved[currency] <= fakeEpsilon {
+		delete(f.reserved, currency)
+	}
+}
+
+func insertOrder(orders []*fakeOrder, o *fakeOrder, better func(a, b float64) bool) []*fakeOrder {
+	i := sort.Search(len(orders), func(i int) bool { return better(o.price, orders[i].price) })
+	orders = append(orders, nil)
+	copy(orders[i+1:], orders[i:])
+	orders[i] = o
+	return orders
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// signBody подписывает тело запроса так же, как клиент Exmo.
+func signBody(secret, body string) string {
+	mac := hmac.New(sha512.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
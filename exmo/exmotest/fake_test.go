@@ -0,0 +1,172 @@
+package exmotest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+func TestFakeExmo_PublicEndpoints(t *testing.T) {
+	fake := NewFakeExmo()
+	defer fake.Close()
+
+	fake.SetCurrencies("BTC", "USD")
+	fake.AddOrder("BTC_USD", exmo.Sell, 101, 1)
+	fake.AddOrder("BTC_USD", exmo.Sell, 101, 0.5)
+	fake.AddOrder("BTC_USD", exmo.Sell, 102, 2)
+	fake.AddOrder("BTC_USD", exmo.Buy, 99, 3)
+	start := time.Unix(1700000000, 0)
+	fake.SetCandles("BTC_USD", []exmo.Candle{
+		{T: start.UnixMilli(), O: 1, C: 2, H: 3, L: 1, V: 10},
+		{T: start.Add(time.Hour).UnixMilli(), O: 2, C: 4, H: 5, L: 2, V: 20},
+	})
+
+	client := fake.Client()
+
+	currencies, err := client.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, exmo.Currencies{"BTC": {}, "USD": {}}, currencies)
+
+	book, err := client.GetOrderBook(10, "BTC_USD")
+	require.NoError(t, err)
+	asks, err := book["BTC_USD"].Asks()
+	require.NoError(t, err)
+	assert.Equal(t, []exmo.BookLevel{{Price: 101, Quantity: 1.5}, {Price: 102, Quantity: 2}}, asks)
+	assert.Equal(t, "99", book["BTC_USD"].BidTop)
+
+	ticker, err := client.GetTicker()
+	require.NoError(t, err)
+	assert.Equal(t, "99", ticker["BTC_USD"].BuyPrice)
+	assert.Equal(t, "101", ticker["BTC_USD"].SellPrice)
+
+	prices, err := client.GetClosePrice("BTC_USD", 60, start, start.Add(30*time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, []float64{2}, prices)
+
+	_, err = client.GetTrades("BTCUSD")
+	assert.True(t, errors.Is(err, exmo.ErrInvalidPair), "got %v", err)
+}
+
+func TestFakeExmo_OrderLifecycle(t *testing.T) {
+	fake := NewFakeExmo(WithFakeCredentials("key", "secret"))
+	defer fake.Close()
+
+	fake.SetBalance("USD", 1000)
+	fake.AddOrder("BTC_USD", exmo.Sell, 110, 1)
+
+	client := fake.Client(exmo.WithAPIKey("key", "secret"))
+
+	// Лимитная покупка ниже рынка встает в стакан и резервирует средства
+	id, err := client.CreateOrder("BTC_USD", 2, 100, exmo.OrderBuy)
+	require.NoError(t, err)
+
+	open, err := client.GetOpenOrders()
+	require.NoError(t, err)
+	require.Len(t, open["BTC_USD"], 1)
+	assert.Equal(t, id, open["BTC_USD"][0].OrderID)
+
+	info, err := client.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "800", info.Balances["USD"])
+	assert.Equal(t, "200", info.Reserved["USD"])
+
+	// Встречная заявка исполняет часть лимитной заявки
+	fake.AddOrder("BTC_USD", exmo.Sell, 100, 0.5)
+
+	trades, err := client.GetUserTrades(10, "BTC_USD")
+	require.NoError(t, err)
+	require.Len(t, trades["BTC_USD"], 1)
+	assert.Equal(t, "maker", trades["BTC_USD"][0].ExecType)
+	assert.Equal(t, "0.5", trades["BTC_USD"][0].Quantity)
+
+	require.NoError(t, client.CancelOrder(id))
+	info, err = client.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "950", info.Balances["USD"])
+	assert.Equal(t, "0.5", info.Balances["BTC"])
+	assert.Empty(t, info.Reserved)
+
+	// Рыночная покупка забирает лучшую цену
+	_, err = client.CreateOrder("BTC_USD", 1, 0, exmo.OrderMarketBuy)
+	require.NoError(t, err)
+	info, err = client.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "840", info.Balances["USD"])
+	assert.Equal(t, "1.5", info.Balances["BTC"])
+
+	err = client.CancelOrder(id)
+	var apiErr *exmo.APIError
+	require.True(t, errors.As(err, &apiErr), "got %v", err)
+	assert.Equal(t, 50173, apiErr.Code)
+
+	_, err = client.CreateOrder("BTC_USD", 100, 100, exmo.OrderBuy)
+	require.Error(t, err)
+}
+
+func TestFakeExmo_Auth(t *testing.T) {
+	fake := NewFakeExmo(WithFakeCredentials("key", "secret"))
+	defer fake.Close()
+
+	_, err := fake.Client(exmo.WithAPIKey("other", "secret")).GetUserInfo()
+	assert.True(t, errors.Is(err, exmo.ErrAuth), "got %v", err)
+
+	_, err = fake.Client(exmo.WithAPIKey("key", "wrong")).GetUserInfo()
+	assert.True(t, errors.Is(err, exmo.ErrAuth), "got %v", err)
+
+	_, err = fake.Client().GetUserInfo()
+	assert.True(t, errors.Is(err, exmo.ErrAuth), "got %v", err)
+
+	client := fake.Client(exmo.WithAPIKey("key", "secret"))
+	_, err = client.GetUserInfo()
+	require.NoError(t, err)
+
+	// Nonce не больше уже использованного отклоняется
+	fake.mu.Lock()
+	fake.lastNonce += 1 << 40
+	fake.mu.Unlock()
+	_, err = client.GetUserInfo()
+	assert.True(t, errors.Is(err, exmo.ErrAuth), "got %v", err)
+}
+
+func TestFakeExmo_Faults(t *testing.T) {
+	fake := NewFakeExmo()
+	defer fake.Close()
+	fake.SetCurrencies("BTC")
+	client := fake.Client()
+
+	fake.Inject("/currency", Fault{Status: 429, Body: "Too Many Requests", Times: 1})
+	_, err := client.GetCurrencies()
+	assert.True(t, errors.Is(err, exmo.ErrRateLimited), "got %v", err)
+
+	_, err = client.GetCurrencies()
+	require.NoError(t, err)
+
+	fake.Inject("/currency", Fault{Malformed: true, Times: 1})
+	_, err = client.GetCurrencies()
+	assert.ErrorContains(t, err, "decode response")
+
+	fake.Inject("/currency", Fault{Error: "Error 40005: Authorization error", Times: 1})
+	_, err = client.GetCurrencies()
+	assert.True(t, errors.Is(err, exmo.ErrAuth), "got %v", err)
+
+	fake.Inject("/currency", Fault{Latency: 50 * time.Millisecond, Times: 1})
+	began := time.Now()
+	_, err = client.GetCurrencies()
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(began), 50*time.Millisecond)
+
+	fake.Inject("/currency", Fault{Status: 502})
+	for i := 0; i < 2; i++ {
+		_, err = client.GetCurrencies()
+		require.Error(t, err)
+	}
+	fake.ClearFaults()
+	_, err = client.GetCurrencies()
+	require.NoError(t, err)
+	assert.Equal(t, 8, fake.Requests("/currency"))
+}
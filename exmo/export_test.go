@@ -0,0 +1,23 @@
+package exmo
+
+import "time"
+
+// Доступ к внутренностям клиента для тестов пакета exmo_test, которые
+// работают с поддельным сервером из exmotest.
+
+// WithSleep подменяет ожидание между попытками и в ограничителе запросов.
+func WithSleep(sleep func(time.Duration)) Option {
+	return func(e *Exmo) {
+		e.sleep = sleep
+	}
+}
+
+func (e *Exmo) Nonce() int64 {
+	return e.nonce
+}
+
+func (e *Exmo) Redact(s string) string {
+	return e.redact(s)
+}
+
+var SignBody = signBody
@@ -0,0 +1,66 @@
+package exmo_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+func scrape(t *testing.T, m *telemetry.Metrics) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, m.WritePrometheus(&b))
+	return b.String()
+}
+
+func TestMetrics_Client(t *testing.T) {
+	fake := exmotest.NewFakeExmo()
+	defer fake.Close()
+	fake.SetTicker(exmo.Ticker{"BTC_USD": {LastTrade: "100"}})
+	fake.Inject("/ticker", exmotest.Fault{Status: http.StatusBadGateway, Times: 1})
+
+	m := telemetry.NewMetrics()
+	client := fake.Client(exmo.WithMetrics(m),
+		exmo.WithRetry(exmo.RetryPolicy{MaxAttempts: 2}),
+		exmo.WithRateLimit(60, 1),
+		exmo.WithSleep(func(time.Duration) {}),
+	)
+	_, err := client.GetTicker()
+	require.NoError(t, err)
+
+	fake.Inject("/currency", exmotest.Fault{Malformed: true})
+	_, err = client.GetCurrencies()
+	require.Error(t, err)
+
+	out := scrape(t, m)
+	for _, line := range []string{
+		`# TYPE exmo_requests_total counter`,
+		`exmo_requests_total{endpoint="/ticker",code="2xx"} 1`,
+		`exmo_requests_total{endpoint="/ticker",code="5xx"} 1`,
+		`exmo_requests_total{endpoint="/currency",code="2xx"} 1`,
+		`exmo_retries_total{endpoint="/ticker"} 1`,
+		`exmo_decode_errors_total{endpoint="/currency"} 1`,
+		`exmo_request_duration_seconds_bucket{endpoint="/ticker",le="+Inf"} 2`,
+		`exmo_request_duration_seconds_count{endpoint="/ticker"} 2`,
+		// Первый запрос занимает единственный маркер, остальные ждут
+		`exmo_rate_limit_waits_total 2`,
+	} {
+		assert.Contains(t, out, line+"\n")
+	}
+}
+
+func TestMetrics_NetworkError(t *testing.T) {
+	m := telemetry.NewMetrics()
+	client := exmo.NewExmo(exmo.WithBaseURL("http://127.0.0.1:1"), exmo.WithMetrics(m))
+	_, err := client.GetTicker()
+	require.Error(t, err)
+	assert.Contains(t, scrape(t, m), `exmo_requests_total{endpoint="/ticker",code="error"} 1`)
+}
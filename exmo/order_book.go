@@ -0,0 +1,69 @@
+package exmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+func UnmarshalOrderBook(data []byte) (OrderBook, error) {
+	var r OrderBook
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *OrderBook) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// OrderBook - ответ /order_book: стаканы по парам.
+type OrderBook map[string]OrderBookPair
+
+// OrderBookPair - стакан пары. Уровни Ask и Bid - строки [цена, количество,
+// сумма], разобрать их можно методами Asks и Bids.
+type OrderBookPair struct {
+	AskQuantity string     `json:"ask_quantity"`
+	AskAmount   string     `json:"ask_amount"`
+	AskTop      string     `json:"ask_top"`
+	BidQuantity string     `json:"bid_quantity"`
+	BidAmount   string     `json:"bid_amount"`
+	BidTop      string     `json:"bid_top"`
+	Ask         [][]string `json:"ask"`
+	Bid         [][]string `json:"bid"`
+}
+
+
+// BookLevel - разобранный уровень стакана.
+type BookLevel struct {
+	Price    float64
+	Quantity float64
+}
+
+// Asks возвращает уровни на продажу в порядке, полученном от биржи (по возрастанию цены).
+func (p OrderBookPair) Asks() ([]BookLevel, error) {
+	return parseBookLevels(p.Ask)
+}
+
+// Bids возвращает уровни на покупку в порядке, полученном от биржи (по убыванию цены).
+func (p OrderBookPair) Bids() ([]BookLevel, error) {
+	return parseBookLevels(p.Bid)
+}
+
+func parseBookLevels(raw [][]string) ([]BookLevel, error) {
+	levels := make([]BookLevel, 0, len(raw))
+	for _, row := range raw {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("malformed order book level %v", row)
+		}
+		price, err := strconv.ParseFloat(row[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse price %q: %w", row[0], err)
+		}
+		qty, err := strconv.ParseFloat(row[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse quantity %q: %w", row[1], err)
+		}
+		levels = append(levels, BookLevel{Price: price, Quantity: qty})
+	}
+	return levels, nil
+}
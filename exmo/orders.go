@@ -0,0 +1,108 @@
+package exmo
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Trader описывает приватное API биржи для работы с ордерами и балансами.
+// Методы требуют ключей, заданных через WithAPIKey.
+type Trader interface {
+	CreateOrder(pair string, quantity, price float64, orderType OrderType) (int64, error)
+	CancelOrder(orderID int64) error
+	GetOpenOrders() (OpenOrders, error)
+	GetUserTrades(limit int, pairs ...string) (UserTrades, error)
+	GetUserInfo() (UserInfo, error)
+}
+
+// OrderType - тип ордера для CreateOrder.
+type OrderType string
+
+const (
+	OrderBuy        OrderType = "buy"
+	OrderSell       OrderType = "sell"
+	OrderMarketBuy  OrderType = "market_buy"
+	OrderMarketSell OrderType = "market_sell"
+)
+
+// IsMarket сообщает, исполняется ли ордер по рынку.
+func (t OrderType) IsMarket() bool {
+	return t == OrderMarketBuy || t == OrderMarketSell
+}
+
+// Side возвращает направление сделки без учета типа исполнения.
+func (t OrderType) Side() Type {
+	if t == OrderBuy || t == OrderMarketBuy {
+		return Buy
+	}
+	return Sell
+}
+
+func UnmarshalOpenOrders(data []byte) (OpenOrders, error) {
+	var r OpenOrders
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *OpenOrders) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// OpenOrders - ответ /user_open_orders по парам.
+type OpenOrders map[string][]OpenOrder
+
+type OpenOrder struct {
+	OrderID  int64     `json:"order_id,string"`
+	Created  int64     `json:"created,string"`
+	Type     OrderType `json:"type"`
+	Pair     string    `json:"pair"`
+	Price    string    `json:"price"`
+	Quantity string    `json:"quantity"`
+	Amount   string    `json:"amount"`
+}
+
+func UnmarshalUserTrades(data []byte) (UserTrades, error) {
+	var r UserTrades
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *UserTrades) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// UserTrades - ответ /user_trades по парам.
+type UserTrades map[string][]UserTrade
+
+type UserTrade struct {
+	TradeID            int64  `json:"trade_id"`
+	Date               int64  `json:"date"`
+	Type               Type   `json:"type"`
+	Pair               string `json:"pair"`
+	OrderID            int64  `json:"order_id"`
+	Quantity           string `json:"quantity"`
+	Price              string `json:"price"`
+	Amount             string `json:"amount"`
+	ExecType           string `json:"exec_type"`
+	CommissionAmount   string `json:"commission_amount"`
+	CommissionCurrency string `json:"commission_currency"`
+	CommissionPercent  string `json:"commission_percent"`
+}
+
+// UserInfo - ответ /user_info: свободные и зарезервированные балансы.
+type UserInfo struct {
+	UID        int64             `json:"uid"`
+	ServerDate int64             `json:"server_date"`
+	Balances   map[string]string `json:"balances"`
+	Reserved   map[string]string `json:"reserved"`
+}
+
+// SplitPair разбивает пару вида BTC_USD на базовую и котируемую валюты.
+func SplitPair(pair string) (base, quote string, err error) {
+	parts := strings.Split(pair, "_")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", fmt.Errorf("invalid pair %q", pair)
+	}
+	return parts[0], parts[1], nil
+}
@@ -0,0 +1,43 @@
+package exmo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOpenOrdersMarshaling(t *testing.T) {
+	t.Run("unmarshal exmo response", func(t *testing.T) {
+		data := []byte(`{"BTC_USD":[{"order_id":"14","created":"1435517311","type":"buy","pair":"BTC_USD","price":"100","quantity":"1","amount":"100"}]}`)
+		orders, err := UnmarshalOpenOrders(data)
+
+		assert.NoError(t, err)
+		assert.Equal(t, int64(14), orders["BTC_USD"][0].OrderID)
+		assert.Equal(t, OrderBuy, orders["BTC_USD"][0].Type)
+	})
+
+	t.Run("marshal keeps string ids", func(t *testing.T) {
+		orders := OpenOrders{"BTC_USD": {{OrderID: 7}}}
+		data, err := orders.Marshal()
+
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), `"order_id":"7"`)
+	})
+}
+
+func TestOrderType(t *testing.T) {
+	assert.True(t, OrderMarketBuy.IsMarket())
+	assert.False(t, OrderSell.IsMarket())
+	assert.Equal(t, Buy, OrderMarketBuy.Side())
+	assert.Equal(t, Sell, OrderSell.Side())
+}
+
+func TestSplitPair(t *testing.T) {
+	base, quote, err := SplitPair("BTC_USD")
+	assert.NoError(t, err)
+	assert.Equal(t, "BTC", base)
+	assert.Equal(t, "USD", quote)
+
+	_, _, err = SplitPair("BTCUSD")
+	assert.Error(t, err)
+}
@@ -0,0 +1,140 @@
+package exmo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidOrder возвращает PairSetting.Validate для ордера вне ограничений пары.
+var ErrInvalidOrder = errors.New("order violates pair settings")
+
+func UnmarshalPairSettings(data []byte) (PairSettings, error) {
+	var r PairSettings
+	err := json.Unmarshal(data, &r)
+	return r, err
+}
+
+func (r *PairSettings) Marshal() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+// PairSettings - ответ /pair_settings: ограничения ордеров по парам.
+type PairSettings map[string]PairSetting
+
+type PairSetting struct {
+	MinQuantity            float64 `json:"min_quantity,string"`
+	MaxQuantity            float64 `json:"max_quantity,string"`
+	MinPrice               float64 `json:"min_price,string"`
+	MaxPrice               float64 `json:"max_price,string"`
+	MinAmount              float64 `json:"min_amount,string"`
+	MaxAmount              float64 `json:"max_amount,string"`
+	PricePrecision         int     `json:"price_precision"`
+	CommissionTakerPercent float64 `json:"commission_taker_percent,string"`
+	CommissionMakerPercent float64 `json:"commission_maker_percent,string"`
+}
+
+// Pairs возвращает отсортированный список всех пар.
+func (s PairSettings) Pairs() []string {
+	pairs := make([]string, 0, len(s))
+	for pair := range s {
+		pairs = append(pairs, pair)
+	}
+	sort.Strings(pairs)
+	return pairs
+}
+
+// Filter возвращает пары с указанной базовой и котируемой валютой.
+// Пустая строка означает любую валюту.
+func (s PairSettings) Filter(base, quote string) []string {
+	var result []string
+	for _, pair := range s.Pairs() {
+		b, q, err := SplitPair(pair)
+		if err != nil {
+			continue
+		}
+		if (base == "" || b == base) && (quote == "" || q == quote) {
+			result = append(result, pair)
+		}
+	}
+	return result
+}
+
+// Lookup ищет настройки пары по базовой и котируемой валюте.
+func (s PairSettings) Lookup(base, quote string) (string, PairSetting, bool) {
+	pair := strings.ToUpper(base) + "_" + strings.ToUpper(quote)
+	setting, ok := s[pair]
+	return pair, setting, ok
+}
+
+// PriceTick - минимальный шаг цены.
+func (p PairSetting) PriceTick() float64 {
+	return math.Pow10(-p.PricePrecision)
+}
+
+// QuantityStep - шаг количества. Биржа не отдает его явно, поэтому он
+// выводится из числа знаков минимального количества.
+func (p PairSetting) QuantityStep() float64 {
+	return math.Pow10(-decimals(p.MinQuantity))
+}
+
+// QuantizePrice округляет цену до ближайшего шага цены.
+func (p PairSetting) QuantizePrice(price float64) float64 {
+	return quantize(price, p.PricePrecision, math.Round)
+}
+
+// QuantizeQuantity округляет количество вниз до шага, чтобы не превысить доступный объем.
+func (p PairSetting) QuantizeQuantity(quantity float64) float64 {
+	return quantize(quantity, decimals(p.MinQuantity), math.Floor)
+}
+
+// Validate проверяет ордер на соответствие ограничениям пары.
+func (p PairSetting) Validate(quantity, price float64) error {
+	if quantity < p.MinQuantity {
+		return fmt.Errorf("%w: quantity %v is below minimum %v", ErrInvalidOrder, quantity, p.MinQuantity)
+	}
+	if p.MaxQuantity > 0 && quantity > p.MaxQuantity {
+		return fmt.Errorf("%w: quantity %v is above maximum %v", ErrInvalidOrder, quantity, p.MaxQuantity)
+	}
+	if price < p.MinPrice {
+		return fmt.Errorf("%w: price %v is below minimum %v", ErrInvalidOrder, price, p.MinPrice)
+	}
+	if p.MaxPrice > 0 && price > p.MaxPrice {
+		return fmt.Errorf("%w: price %v is above maximum %v", ErrInvalidOrder, price, p.MaxPrice)
+	}
+	if q := p.QuantizePrice(price); math.Abs(q-price) > p.PriceTick()/1e6 {
+		return fmt.Errorf("%w: price %v is not a multiple of tick %v", ErrInvalidOrder, price, p.PriceTick())
+	}
+	amount := quantity * price
+	if amount < p.MinAmount {
+		return fmt.Errorf("%w: amount %v is below minimum %v", ErrInvalidOrder, amount, p.MinAmount)
+	}
+	if p.MaxAmount > 0 && amount > p.MaxAmount {
+		return fmt.Errorf("%w: amount %v is above maximum %v", ErrInvalidOrder, amount, p.MaxAmount)
+	}
+	return nil
+}
+
+// decimals возвращает число знаков после запятой в кратчайшей записи числа.
+func decimals(v float64) int {
+	s := strconv.FormatFloat(v, 'f', -1, 64)
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return len(s) - i - 1
+	}
+	return 0
+}
+
+func quantize(v float64, precision int, round func(float64) float64) float64 {
+	scale := math.Pow10(precision)
+	// Небольшой сдвиг гасит ошибку представления вроде 0.29*100 = 28.999999999999996
+	shifted := v * scale
+	if r := math.Round(shifted); math.Abs(shifted-r) < 1e-9 {
+		shifted = r
+	}
+	result, _ := strconv.ParseFloat(strconv.FormatFloat(round(shifted)/scale, 'f', precision, 64), 64)
+	return result
+}
@@ -0,0 +1,90 @@
+package exmo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pairSettingsJSON = `{
+	"BTC_USD":{"min_quantity":"0.0001","max_quantity":"1000","min_price":"1","max_price":"30000000","max_amount":"500000","min_amount":"1","price_precision":2,"commission_taker_percent":"0.3","commission_maker_percent":"0.2"},
+	"ETH_BTC":{"min_quantity":"0.001","max_quantity":"5000","min_price":"0.00000001","max_price":"10","max_amount":"100","min_amount":"0.0001","price_precision":6,"commission_taker_percent":"0.3","commission_maker_percent":"0.2"},
+	"ETH_USD":{"min_quantity":"0.001","max_quantity":"5000","min_price":"0.01","max_price":"100000","max_amount":"500000","min_amount":"1","price_precision":2,"commission_taker_percent":"0.3","commission_maker_percent":"0.2"}
+}`
+
+func TestPairSettingsMarshaling(t *testing.T) {
+	settings, err := UnmarshalPairSettings([]byte(pairSettingsJSON))
+
+	require.NoError(t, err)
+	btc := settings["BTC_USD"]
+	assert.Equal(t, 0.0001, btc.MinQuantity)
+	assert.Equal(t, 2, btc.PricePrecision)
+	assert.Equal(t, 0.3, btc.CommissionTakerPercent)
+
+	data, err := settings.Marshal()
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"min_quantity":"0.0001"`)
+}
+
+func TestPairSettings_Listing(t *testing.T) {
+	settings, err := UnmarshalPairSettings([]byte(pairSettingsJSON))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"BTC_USD", "ETH_BTC", "ETH_USD"}, settings.Pairs())
+	assert.Equal(t, []string{"ETH_BTC", "ETH_USD"}, settings.Filter("ETH", ""))
+	assert.Equal(t, []string{"BTC_USD", "ETH_USD"}, settings.Filter("", "USD"))
+
+	pair, setting, ok := settings.Lookup("eth", "btc")
+	assert.True(t, ok)
+	assert.Equal(t, "ETH_BTC", pair)
+	assert.Equal(t, 6, setting.PricePrecision)
+
+	_, _, ok = settings.Lookup("BTC", "EUR")
+	assert.False(t, ok)
+}
+
+func TestPairSetting_Quantize(t *testing.T) {
+	settings, err := UnmarshalPairSettings([]byte(pairSettingsJSON))
+	require.NoError(t, err)
+	btc := settings["BTC_USD"]
+
+	assert.Equal(t, 0.01, btc.PriceTick())
+	assert.Equal(t, 0.0001, btc.QuantityStep())
+	assert.Equal(t, 50123.46, btc.QuantizePrice(50123.456))
+	assert.Equal(t, 0.29, btc.QuantizePrice(0.29))
+	assert.Equal(t, 0.1234, btc.QuantizeQuantity(0.12349))
+	assert.Equal(t, 0.0003, btc.QuantizeQuantity(0.0003))
+}
+
+func TestPairSetting_Validate(t *testing.T) {
+	settings, err := UnmarshalPairSettings([]byte(pairSettingsJSON))
+	require.NoError(t, err)
+	btc := settings["BTC_USD"]
+
+	testCases := []struct {
+		name     string
+		quantity float64
+		price    float64
+		wantErr  bool
+	}{
+		{name: "valid", quantity: 0.01, price: 50000.5},
+		{name: "quantity below minimum", quantity: 0.00001, price: 50000, wantErr: true},
+		{name: "quantity above maximum", quantity: 2000, price: 100, wantErr: true},
+		{name: "price below minimum", quantity: 1, price: 0.5, wantErr: true},
+		{name: "price off tick", quantity: 0.01, price: 50000.123, wantErr: true},
+		{name: "amount below minimum", quantity: 0.0001, price: 5, wantErr: true},
+		{name: "amount above maximum", quantity: 100, price: 50000, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := btc.Validate(tc.quantity, tc.price)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidOrder)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -1,7 +1,8 @@
-package main
+package exmo
 
 import "encoding/json"
 
+// Ticker - ответ /ticker: значения по парам вида BTC_USD.
 type Ticker map[string]TickerValue
 
 func UnmarshalTicker(data []byte) (Ticker, error) {
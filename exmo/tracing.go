@@ -0,0 +1,114 @@
+package exmo
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// ContextExchanger - Exchanger, который можно привязать к контексту
+// вызывающего, чтобы его спаны стали дочерними.
+type ContextExchanger interface {
+	Exchanger
+	WithContext(ctx context.Context) Exchanger
+}
+
+// BindContext привязывает ex к ctx, если он это поддерживает.
+func BindContext(ctx context.Context, ex Exchanger) Exchanger {
+	if c, ok := ex.(ContextExchanger); ok {
+		return c.WithContext(ctx)
+	}
+	return ex
+}
+
+// TracingExchanger открывает спан на каждый вызов вложенного Exchanger.
+// Атрибуты: эндпоинт Exmo, пары, лимит и размер ответа в элементах.
+type TracingExchanger struct {
+	next   Exchanger
+	tracer *telemetry.Tracer
+	ctx    context.Context
+}
+
+// NewTracingExchanger оборачивает next. С nil-трассировщиком спаны не пишутся.
+func NewTracingExchanger(next Exchanger, tracer *telemetry.Tracer) *TracingExchanger {
+	return &TracingExchanger{next: next, tracer: tracer, ctx: context.Background()}
+}
+
+// WithContext возвращает копию, чьи спаны - потомки спана из ctx.
+func (t *TracingExchanger) WithContext(ctx context.Context) Exchanger {
+	c := *t
+	c.ctx = ctx
+	return &c
+}
+
+func (t *TracingExchanger) start(method, endpoint string, attrs ...telemetry.Attribute) *telemetry.ActiveSpan {
+	attrs = append([]telemetry.Attribute{telemetry.StringAttr("exmo.endpoint", endpoint)}, attrs...)
+	_, span := t.tracer.Start(t.ctx, "Exchanger."+method, telemetry.SpanClient, attrs...)
+	return span
+}
+
+func finishSpan(span *telemetry.ActiveSpan, size int, err error) {
+	if err == nil {
+		span.SetAttributes(telemetry.IntAttr("exmo.response_size", size))
+	}
+	span.End(err)
+}
+
+func (t *TracingExchanger) GetTicker() (Ticker, error) {
+	span := t.start("GetTicker", "/ticker")
+	v, err := t.next.GetTicker()
+	finishSpan(span, len(v), err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetTrades(pairs ...string) (Trades, error) {
+	span := t.start("GetTrades", "/trades", telemetry.StringAttr("exmo.pair", strings.Join(pairs, ",")))
+	v, err := t.next.GetTrades(pairs...)
+	size := 0
+	for _, trades := range v {
+		size += len(trades)
+	}
+	finishSpan(span, size, err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetOrderBook(limit int, pairs ...string) (OrderBook, error) {
+	span := t.start("GetOrderBook", "/order_book", telemetry.StringAttr("exmo.pair", strings.Join(pairs, ",")), telemetry.IntAttr("exmo.limit", limit))
+	v, err := t.next.GetOrderBook(limit, pairs...)
+	size := 0
+	for _, book := range v {
+		size += len(book.Ask) + len(book.Bid)
+	}
+	finishSpan(span, size, err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetCurrencies() (Currencies, error) {
+	span := t.start("GetCurrencies", "/currency")
+	v, err := t.next.GetCurrencies()
+	finishSpan(span, len(v), err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetPairSettings() (PairSettings, error) {
+	span := t.start("GetPairSettings", "/pair_settings")
+	v, err := t.next.GetPairSettings()
+	finishSpan(span, len(v), err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (CandlesHistory, error) {
+	span := t.start("GetCandlesHistory", "/candles_history", telemetry.StringAttr("exmo.pair", pair), telemetry.IntAttr("exmo.resolution", period))
+	v, err := t.next.GetCandlesHistory(pair, period, start, end)
+	finishSpan(span, len(v.Candles), err)
+	return v, err
+}
+
+func (t *TracingExchanger) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	span := t.start("GetClosePrice", "/candles_history", telemetry.StringAttr("exmo.pair", pair), telemetry.IntAttr("exmo.resolution", resolution))
+	v, err := t.next.GetClosePrice(pair, resolution, start, end)
+	finishSpan(span, len(v), err)
+	return v, err
+}
@@ -0,0 +1,40 @@
+package exmo_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+func TestTracing_ExchangerErrorAndRoot(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	mock.EXPECT().GetOrderBook(10, "BTC_USD", "ETH_USD").Return(exmo.OrderBook{
+		"BTC_USD": {Ask: [][]string{{"1", "1", "1"}}, Bid: [][]string{{"1", "1", "1"}, {"1", "1", "1"}}},
+	}, nil)
+	mock.EXPECT().GetTicker().Return(nil, errors.New("boom"))
+
+	exporter := telemetry.NewInMemoryExporter()
+	ex := exmo.NewTracingExchanger(mock, telemetry.NewTracer(exporter))
+	_, err := ex.GetOrderBook(10, "BTC_USD", "ETH_USD")
+	require.NoError(t, err)
+	_, err = ex.GetTicker()
+	require.Error(t, err)
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 2)
+	assert.Empty(t, spans[0].ParentID)
+	assert.Equal(t, int64(10), spans[0].Attr("exmo.limit"))
+	assert.Equal(t, "BTC_USD,ETH_USD", spans[0].Attr("exmo.pair"))
+	assert.Equal(t, int64(3), spans[0].Attr("exmo.response_size"))
+	assert.NotEqual(t, spans[0].TraceID, spans[1].TraceID)
+	assert.Equal(t, "boom", spans[1].Err)
+	assert.Nil(t, spans[1].Attr("exmo.response_size"))
+}
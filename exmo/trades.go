@@ -1,4 +1,4 @@
-package main
+package exmo
 
 import "encoding/json"
 
@@ -12,8 +12,10 @@ func (r *Trades) Marshal() ([]byte, error) {
 	return json.Marshal(r)
 }
 
+// Trades - ответ /trades: последние сделки по парам.
 type Trades map[string][]Pair
 
+// Pair - сделка из /trades.
 type Pair struct {
 	TradeID  int64  `json:"trade_id"`
 	Date     int64  `json:"date"`
@@ -23,6 +25,7 @@ type Pair struct {
 	Amount   string `json:"amount"`
 }
 
+// Type - сторона сделки.
 type Type string
 
 const (
@@ -0,0 +1,4 @@
+// Package exmopb содержит protobuf-сообщения и gRPC-сервис MarketData.
+package exmopb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative exmo.proto
@@ -0,0 +1,310 @@
+// Package fixture записывает ответы exmo.Exchanger в файл фикстур
+// (RecordingExchanger) и воспроизводит их без сети (ReplayExchanger).
+package fixture
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+var ErrReplayMismatch = errors.New("no recorded response for call")
+
+// Fixture - записанный вызов Exchanger: параметры, ответ и ошибка.
+type Fixture struct {
+	Method   string          `json:"method"`
+	Params   json.RawMessage `json:"params"`
+	Response json.RawMessage `json:"response,omitempty"`
+	Error    *FixtureError   `json:"error,omitempty"`
+}
+
+// FixtureError сохраняет ошибку так, чтобы *APIError восстановился при воспроизведении.
+type FixtureError struct {
+	Message    string `json:"message"`
+	Endpoint   string `json:"endpoint,omitempty"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Code       int    `json:"code,omitempty"`
+}
+
+func newFixtureError(err error) *FixtureError {
+	if err == nil {
+		return nil
+	}
+	var apiErr *exmo.APIError
+	if errors.As(err, &apiErr) {
+		return &FixtureError{Message: apiErr.Message, Endpoint: apiErr.Endpoint, StatusCode: apiErr.StatusCode, Code: apiErr.Code}
+	}
+	return &FixtureError{Message: err.Error()}
+}
+
+func (e *FixtureError) err() error {
+	if e == nil {
+		return nil
+	}
+	if e.StatusCode != 0 {
+		return &exmo.APIError{Endpoint: e.Endpoint, StatusCode: e.StatusCode, Code: e.Code, Message: e.Message}
+	}
+	return errors.New(e.Message)
+}
+
+type fixtureFile struct {
+	Fixtures []Fixture `json:"fixtures"`
+}
+
+// LoadFixtures читает записи из файла.
+func LoadFixtures(path string) ([]Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file fixtureFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse fixtures %s: %w", path, err)
+	}
+	// Параметры сравниваются побайтно, поэтому убираем отступы форматирования
+	for i, f := range file.Fixtures {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, f.Params); err != nil {
+			return nil, fmt.Errorf("parse fixtures %s: params of %s: %w", path, f.Method, err)
+		}
+		file.Fixtures[i].Params = buf.Bytes()
+	}
+	return file.Fixtures, nil
+}
+
+// SaveFixtures записывает записи в файл в читаемом виде.
+func SaveFixtures(path string, fixtures []Fixture) error {
+	data, err := json.MarshalIndent(fixtureFile{Fixtures: fixtures}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+func encodeParams(params ...interface{}) json.RawMessage {
+	data, err := json.Marshal(params)
+	if err != nil {
+		// Параметры методов Exchanger всегда сериализуемы
+		panic(err)
+	}
+	return data
+}
+
+// RecordingExchanger передает вызовы вложенному Exchanger и записывает их.
+type RecordingExchanger struct {
+	next     exmo.Exchanger
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+func NewRecordingExchanger(next exmo.Exchanger) *RecordingExchanger {
+	return &RecordingExchanger{next: next}
+}
+
+// Fixtures возвращает копию записанных вызовов.
+func (r *RecordingExchanger) Fixtures() []Fixture {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Fixture(nil), r.fixtures...)
+}
+
+// Save записывает накопленные вызовы в файл.
+func (r *RecordingExchanger) Save(path string) error {
+	return SaveFixtures(path, r.Fixtures())
+}
+
+func (r *RecordingExchanger) record(method string, params json.RawMessage, response interface{}, err error) {
+	f := Fixture{Method: method, Params: params, Error: newFixtureError(err)}
+	if err == nil {
+		data, mErr := json.Marshal(response)
+		if mErr != nil {
+			f.Error = &FixtureError{Message: "record response: " + mErr.Error()}
+		} else {
+			f.Response = data
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures = append(r.fixtures, f)
+}
+
+func (r *RecordingExchanger) GetTicker() (exmo.Ticker, error) {
+	v, err := r.next.GetTicker()
+	r.record("GetTicker", encodeParams(), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetTrades(pairs ...string) (exmo.Trades, error) {
+	v, err := r.next.GetTrades(pairs...)
+	r.record("GetTrades", encodeParams(pairs), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetOrderBook(limit int, pairs ...string) (exmo.OrderBook, error) {
+	v, err := r.next.GetOrderBook(limit, pairs...)
+	r.record("GetOrderBook", encodeParams(limit, pairs), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetCurrencies() (exmo.Currencies, error) {
+	v, err := r.next.GetCurrencies()
+	r.record("GetCurrencies", encodeParams(), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (exmo.CandlesHistory, error) {
+	v, err := r.next.GetCandlesHistory(pair, period, start, end)
+	r.record("GetCandlesHistory", encodeParams(pair, period, start, end), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	v, err := r.next.GetClosePrice(pair, resolution, start, end)
+	r.record("GetClosePrice", encodeParams(pair, resolution, start, end), v, err)
+	return v, err
+}
+
+func (r *RecordingExchanger) GetPairSettings() (exmo.PairSettings, error) {
+	v, err := r.next.GetPairSettings()
+	r.record("GetPairSettings", encodeParams(), v, err)
+	return v, err
+}
+
+type ReplayMode int
+
+const (
+	// ReplayStrict требует, чтобы вызовы шли в том же порядке и с теми же параметрами.
+	ReplayStrict ReplayMode = iota
+	// ReplayLenient ищет запись с теми же параметрами в любом порядке, затем
+	// любую запись того же метода. Исчерпанные записи используются повторно.
+	ReplayLenient
+)
+
+// ReplayExchanger воспроизводит записанные ответы без обращения к сети.
+type ReplayExchanger struct {
+	mode     ReplayMode
+	mu       sync.Mutex
+	fixtures []Fixture
+	used     []bool
+	pos      int
+}
+
+func NewReplayExchanger(fixtures []Fixture, mode ReplayMode) *ReplayExchanger {
+	return &ReplayExchanger{
+		mode:     mode,
+		fixtures: fixtures,
+		used:     make([]bool, len(fixtures)),
+	}
+}
+
+// LoadReplayExchanger создает ReplayExchanger из файла, записанного RecordingExchanger.
+func LoadReplayExchanger(path string, mode ReplayMode) (*ReplayExchanger, error) {
+	fixtures, err := LoadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayExchanger(fixtures, mode), nil
+}
+
+// Remaining возвращает число записей, которые еще не были воспроизведены.
+func (r *ReplayExchanger) Remaining() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, u := range r.used {
+		if !u {
+			n++
+		}
+	}
+	return n
+}
+
+func (r *ReplayExchanger) find(method string, params json.RawMessage) (Fixture, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ReplayStrict {
+		if r.pos >= len(r.fixtures) {
+			return Fixture{}, fmt.Errorf("%w: %s%s after all %d fixtures were replayed", ErrReplayMismatch, method, params, len(r.fixtures))
+		}
+		f := r.fixtures[r.pos]
+		if f.Method != method || !bytes.Equal(f.Params, params) {
+			return Fixture{}, fmt.Errorf("%w: got %s%s, want %s%s at position %d", ErrReplayMismatch, method, params, f.Method, f.Params, r.pos)
+		}
+		r.used[r.pos] = true
+		r.pos++
+		return f, nil
+	}
+
+	match := func(sameParams, unusedOnly bool) (int, bool) {
+		for i, f := range r.fixtures {
+			if f.Method != method || (unusedOnly && r.used[i]) {
+				continue
+			}
+			if sameParams && !bytes.Equal(f.Params, params) {
+				continue
+			}
+			return i, true
+		}
+		return 0, false
+	}
+	for _, try := range []struct{ sameParams, unusedOnly bool }{
+		{true, true}, {true, false}, {false, true}, {false, false},
+	} {
+		if i, ok := match(try.sameParams, try.unusedOnly); ok {
+			r.used[i] = true
+			return r.fixtures[i], nil
+		}
+	}
+	return Fixture{}, fmt.Errorf("%w: %s%s", ErrReplayMismatch, method, params)
+}
+
+func replay[T any](r *ReplayExchanger, method string, params json.RawMessage) (T, error) {
+	var zero T
+	f, err := r.find(method, params)
+	if err != nil {
+		return zero, err
+	}
+	if f.Error != nil {
+		return zero, f.Error.err()
+	}
+	var v T
+	if err := json.Unmarshal(f.Response, &v); err != nil {
+		return zero, fmt.Errorf("decode recorded %s response: %w", method, err)
+	}
+	return v, nil
+}
+
+func (r *ReplayExchanger) GetTicker() (exmo.Ticker, error) {
+	return replay[exmo.Ticker](r, "GetTicker", encodeParams())
+}
+
+func (r *ReplayExchanger) GetTrades(pairs ...string) (exmo.Trades, error) {
+	return replay[exmo.Trades](r, "GetTrades", encodeParams(pairs))
+}
+
+func (r *ReplayExchanger) GetOrderBook(limit int, pairs ...string) (exmo.OrderBook, error) {
+	return replay[exmo.OrderBook](r, "GetOrderBook", encodeParams(limit, pairs))
+}
+
+func (r *ReplayExchanger) GetCurrencies() (exmo.Currencies, error) {
+	return replay[exmo.Currencies](r, "GetCurrencies", encodeParams())
+}
+
+func (r *ReplayExchanger) GetCandlesHistory(pair string, period int, start, end time.Time) (exmo.CandlesHistory, error) {
+	return replay[exmo.CandlesHistory](r, "GetCandlesHistory", encodeParams(pair, period, start, end))
+}
+
+func (r *ReplayExchanger) GetClosePrice(pair string, resolution int, start, end time.Time) ([]float64, error) {
+	return replay[[]float64](r, "GetClosePrice", encodeParams(pair, resolution, start, end))
+}
+
+func (r *ReplayExchanger) GetPairSettings() (exmo.PairSettings, error) {
+	return replay[exmo.PairSettings](r, "GetPairSettings", encodeParams())
+}
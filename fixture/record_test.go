@@ -0,0 +1,109 @@
+package fixture
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func recordFixtures(t *testing.T) string {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	from, to := time.Unix(1700000000, 0).UTC(), time.Unix(1700086400, 0).UTC()
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	gomock.InOrder(
+		mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "50000"}}, nil),
+		mockExchanger.EXPECT().GetOrderBook(10, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {AskTop: "50100"}}, nil),
+		mockExchanger.EXPECT().GetClosePrice("BTC_USD", 30, from, to).Return([]float64{1, 2, 3}, nil),
+		mockExchanger.EXPECT().GetTrades("BTC_XXX").Return(nil, &exmo.APIError{Endpoint: "/trades", StatusCode: 200, Code: 50304, Message: "Incorrect pair"}),
+		mockExchanger.EXPECT().GetCurrencies().Return(nil, errors.New("connection reset")),
+	)
+
+	rec := NewRecordingExchanger(mockExchanger)
+	_, _ = rec.GetTicker()
+	_, _ = rec.GetOrderBook(10, "BTC_USD")
+	_, _ = rec.GetClosePrice("BTC_USD", 30, from, to)
+	_, _ = rec.GetTrades("BTC_XXX")
+	_, _ = rec.GetCurrencies()
+
+	require.Len(t, rec.Fixtures(), 5)
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, rec.Save(path))
+	return path
+}
+
+func TestReplayExchanger_Strict(t *testing.T) {
+	path := recordFixtures(t)
+	replay, err := LoadReplayExchanger(path, ReplayStrict)
+	require.NoError(t, err)
+
+	ticker, err := replay.GetTicker()
+	require.NoError(t, err)
+	assert.Equal(t, "50000", ticker["BTC_USD"].LastTrade)
+
+	// Порядок нарушен
+	_, err = replay.GetClosePrice("BTC_USD", 30, time.Unix(1700000000, 0), time.Unix(1700086400, 0))
+	assert.ErrorIs(t, err, ErrReplayMismatch)
+
+	book, err := replay.GetOrderBook(10, "BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, "50100", book["BTC_USD"].AskTop)
+
+	prices, err := replay.GetClosePrice("BTC_USD", 30, time.Unix(1700000000, 0).UTC(), time.Unix(1700086400, 0).UTC())
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, prices)
+
+	_, err = replay.GetTrades("BTC_XXX")
+	var apiErr *exmo.APIError
+	require.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, 50304, apiErr.Code)
+	assert.ErrorIs(t, err, exmo.ErrInvalidPair)
+
+	_, err = replay.GetCurrencies()
+	assert.EqualError(t, err, "connection reset")
+
+	assert.Equal(t, 0, replay.Remaining())
+	_, err = replay.GetTicker()
+	assert.ErrorIs(t, err, ErrReplayMismatch)
+}
+
+func TestReplayExchanger_Lenient(t *testing.T) {
+	path := recordFixtures(t)
+	replay, err := LoadReplayExchanger(path, ReplayLenient)
+	require.NoError(t, err)
+
+	// Любой порядок и другие параметры того же метода
+	prices, err := replay.GetClosePrice("BTC_USD", 30, time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1, 2, 3}, prices)
+
+	for i := 0; i < 2; i++ {
+		ticker, err := replay.GetTicker()
+		require.NoError(t, err)
+		assert.Equal(t, "50000", ticker["BTC_USD"].LastTrade)
+	}
+	assert.Equal(t, 3, replay.Remaining())
+
+	_, err = replay.GetPairSettings()
+	assert.ErrorIs(t, err, ErrReplayMismatch)
+}
+
+func TestLoadFixtures_Errors(t *testing.T) {
+	_, err := LoadFixtures(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	path := filepath.Join(t.TempDir(), "empty.json")
+	require.NoError(t, SaveFixtures(path, nil))
+	fixtures, err := LoadFixtures(path)
+	assert.NoError(t, err)
+	assert.Empty(t, fixtures)
+}
@@ -0,0 +1,6 @@
+// Package indicator считает индикаторы SMA, EMA и RSI по ценам закрытия
+// свечей, полученным через exmo.Exchanger.
+//
+// Функции CalculateSMA, CalculateEMA и CalculateRSI работают с готовым рядом
+// цен и не обращаются к бирже.
+package indicator
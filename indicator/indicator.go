@@ -0,0 +1,184 @@
+package indicator
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/cinar/indicator"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
+)
+
+// Indicatorer считает индикаторы по ценам закрытия свечей пары за интервал
+// [from, to]. resolution - размер свечи в минутах.
+type Indicatorer interface {
+	SMA(pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	EMA(pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	RSI(pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	// Варианты с контекстом: спан индикатора становится потомком спана из ctx
+	SMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	EMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	RSIContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error)
+}
+
+// Indicator - реализация Indicatorer поверх exmo.Exchanger.
+type Indicator struct {
+	exchange     exmo.Exchanger
+	calculateSMA func(data []float64, period int) []float64
+	calculateEMA func(data []float64, period int) []float64
+	calculateRSI func(data []float64, period int) []float64
+	metrics      *telemetry.Metrics
+	tracer       *telemetry.Tracer
+}
+
+// IndicatorOption настраивает Indicator.
+type IndicatorOption func(*Indicator)
+
+// WithCalculateSMA подменяет расчет SMA, по умолчанию CalculateSMA.
+func WithCalculateSMA(f func(data []float64, period int) []float64) IndicatorOption {
+	return func(i *Indicator) {
+		i.calculateSMA = f
+	}
+}
+
+// WithCalculateEMA подменяет расчет EMA, по умолчанию CalculateEMA.
+func WithCalculateEMA(f func(data []float64, period int) []float64) IndicatorOption {
+	return func(i *Indicator) {
+		i.calculateEMA = f
+	}
+}
+
+// WithCalculateRSI подменяет расчет RSI, по умолчанию CalculateRSI.
+func WithCalculateRSI(f func(data []float64, period int) []float64) IndicatorOption {
+	return func(i *Indicator) {
+		i.calculateRSI = f
+	}
+}
+
+// WithIndicatorMetrics подключает учет времени расчета индикаторов.
+func WithIndicatorMetrics(m *telemetry.Metrics) IndicatorOption {
+	return func(i *Indicator) {
+		i.metrics = m
+	}
+}
+
+// WithIndicatorTracer включает спаны индикаторов. Если Exchanger реализует
+// ContextExchanger, его спаны становятся дочерними к спану индикатора.
+func WithIndicatorTracer(t *telemetry.Tracer) IndicatorOption {
+	return func(i *Indicator) {
+		i.tracer = t
+	}
+}
+
+// NewIndicator создает Indicator, который берет цены из exchange.
+func NewIndicator(exchange exmo.Exchanger, opts ...IndicatorOption) Indicatorer {
+	ind := &Indicator{
+		exchange: exchange,
+	}
+
+	for _, opt := range opts {
+		opt(ind)
+	}
+
+	// Установка значений по умолчанию
+	if ind.calculateSMA == nil {
+		ind.calculateSMA = CalculateSMA
+	}
+
+	if ind.calculateEMA == nil {
+		ind.calculateEMA = CalculateEMA
+	}
+
+	if ind.calculateRSI == nil {
+		ind.calculateRSI = CalculateRSI
+	}
+
+	return ind
+}
+
+func (i *Indicator) SMA(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.SMAContext(context.Background(), pair, resolution, period, from, to)
+}
+
+func (i *Indicator) EMA(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.EMAContext(context.Background(), pair, resolution, period, from, to)
+}
+
+func (i *Indicator) RSI(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.RSIContext(context.Background(), pair, resolution, period, from, to)
+}
+
+func (i *Indicator) SMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.run(ctx, "sma", i.calculateSMA, pair, resolution, period, from, to)
+}
+
+func (i *Indicator) EMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.run(ctx, "ema", i.calculateEMA, pair, resolution, period, from, to)
+}
+
+func (i *Indicator) RSIContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	return i.run(ctx, "rsi", i.calculateRSI, pair, resolution, period, from, to)
+}
+
+// run загружает цены закрытия и считает индикатор. Загрузка и расчет
+// попадают в отдельные дочерние спаны, чтобы их время было видно порознь.
+func (i *Indicator) run(ctx context.Context, name string, calc func([]float64, int) []float64, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	ctx, span := i.tracer.Start(ctx, "Indicator."+strings.ToUpper(name), telemetry.SpanInternal,
+		telemetry.StringAttr("indicator.pair", pair),
+		telemetry.IntAttr("indicator.resolution", resolution),
+		telemetry.IntAttr("indicator.period", period),
+	)
+	data, err := exmo.BindContext(ctx, i.exchange).GetClosePrice(pair, resolution, from, to)
+	if err != nil {
+		span.End(err)
+		return nil, err
+	}
+
+	_, calcSpan := i.tracer.Start(ctx, "Indicator.calculate", telemetry.SpanInternal, telemetry.IntAttr("indicator.input_size", len(data)))
+	result := i.compute(name, calc, data, period)
+	calcSpan.End(nil)
+	span.End(nil)
+	return result, nil
+}
+
+// compute считает индикатор и учитывает время расчета в метриках.
+func (i *Indicator) compute(name string, calc func([]float64, int) []float64, data []float64, period int) []float64 {
+	start := time.Now()
+	result := calc(data, period)
+	i.metrics.ObserveIndicator(name, time.Since(start))
+	return result
+}
+
+// CalculateSMA возвращает скользящее среднее по data.
+func CalculateSMA(data []float64, period int) []float64 {
+    if len(data) < period || period <= 0 {
+        return []float64{}
+    }
+    return indicator.Sma(period, data)[period-1:]
+}
+
+// CalculateEMA возвращает экспоненциальное скользящее среднее по data.
+func CalculateEMA(data []float64, period int) []float64 {
+    if len(data) < period || period <= 0 {
+        return []float64{}
+    }
+    // EMA возвращает результат той же длины, что и входные данные
+    return indicator.Ema(period, data)
+}
+
+// CalculateRSI возвращает RSI без первых period значений, пока среднее не набрано.
+func CalculateRSI(data []float64, period int) []float64 {
+	if len(data) <= period || period <= 0 {
+		return []float64{}
+	}
+	_, rsi := indicator.RsiPeriod(period, data)
+	return rsi[period:]
+}
+
+// Warmup возвращает, сколько свечей загружать для индикатора с периодом
+// period, чтобы EMA и RSI успели сойтись к устойчивому значению.
+func Warmup(period int) int {
+	return period*5 + 1
+}
@@ -1,19 +1,26 @@
-package main
+package indicator
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
 )
 
 func TestNewIndicator(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockExchanger := NewMockExchanger(ctrl)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
 
 	t.Run("default options", func(t *testing.T) {
 		ind := NewIndicator(mockExchanger).(*Indicator)
@@ -37,13 +44,28 @@ func TestNewIndicator(t *testing.T) {
 		assert.Equal(t, []float64{2.0}, ind.calculateEMA(nil, 0))
 		assert.Equal(t, mockExchanger, ind.exchange)
 	})
+
+	t.Run("custom calculations are used", func(t *testing.T) {
+		custom := func(v float64) func([]float64, int) []float64 {
+			return func(data []float64, period int) []float64 { return []float64{v, data[0], float64(period)} }
+		}
+		ind := NewIndicator(mockExchanger, WithCalculateSMA(custom(1)), WithCalculateEMA(custom(2)), WithCalculateRSI(custom(3)))
+		now := time.Now()
+		mockExchanger.EXPECT().GetClosePrice("BTC_USD", 30, now, now).Return([]float64{7}, nil).Times(3)
+
+		for want, calc := range map[float64]func(string, int, int, time.Time, time.Time) ([]float64, error){1: ind.SMA, 2: ind.EMA, 3: ind.RSI} {
+			values, err := calc("BTC_USD", 30, 5, now, now)
+			require.NoError(t, err)
+			assert.Equal(t, []float64{want, 7, 5}, values)
+		}
+	})
 }
 
 func TestSMA(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockExchanger := NewMockExchanger(ctrl)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
 	ind := NewIndicator(mockExchanger)
 
 	now := time.Now()
@@ -137,7 +159,7 @@ func TestEMA(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockExchanger := NewMockExchanger(ctrl)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
 	ind := NewIndicator(mockExchanger)
 
 	now := time.Now()
@@ -262,7 +284,7 @@ func TestCalculateSMA(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateSMA(tt.data, tt.period)
+			result := CalculateSMA(tt.data, tt.period)
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -316,7 +338,7 @@ func TestCalculateEMA(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := calculateEMA(tt.data, tt.period)
+			result := CalculateEMA(tt.data, tt.period)
 			assert.Equal(t, len(tt.expected), len(result), "length mismatch")
 			for i := range tt.expected {
 				assert.InDelta(t, tt.expected[i], result[i], 0.0001, 
@@ -324,4 +346,92 @@ func TestCalculateEMA(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestCalculateRSI(t *testing.T) {
+	// Только рост - RSI 100, только падение - 0
+	rising := CalculateRSI([]float64{1, 2, 3, 4, 5, 6}, 3)
+	require.Len(t, rising, 3)
+	for _, v := range rising {
+		assert.InDelta(t, 100, v, 1e-9)
+	}
+	falling := CalculateRSI([]float64{6, 5, 4, 3, 2, 1}, 3)
+	for _, v := range falling {
+		assert.InDelta(t, 0, v, 1e-9)
+	}
+
+	mixed := CalculateRSI([]float64{10, 11, 10, 11, 10, 11, 10}, 2)
+	for _, v := range mixed {
+		assert.True(t, v > 0 && v < 100, "%v", v)
+	}
+
+	assert.Equal(t, []float64{}, CalculateRSI([]float64{1, 2, 3}, 3))
+	assert.Equal(t, []float64{}, CalculateRSI([]float64{1, 2, 3}, 0))
+}
+
+func scrape(t *testing.T, m *telemetry.Metrics) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, m.WritePrometheus(&b))
+	return b.String()
+}
+
+func TestMetrics_Indicator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	mock.EXPECT().GetClosePrice("BTC_USD", 30, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 3}, nil).Times(2)
+
+	m := telemetry.NewMetrics()
+	ind := NewIndicator(mock, WithIndicatorMetrics(m))
+	_, err := ind.SMA("BTC_USD", 30, 2, time.Now(), time.Now())
+	require.NoError(t, err)
+	_, err = ind.EMA("BTC_USD", 30, 2, time.Now(), time.Now())
+	require.NoError(t, err)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_count{indicator="sma"} 1`)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_count{indicator="ema"} 1`)
+}
+
+func spansByName(spans []telemetry.Span) map[string]telemetry.Span {
+	m := make(map[string]telemetry.Span, len(spans))
+	for _, s := range spans {
+		m[s.Name] = s
+	}
+	return m
+}
+
+func TestTracing_IndicatorSpans(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	mock.EXPECT().GetClosePrice("BTC_USD", 30, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 3, 4}, nil)
+
+	exporter := telemetry.NewInMemoryExporter()
+	tracer := telemetry.NewTracer(exporter)
+	ind := NewIndicator(exmo.NewTracingExchanger(mock, tracer), WithIndicatorTracer(tracer))
+
+	ctx, root := tracer.Start(context.Background(), "request", telemetry.SpanInternal)
+	sma, err := ind.SMAContext(ctx, "BTC_USD", 30, 2, time.Now(), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 2.5, 3.5}, sma)
+	root.End(nil)
+
+	spans := spansByName(exporter.Spans())
+	require.Len(t, spans, 4)
+	parent, fetch, calc := spans["Indicator.SMA"], spans["Exchanger.GetClosePrice"], spans["Indicator.calculate"]
+
+	assert.Equal(t, spans["request"].SpanID, parent.ParentID)
+	assert.Equal(t, parent.SpanID, fetch.ParentID)
+	assert.Equal(t, parent.SpanID, calc.ParentID)
+	for _, s := range spans {
+		assert.Equal(t, spans["request"].TraceID, s.TraceID)
+	}
+
+	assert.Equal(t, telemetry.SpanClient, fetch.Kind)
+	assert.Equal(t, "/candles_history", fetch.Attr("exmo.endpoint"))
+	assert.Equal(t, "BTC_USD", fetch.Attr("exmo.pair"))
+	assert.Equal(t, int64(4), fetch.Attr("exmo.response_size"))
+	assert.Equal(t, int64(2), parent.Attr("indicator.period"))
+	assert.Equal(t, int64(4), calc.Attr("indicator.input_size"))
+}
+
@@ -0,0 +1,4 @@
+// Package indicatormock содержит gomock-мок indicator.Indicatorer.
+package indicatormock
+
+//go:generate mockgen -source=../indicator.go -destination=indicator.go -package=indicatormock
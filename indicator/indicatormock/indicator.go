@@ -0,0 +1,126 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ../indicator.go
+
+// Package indicatormock is a generated GoMock package.
+package indicatormock
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIndicatorer is a mock of Indicatorer interface.
+type MockIndicatorer struct {
+	ctrl     *gomock.Controller
+	recorder *MockIndicatorerMockRecorder
+}
+
+// MockIndicatorerMockRecorder is the mock recorder for MockIndicatorer.
+type MockIndicatorerMockRecorder struct {
+	mock *MockIndicatorer
+}
+
+// NewMockIndicatorer creates a new mock instance.
+func NewMockIndicatorer(ctrl *gomock.Controller) *MockIndicatorer {
+	mock := &MockIndicatorer{ctrl: ctrl}
+	mock.recorder = &MockIndicatorerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIndicatorer) EXPECT() *MockIndicatorerMockRecorder {
+	return m.recorder
+}
+
+// EMA mocks base method.
+func (m *MockIndicatorer) EMA(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EMA", pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EMA indicates an expected call of EMA.
+func (mr *MockIndicatorerMockRecorder) EMA(pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EMA", reflect.TypeOf((*MockIndicatorer)(nil).EMA), pair, resolution, period, from, to)
+}
+
+// EMAContext mocks base method.
+func (m *MockIndicatorer) EMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EMAContext", ctx, pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EMAContext indicates an expected call of EMAContext.
+func (mr *MockIndicatorerMockRecorder) EMAContext(ctx, pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EMAContext", reflect.TypeOf((*MockIndicatorer)(nil).EMAContext), ctx, pair, resolution, period, from, to)
+}
+
+// RSI mocks base method.
+func (m *MockIndicatorer) RSI(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RSI", pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RSI indicates an expected call of RSI.
+func (mr *MockIndicatorerMockRecorder) RSI(pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RSI", reflect.TypeOf((*MockIndicatorer)(nil).RSI), pair, resolution, period, from, to)
+}
+
+// RSIContext mocks base method.
+func (m *MockIndicatorer) RSIContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RSIContext", ctx, pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RSIContext indicates an expected call of RSIContext.
+func (mr *MockIndicatorerMockRecorder) RSIContext(ctx, pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RSIContext", reflect.TypeOf((*MockIndicatorer)(nil).RSIContext), ctx, pair, resolution, period, from, to)
+}
+
+// SMA mocks base method.
+func (m *MockIndicatorer) SMA(pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMA", pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMA indicates an expected call of SMA.
+func (mr *MockIndicatorerMockRecorder) SMA(pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMA", reflect.TypeOf((*MockIndicatorer)(nil).SMA), pair, resolution, period, from, to)
+}
+
+// SMAContext mocks base method.
+func (m *MockIndicatorer) SMAContext(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SMAContext", ctx, pair, resolution, period, from, to)
+	ret0, _ := ret[0].([]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SMAContext indicates an expected call of SMAContext.
+func (mr *MockIndicatorerMockRecorder) SMAContext(ctx, pair, resolution, period, from, to interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SMAContext", reflect.TypeOf((*MockIndicatorer)(nil).SMAContext), ctx, pair, resolution, period, from, to)
+}
@@ -0,0 +1,70 @@
+// Package timearg разбирает время и разрешение свечей, заданные в
+// аргументах командной строки и параметрах HTTP-запросов.
+package timearg
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParseResolution понимает 15m, 1h, 1d, 1w и число минут.
+func ParseResolution(s string) (int, error) {
+	units := map[byte]int{'m': 1, 'h': 60, 'd': 24 * 60, 'w': 7 * 24 * 60}
+	num := strings.ToLower(s)
+	mult := 1
+	if m, ok := units[num[len(num)-1]]; ok {
+		mult, num = m, num[:len(num)-1]
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid resolution %q", s)
+	}
+	return n * mult, nil
+}
+
+// Parse понимает RFC3339, "now" и смещения вида -2d, -1w, -90m, -1h30m.
+func Parse(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "now" {
+		return now, nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if s[0] != '-' && s[0] != '+' {
+		return time.Time{}, fmt.Errorf("invalid time %q: want RFC3339 or offset like -2d", s)
+	}
+
+	sign := time.Duration(1)
+	if s[0] == '-' {
+		sign = -1
+	}
+	rest := s[1:]
+	var offset time.Duration
+	// Дни и недели time.ParseDuration не понимает, разбираем их сами
+	for _, unit := range []struct {
+		suffix string
+		size   time.Duration
+	}{{"w", 7 * 24 * time.Hour}, {"d", 24 * time.Hour}} {
+		i := strings.Index(rest, unit.suffix)
+		if i < 0 {
+			continue
+		}
+		n, err := strconv.Atoi(rest[:i])
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q", s)
+		}
+		offset += time.Duration(n) * unit.size
+		rest = rest[i+1:]
+	}
+	if rest != "" {
+		d, err := time.ParseDuration(rest)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid offset %q", s)
+		}
+		offset += d
+	}
+	return now.Add(sign * offset), nil
+}
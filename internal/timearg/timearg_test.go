@@ -0,0 +1,36 @@
+package timearg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"now", now},
+		{"", now},
+		{"-2d", now.AddDate(0, 0, -2)},
+		{"-1w", now.AddDate(0, 0, -7)},
+		{"-6h", now.Add(-6 * time.Hour)},
+		{"-1d12h", now.Add(-36 * time.Hour)},
+		{"+30m", now.Add(30 * time.Minute)},
+		{"2024-03-01T00:00:00Z", time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, now)
+		require.NoError(t, err, tt.in)
+		assert.True(t, tt.want.Equal(got), "%s: got %s, want %s", tt.in, got, tt.want)
+	}
+
+	for _, in := range []string{"yesterday", "-xd", "-2q", "2024-03-01"} {
+		_, err := Parse(in, now)
+		assert.Error(t, err, in)
+	}
+}
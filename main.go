@@ -1,38 +1,30 @@
 package main
 
 import (
-	"fmt"
-	"time"
+	"os"
+	"path/filepath"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/telemetry"
 )
 
 // Добавляем глобальную переменную для возможности подмены в тестах
-var globalExchanger Exchanger = NewExmo()
+var globalExchanger exmo.Exchanger = globalConfig.NewExchanger(os.Getenv)
 
-func main() {
-	// Используем globalExchanger вместо создания нового экземпляра
-	indicator := NewIndicator(globalExchanger,
-		WithCalculateSMA(calculateSMA),
-		WithCalculateEMA(calculateEMA),
-	)
+// globalMetrics - метрики клиента и индикаторов, см. флаг -metrics
+var globalMetrics = telemetry.NewMetrics()
 
-	// Остальной код остается без изменений
-	pair := "BTC_USD"
-	resolution := 30
-	period := 5
-	from := time.Now().AddDate(0, 0, -2)
-	to := time.Now()
+// globalTracer включается флагом -otlp, nil - без трассировки
+var globalTracer *telemetry.Tracer
 
-	sma, err := indicator.SMA(pair, resolution, period, from, to)
-	if err != nil {
-		fmt.Println("Ошибка при расчете SMA:", err)
-		return
-	}
-	fmt.Println("SMA:", sma)
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
 
-	ema, err := indicator.EMA(pair, resolution, period, from, to)
-	if err != nil {
-		fmt.Println("Ошибка при расчете EMA:", err)
-		return
+// defaultStoreDir возвращает каталог хранилища свечей в пользовательском кэше.
+func defaultStoreDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "exmo-candles")
 	}
-	fmt.Println("EMA:", ema)
-}
\ No newline at end of file
+	return ".exmo-candles"
+}
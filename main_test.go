@@ -1,55 +1,51 @@
 package main
 
 import (
-	"io"
-	"os"
+	"bytes"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
 )
 
 func TestMainOutput(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
-	mockExchanger := NewMockExchanger(ctrl)
-	
-	// Используем gomock.Any() для временных параметров
-	mockExchanger.EXPECT().GetClosePrice(
-		"BTC_USD", 
-		30, 
-		gomock.Any(), // Любое время начала
-		gomock.Any(), // Любое время окончания
-	).Return([]float64{100, 101, 102, 103, 104}, nil).Times(2)
-
-	// Перехватываем stdout
-	old := os.Stdout
-	r, w, _ := os.Pipe()
-	os.Stdout = w
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+
+	// Индикатор и время его значений берутся из одного ответа со свечами
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]exmo.Candle, 5)
+	for i := range candles {
+		candles[i] = exmo.Candle{T: start.Add(time.Duration(i) * 30 * time.Minute).UnixMilli(), C: float64(100 + i)}
+	}
+	mockExchanger.EXPECT().GetCandlesHistory("BTC_USD", 30, gomock.Any(), gomock.Any()).
+		Return(exmo.CandlesHistory{Candles: candles}, nil).Times(2)
 
 	// Подменяем globalExchanger
 	original := globalExchanger
 	globalExchanger = mockExchanger
-	defer func() { 
-		globalExchanger = original 
-		w.Close()
-		os.Stdout = old
-	}()
+	defer func() { globalExchanger = original }()
 
-	main()
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, exitOK, run([]string{"indicator", "sma"}, &stdout, &stderr))
+	assert.Equal(t, exitOK, run([]string{"indicator", "ema"}, &stdout, &stderr))
 
-	w.Close()
-	out, _ := io.ReadAll(r)
-	os.Stdout = old
+	output := stdout.String()
 
-	output := string(out)
-	
 	// Проверяем общую структуру вывода
-	assert.Contains(t, output, "SMA:")
-	assert.Contains(t, output, "EMA:")
-	
-	// Проверяем что вывод содержит списки чисел
-	assert.Regexp(t, `SMA: \[[\d\., ]+\]`, output)
-	assert.Regexp(t, `EMA: \[[\d\., ]+\]`, output)
-}
\ No newline at end of file
+	assert.Contains(t, output, "INDICATOR")
+	assert.Contains(t, output, "TIME")
+	assert.Contains(t, output, "VALUE")
+
+	// Проверяем что вывод содержит строки со значениями
+	// SMA с периодом 5 дает одно значение - по последней свече
+	assert.Regexp(t, `SMA\s+BTC_USD\s+0\s+2024-01-01 02:00\S*\s+[\d\.,]+`, output)
+	assert.Regexp(t, `EMA\s+BTC_USD\s+0\s+\S+\s+[\d\.,]+`, output)
+	assert.Empty(t, stderr.String())
+}
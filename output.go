@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// OutputFormat - формат вывода результатов CLI.
+type OutputFormat string
+
+const (
+	OutputTable  OutputFormat = "table"
+	OutputJSON   OutputFormat = "json"
+	OutputCSV    OutputFormat = "csv"
+	OutputNDJSON OutputFormat = "ndjson"
+)
+
+var outputFormats = []OutputFormat{OutputTable, OutputJSON, OutputCSV, OutputNDJSON}
+
+func parseOutputFormat(s string) (OutputFormat, error) {
+	for _, f := range outputFormats {
+		if string(f) == strings.ToLower(s) {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unknown output format %q: want table, json, csv or ndjson", s)
+}
+
+// ColumnType определяет, как значение колонки выводится в каждом формате.
+type ColumnType string
+
+const (
+	ColumnString ColumnType = "string"
+	ColumnNumber ColumnType = "number" // float64, в JSON - число
+	ColumnInt    ColumnType = "integer"
+	ColumnTime   ColumnType = "time" // time.Time, в JSON и CSV - RFC3339 UTC
+)
+
+// Column описывает поле результата. Key используется в JSON и заголовке CSV,
+// Title - в заголовке таблицы.
+type Column struct {
+	Key         string
+	Title       string
+	Type        ColumnType
+	Description string
+}
+
+// Result - табличный результат команды. Порядок колонок задает порядок
+// ключей в JSON, поэтому вывод стабилен между запусками.
+type Result struct {
+	Columns []Column
+	Rows    [][]interface{}
+}
+
+func (r *Result) Add(values ...interface{}) {
+	r.Rows = append(r.Rows, values)
+}
+
+// Write выводит результат в выбранном формате.
+func (r *Result) Write(w io.Writer, format OutputFormat) error {
+	switch format {
+	case OutputTable:
+		return r.writeTable(w)
+	case OutputJSON:
+		return r.writeJSON(w)
+	case OutputCSV:
+		return r.writeCSV(w)
+	case OutputNDJSON:
+		return r.writeNDJSON(w)
+	}
+	return fmt.Errorf("unknown output format %q", format)
+}
+
+// WriteSchema печатает описание полей результата.
+func (r *Result) WriteSchema(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, c := range r.Columns {
+		fmt.Fprintf(tw, "  %s\t%s\t%s\n", c.Key, c.Type, c.Description)
+	}
+	tw.Flush()
+}
+
+func (r *Result) writeTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	titles := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		titles[i] = c.Title
+	}
+	fmt.Fprintln(tw, strings.Join(titles, "\t")+"\t")
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = humanValue(v)
+		}
+		fmt.Fprintln(tw, strings.Join(cells, "\t")+"\t")
+	}
+	return tw.Flush()
+}
+
+func (r *Result) writeCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := make([]string, len(r.Columns))
+	for i, c := range r.Columns {
+		header[i] = c.Key
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range r.Rows {
+		cells := make([]string, len(row))
+		for i, v := range row {
+			cells[i] = plainValue(v)
+		}
+		if err := cw.Write(cells); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func (r *Result) writeJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range r.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  ")
+		if err := r.encodeRow(&buf, row); err != nil {
+			return err
+		}
+	}
+	if len(r.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func (r *Result) writeNDJSON(w io.Writer) error {
+	var buf bytes.Buffer
+	for _, row := range r.Rows {
+		if err := r.encodeRow(&buf, row); err != nil {
+			return err
+		}
+		buf.WriteString("\n")
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// encodeRow пишет строку как JSON-объект с ключами в порядке колонок.
+func (r *Result) encodeRow(buf *bytes.Buffer, row []interface{}) error {
+	buf.WriteString("{")
+	for i, c := range r.Columns {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		key, _ := json.Marshal(c.Key)
+		buf.Write(key)
+		buf.WriteString(":")
+
+		var v interface{}
+		if i < len(row) {
+			v = row[i]
+		}
+		data, err := json.Marshal(jsonValue(v))
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", c.Key, err)
+		}
+		buf.Write(data)
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case float64:
+		// NaN и бесконечность не представимы в JSON
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+		return json.Number(formatFloat(v))
+	}
+	return v
+}
+
+// plainValue - значение без форматирования для CSV.
+func plainValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case float64:
+		return formatFloat(v)
+	case string:
+		return v
+	}
+	return fmt.Sprint(v)
+}
+
+// humanValue - значение для таблицы: числа с разделителями разрядов.
+func humanValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case float64:
+		return humanNumber(v)
+	case int64:
+		return groupDigits(strconv.FormatInt(v, 10))
+	case int:
+		return groupDigits(strconv.Itoa(v))
+	case time.Time:
+		return v.UTC().Format("2006-01-02 15:04:05")
+	}
+	return plainValue(v)
+}
+
+// humanNumber округляет до 8 знаков после запятой и группирует разряды.
+func humanNumber(v float64) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(v, 'f', 8, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	intPart, frac, hasFrac := strings.Cut(s, ".")
+	s = groupDigits(intPart)
+	if hasFrac {
+		s += "." + frac
+	}
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+func groupDigits(s string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	if len(s) <= 3 {
+		return sign + s
+	}
+	var b strings.Builder
+	head := len(s) % 3
+	if head > 0 {
+		b.WriteString(s[:head])
+	}
+	for i := head; i < len(s); i += 3 {
+		if b.Len() > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(s[i : i+3])
+	}
+	return sign + b.String()
+}
+
+// numberCell переводит строковое число из ответа Exmo в float64.
+// Пустые и нечисловые значения выводятся как есть.
+func numberCell(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return s
+	}
+	return v
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func parsePrice(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
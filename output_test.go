@@ -0,0 +1,116 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func sampleResult() *Result {
+	r := &Result{Columns: []Column{
+		{"pair", "PAIR", ColumnString, ""},
+		{"time", "TIME", ColumnTime, ""},
+		{"price", "PRICE", ColumnNumber, ""},
+		{"count", "COUNT", ColumnInt, ""},
+	}}
+	r.Add("BTC_USD", time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("MSK", 3*3600)), 65432.123456789, int64(1200))
+	r.Add("ETH, USD", time.Unix(0, 0), nil, 7)
+	return r
+}
+
+func TestResult_Table(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sampleResult().Write(&buf, OutputTable))
+
+	lines := bytes.Split(bytes.TrimRight(buf.Bytes(), "\n"), []byte("\n"))
+	require.Len(t, lines, 3)
+	assert.Equal(t, len(lines[0]), len(lines[1]), "columns are aligned")
+	assert.Contains(t, string(lines[1]), "2024-03-01 09:00:00")
+	assert.Contains(t, string(lines[1]), "65,432.12345679")
+	assert.Contains(t, string(lines[1]), "1,200")
+	assert.Contains(t, string(lines[2]), "-")
+}
+
+func TestResult_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sampleResult().Write(&buf, OutputJSON))
+	assert.Equal(t, `[
+  {"pair":"BTC_USD","time":"2024-03-01T09:00:00Z","price":65432.123456789,"count":1200},
+  {"pair":"ETH, USD","time":"1970-01-01T00:00:00Z","price":null,"count":7}
+]
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, (&Result{Columns: sampleResult().Columns}).Write(&buf, OutputJSON))
+	assert.Equal(t, "[]\n", buf.String())
+}
+
+func TestResult_NDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sampleResult().Write(&buf, OutputNDJSON))
+	lines := bytes.Split(bytes.TrimRight(buf.Bytes(), "\n"), []byte("\n"))
+	require.Len(t, lines, 2)
+	var row map[string]interface{}
+	require.NoError(t, json.Unmarshal(lines[0], &row))
+	assert.Equal(t, 65432.123456789, row["price"])
+}
+
+func TestResult_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, sampleResult().Write(&buf, OutputCSV))
+	assert.Equal(t, "pair,time,price,count\n"+
+		"BTC_USD,2024-03-01T09:00:00Z,65432.123456789,1200\n"+
+		"\"ETH, USD\",1970-01-01T00:00:00Z,,7\n", buf.String())
+}
+
+func TestHumanNumber(t *testing.T) {
+	tests := map[float64]string{
+		0:             "0",
+		-0.0000000001: "0",
+		1234567.5:     "1,234,567.5",
+		-1234.25:      "-1,234.25",
+		0.000012346:   "0.00001235",
+		999:           "999",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, humanNumber(in), "%v", in)
+	}
+}
+
+func TestRun_OutputFlags(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	withExchanger(t, mock)
+	mock.EXPECT().GetCurrencies().Return(exmo.Currencies{"USD": {}, "BTC": {}}, nil).Times(2)
+
+	code, stdout, _ := runCLI("currencies", "-output", "ndjson")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "{\"currency\":\"BTC\"}\n{\"currency\":\"USD\"}\n", stdout)
+
+	path := filepath.Join(t.TempDir(), "out", "currencies.csv")
+	code, stdout, _ = runCLI("currencies", "--output=csv", "-out", path)
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, stdout)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "currency\nBTC\nUSD\n", string(data))
+
+	code, _, stderr := runCLI("currencies", "-output", "xml")
+	assert.Equal(t, exitUsage, code)
+	assert.Contains(t, stderr, "unknown output format")
+
+	code, _, stderr = runCLI("candles", "-h")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, stderr, "Поля результата")
+	assert.Contains(t, stderr, "close")
+}
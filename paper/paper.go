@@ -0,0 +1,506 @@
+// Package paper - бумажная биржа PaperExchange: рыночные данные берутся
+// у настоящего exmo.Exchanger, а балансы, ордера и сделки симулируются
+// локально, поэтому стратегии можно проверить без реальных денег.
+package paper
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+var (
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	ErrOrderNotFound     = errors.New("order not found")
+	ErrNoLiquidity       = errors.New("not enough liquidity in order book")
+)
+
+// epsilon - остаток ордера, который считается нулевым.
+const epsilon = 1e-12
+
+// PaperExchange - бумажная биржа: рыночные данные берутся из источника,
+// а балансы, ордера и сделки симулируются локально по живому стакану.
+// Собственные сделки не изменяют стакан источника.
+type PaperExchange struct {
+	exmo.Exchanger
+
+	mu          sync.Mutex
+	balances    map[string]float64
+	reserved    map[string]float64
+	orders      map[int64]*paperOrder
+	trades      []exmo.UserTrade
+	nextOrderID int64
+	nextTradeID int64
+	// consumed - объем уровней стакана источника, уже забранный нашими
+	// ордерами. Источник о наших сделках не знает, и без этого учета один
+	// и тот же уровень исполнял бы все ордера снова на каждом опросе
+	consumed map[string]map[bookLevelKey]float64
+	takerFee float64
+	makerFee float64
+	depth    int
+	now      func() time.Time
+}
+
+type paperOrder struct {
+	id        int64
+	pair      string
+	orderType exmo.OrderType
+	price     float64
+	quantity  float64
+	created   time.Time
+}
+
+// bookLevelKey - уровень стакана: сторона тейкера и цена.
+type bookLevelKey struct {
+	side  exmo.Type
+	price float64
+}
+
+type PaperOption func(*PaperExchange)
+
+// WithPaperBalance задает стартовый баланс валюты.
+func WithPaperBalance(currency string, amount float64) PaperOption {
+	return func(p *PaperExchange) {
+		p.balances[currency] = amount
+	}
+}
+
+// WithPaperFees задает комиссии тейкера и мейкера в долях (0.003 = 0.3%).
+func WithPaperFees(taker, maker float64) PaperOption {
+	return func(p *PaperExchange) {
+		p.takerFee = taker
+		p.makerFee = maker
+	}
+}
+
+// WithPaperDepth задает глубину стакана, по которой исполняются ордера.
+func WithPaperDepth(limit int) PaperOption {
+	return func(p *PaperExchange) {
+		p.depth = limit
+	}
+}
+
+func WithPaperClock(now func() time.Time) PaperOption {
+	return func(p *PaperExchange) {
+		p.now = now
+	}
+}
+
+func NewPaperExchange(source exmo.Exchanger, opts ...PaperOption) *PaperExchange {
+	p := &PaperExchange{
+		Exchanger:   source,
+		balances:    make(map[string]float64),
+		reserved:    make(map[string]float64),
+		orders:      make(map[int64]*paperOrder),
+		consumed:    make(map[string]map[bookLevelKey]float64),
+		nextOrderID: 1,
+		nextTradeID: 1,
+		takerFee:    0.003,
+		makerFee:    0.003,
+		depth:       100,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// GetOrderBook отдает стакан источника и попутно исполняет лимитные ордера,
+// цены которых он пересек.
+func (p *PaperExchange) GetOrderBook(limit int, pairs ...string) (exmo.OrderBook, error) {
+	book, err := p.Exchanger.GetOrderBook(limit, pairs...)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for pair, levels := range book {
+		if err := p.matchRestingLocked(pair, levels); err != nil {
+			return nil, err
+		}
+	}
+	return book, nil
+}
+
+// CreateOrder исполняет ордер по стакану источника. Рыночный ордер, которому
+// не хватило глубины, исполняется частично: возвращаются его номер и ошибка
+// ErrNoLiquidity с неисполненным остатком.
+func (p *PaperExchange) CreateOrder(pair string, quantity, price float64, orderType exmo.OrderType) (int64, error) {
+	base, quote, err := exmo.SplitPair(pair)
+	if err != nil {
+		return 0, err
+	}
+	if quantity <= 0 {
+		return 0, fmt.Errorf("quantity must be positive, got %v", quantity)
+	}
+	switch orderType {
+	case exmo.OrderBuy, exmo.OrderSell:
+		if price <= 0 {
+			return 0, fmt.Errorf("price must be positive, got %v", price)
+		}
+	case exmo.OrderMarketBuy, exmo.OrderMarketSell:
+	default:
+		return 0, fmt.Errorf("unknown order type %q", orderType)
+	}
+
+	book, err := p.Exchanger.GetOrderBook(p.depth, pair)
+	if err != nil {
+		return 0, err
+	}
+	levels, ok := book[pair]
+	if !ok {
+		return 0, fmt.Errorf("no order book for pair %s", pair)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order := &paperOrder{
+		id:        p.nextOrderID,
+		pair:      pair,
+		orderType: orderType,
+		price:     price,
+		quantity:  quantity,
+		created:   p.now(),
+	}
+
+	available, err := p.availableLocked(pair, levels, orderType.Side())
+	if err != nil {
+		return 0, err
+	}
+	fills := takerFills(available, order)
+	if orderType.IsMarket() && len(fills) == 0 {
+		return 0, ErrNoLiquidity
+	}
+
+	// Проверяем, что средств хватит на немедленное исполнение и на остаток ордера
+	var need float64
+	for _, f := range fills {
+		if orderType.Side() == exmo.Buy {
+			need += f.Price * f.Quantity
+		} else {
+			need += f.Quantity
+		}
+	}
+	rest := order.quantity - sumQuantity(fills)
+	if !orderType.IsMarket() && rest > epsilon {
+		if orderType.Side() == exmo.Buy {
+			need += rest * price
+		} else {
+			need += rest
+		}
+	}
+	spend := quote
+	if orderType.Side() == exmo.Sell {
+		spend = base
+	}
+	if p.balances[spend] < need {
+		return 0, fmt.Errorf("%w: need %v %s, have %v", ErrInsufficientFunds, need, spend, p.balances[spend])
+	}
+
+	p.nextOrderID++
+	p.consumeLocked(pair, orderType.Side(), fills)
+	for _, f := range fills {
+		p.fillLocked(order, f.Price, f.Quantity, p.takerFee, "taker")
+	}
+	if order.quantity > epsilon {
+		if orderType.IsMarket() {
+			return order.id, fmt.Errorf("%w: market order %d filled %s of %s",
+				ErrNoLiquidity, order.id, formatFloat(quantity-order.quantity), formatFloat(quantity))
+		}
+		p.reserveLocked(order)
+		p.orders[order.id] = order
+	}
+	return order.id, nil
+}
+
+func (p *PaperExchange) CancelOrder(orderID int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	order, ok := p.orders[orderID]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrOrderNotFound, orderID)
+	}
+	p.releaseLocked(order, order.quantity)
+	delete(p.orders, orderID)
+	return nil
+}
+
+func (p *PaperExchange) GetOpenOrders() (exmo.OpenOrders, error) {
+	if err := p.Match(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	result := make(exmo.OpenOrders)
+	for _, o := range p.sortedOrdersLocked() {
+		result[o.pair] = append(result[o.pair], exmo.OpenOrder{
+			OrderID:  o.id,
+			Created:  o.created.Unix(),
+			Type:     o.orderType,
+			Pair:     o.pair,
+			Price:    formatFloat(o.price),
+			Quantity: formatFloat(o.quantity),
+			Amount:   formatFloat(o.price * o.quantity),
+		})
+	}
+	return result, nil
+}
+
+// GetUserTrades возвращает последние limit сделок по каждой паре, новые первыми.
+func (p *PaperExchange) GetUserTrades(limit int, pairs ...string) (exmo.UserTrades, error) {
+	if len(pairs) == 0 {
+		return nil, errors.New("at least one pair is required")
+	}
+	if err := p.Match(); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	wanted := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		wanted[pair] = true
+	}
+	result := make(exmo.UserTrades)
+	for i := len(p.trades) - 1; i >= 0; i-- {
+		t := p.trades[i]
+		if !wanted[t.Pair] || (limit > 0 && len(result[t.Pair]) >= limit) {
+			continue
+		}
+		result[t.Pair] = append(result[t.Pair], t)
+	}
+	return result, nil
+}
+
+func (p *PaperExchange) GetUserInfo() (exmo.UserInfo, error) {
+	if err := p.Match(); err != nil {
+		return exmo.UserInfo{}, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info := exmo.UserInfo{
+		ServerDate: p.now().Unix(),
+		Balances:   make(map[string]string, len(p.balances)),
+		Reserved:   make(map[string]string, len(p.reserved)),
+	}
+	for c, v := range p.balances {
+		info.Balances[c] = formatFloat(v)
+	}
+	for c, v := range p.reserved {
+		info.Reserved[c] = formatFloat(v)
+	}
+	return info, nil
+}
+
+// Match запрашивает стаканы по парам с открытыми ордерами и исполняет
+// ордера, чьи цены пересек рынок.
+func (p *PaperExchange) Match() error {
+	p.mu.Lock()
+	pairs := make(map[string]struct{})
+	for _, o := range p.orders {
+		pairs[o.pair] = struct{}{}
+	}
+	p.mu.Unlock()
+	if len(pairs) == 0 {
+		return nil
+	}
+
+	list := make([]string, 0, len(pairs))
+	for pair := range pairs {
+		list = append(list, pair)
+	}
+	sort.Strings(list)
+	_, err := p.GetOrderBook(p.depth, list...)
+	return err
+}
+
+func (p *PaperExchange) matchRestingLocked(pair string, levels exmo.OrderBookPair) error {
+	for _, order := range p.sortedOrdersLocked() {
+		if order.pair != pair {
+			continue
+		}
+		available, err := p.availableLocked(pair, levels, order.orderType.Side())
+		if err != nil {
+			return err
+		}
+		fills := takerFills(available, order)
+		p.consumeLocked(pair, order.orderType.Side(), fills)
+		for _, f := range fills {
+			// Ордер стоял в стакане, поэтому исполняется по своей цене как мейкер
+			p.releaseLocked(order, f.Quantity)
+			p.fillLocked(order, order.price, f.Quantity, p.makerFee, "maker")
+		}
+		if order.quantity <= epsilon {
+			delete(p.orders, order.id)
+		}
+	}
+	return nil
+}
+
+// fillLocked проводит сделку по балансам и уменьшает остаток ордера.
+func (p *PaperExchange) fillLocked(order *paperOrder, price, qty, fee float64, execType string) {
+	base, quote, _ := exmo.SplitPair(order.pair)
+	amount := price * qty
+
+	trade := exmo.UserTrade{
+		TradeID:           p.nextTradeID,
+		Date:              p.now().Unix(),
+		Type:              order.orderType.Side(),
+		Pair:              order.pair,
+		OrderID:           order.id,
+		Quantity:          formatFloat(qty),
+		Price:             formatFloat(price),
+		Amount:            formatFloat(amount),
+		ExecType:          execType,
+		CommissionPercent: formatFloat(fee * 100),
+	}
+	if order.orderType.Side() == exmo.Buy {
+		commission := qty * fee
+		p.balances[quote] -= amount
+		p.balances[base] += qty - commission
+		trade.CommissionAmount = formatFloat(commission)
+		trade.CommissionCurrency = base
+	} else {
+		commission := amount * fee
+		p.balances[base] -= qty
+		p.balances[quote] += amount - commission
+		trade.CommissionAmount = formatFloat(commission)
+		trade.CommissionCurrency = quote
+	}
+	p.nextTradeID++
+	p.trades = append(p.trades, trade)
+	order.quantity -= qty
+}
+
+func (p *PaperExchange) reserveLocked(order *paperOrder) {
+	currency, amount := reservation(order, order.quantity)
+	p.balances[currency] -= amount
+	p.reserved[currency] += amount
+}
+
+func (p *PaperExchange) releaseLocked(order *paperOrder, qty float64) {
+	currency, amount := reservation(order, qty)
+	p.reserved[currency] -= amount
+	p.balances[currency] += amount
+	if p.reserved[currency] <= 0 {
+		delete(p.reserved, currency)
+	}
+}
+
+func (p *PaperExchange) sortedOrdersLocked() []*paperOrder {
+	orders := make([]*paperOrder, 0, len(p.orders))
+	for _, o := range p.orders {
+		orders = append(orders, o)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].id < orders[j].id })
+	return orders
+}
+
+func reservation(order *paperOrder, qty float64) (string, float64) {
+	base, quote, _ := exmo.SplitPair(order.pair)
+	if order.orderType.Side() == exmo.Buy {
+		return quote, qty * order.price
+	}
+	return base, qty
+}
+
+// availableLocked возвращает уровни стакана, доступные тейкеру стороны side,
+// за вычетом уже забранного объема. Учет по ценам, которых больше нет в
+// стакане, сбрасывается: такие уровни источник уже обновил.
+func (p *PaperExchange) availableLocked(pair string, levels exmo.OrderBookPair, side exmo.Type) ([]exmo.BookLevel, error) {
+	var (
+		book []exmo.BookLevel
+		err  error
+	)
+	if side == exmo.Buy {
+		book, err = levels.Asks()
+	} else {
+		book, err = levels.Bids()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	used := p.consumed[pair]
+	present := make(map[float64]bool, len(book))
+	available := make([]exmo.BookLevel, 0, len(book))
+	for _, lvl := range book {
+		present[lvl.Price] = true
+		lvl.Quantity -= used[bookLevelKey{side, lvl.Price}]
+		if lvl.Quantity > epsilon {
+			available = append(available, lvl)
+		}
+	}
+	for key := range used {
+		if key.side == side && !present[key.price] {
+			delete(used, key)
+		}
+	}
+	return available, nil
+}
+
+// consumeLocked запоминает объем, забранный из стакана пары.
+func (p *PaperExchange) consumeLocked(pair string, side exmo.Type, fills []exmo.BookLevel) {
+	if len(fills) == 0 {
+		return
+	}
+	if p.consumed[pair] == nil {
+		p.consumed[pair] = make(map[bookLevelKey]float64)
+	}
+	for _, f := range fills {
+		p.consumed[pair][bookLevelKey{side, f.Price}] += f.Quantity
+	}
+}
+
+// takerFills проходит по противоположной стороне стакана и возвращает объемы,
+// которые ордер может забрать немедленно с учетом своей лимитной цены.
+func takerFills(side []exmo.BookLevel, order *paperOrder) []exmo.BookLevel {
+	var fills []exmo.BookLevel
+	left := order.quantity
+	for _, lvl := range side {
+		if left <= 0 {
+			break
+		}
+		if !order.orderType.IsMarket() {
+			if order.orderType.Side() == exmo.Buy && lvl.Price > order.price {
+				break
+			}
+			if order.orderType.Side() == exmo.Sell && lvl.Price < order.price {
+				break
+			}
+		}
+		qty := lvl.Quantity
+		if qty > left {
+			qty = left
+		}
+		fills = append(fills, exmo.BookLevel{Price: lvl.Price, Quantity: qty})
+		left -= qty
+	}
+	return fills
+}
+
+func sumQuantity(levels []exmo.BookLevel) float64 {
+	var sum float64
+	for _, l := range levels {
+		sum += l.Quantity
+	}
+	return sum
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
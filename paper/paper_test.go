@@ -0,0 +1,226 @@
+package paper
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func paperBook() exmo.OrderBook {
+	return exmo.OrderBook{
+		"BTC_USD": {
+			Ask: [][]string{{"50000", "1", "50000"}, {"51000", "2", "102000"}},
+			Bid: [][]string{{"49000", "1", "49000"}, {"48000", "2", "96000"}},
+		},
+	}
+}
+
+func TestPaperExchange_PassThrough(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{"BTC_USD": {LastTrade: "50000"}}, nil)
+
+	var ex exmo.Exchanger = NewPaperExchange(mockExchanger)
+	ticker, err := ex.GetTicker()
+
+	assert.NoError(t, err)
+	assert.Equal(t, "50000", ticker["BTC_USD"].LastTrade)
+}
+
+func TestPaperExchange_MarketOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(paperBook(), nil).AnyTimes()
+
+	paper := NewPaperExchange(mockExchanger,
+		WithPaperBalance("USD", 200000),
+		WithPaperFees(0.001, 0.001),
+	)
+
+	t.Run("market buy walks the book", func(t *testing.T) {
+		id, err := paper.CreateOrder("BTC_USD", 2, 0, exmo.OrderMarketBuy)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+
+		info, err := paper.GetUserInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "99000", info.Balances["USD"])
+		assert.Equal(t, "1.998", info.Balances["BTC"])
+
+		trades, err := paper.GetUserTrades(10, "BTC_USD")
+		require.NoError(t, err)
+		require.Len(t, trades["BTC_USD"], 2)
+		assert.Equal(t, "51000", trades["BTC_USD"][0].Price)
+		assert.Equal(t, "taker", trades["BTC_USD"][0].ExecType)
+		assert.Equal(t, "BTC", trades["BTC_USD"][0].CommissionCurrency)
+	})
+
+	t.Run("market sell", func(t *testing.T) {
+		_, err := paper.CreateOrder("BTC_USD", 1, 0, exmo.OrderMarketSell)
+		require.NoError(t, err)
+
+		info, err := paper.GetUserInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "0.998", info.Balances["BTC"])
+		assert.Equal(t, "147951", info.Balances["USD"])
+	})
+
+	t.Run("insufficient funds", func(t *testing.T) {
+		_, err := paper.CreateOrder("BTC_USD", 3, 0, exmo.OrderMarketSell)
+		assert.ErrorIs(t, err, ErrInsufficientFunds)
+	})
+}
+
+func TestPaperExchange_MarketOrderRemainder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(paperBook(), nil).Times(2)
+
+	paper := NewPaperExchange(mockExchanger, WithPaperBalance("USD", 500000), WithPaperFees(0, 0))
+	id, err := paper.CreateOrder("BTC_USD", 5, 0, exmo.OrderMarketBuy)
+	assert.ErrorIs(t, err, ErrNoLiquidity)
+	assert.EqualError(t, err, "not enough liquidity in order book: market order 1 filled 3 of 5")
+	assert.Equal(t, int64(1), id, "partial fill is executed")
+
+	info, err := paper.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "3", info.Balances["BTC"])
+	assert.Equal(t, "348000", info.Balances["USD"])
+	assert.Empty(t, info.Reserved, "remainder is not kept")
+
+	// Неизмененный стакан уже выбран до конца
+	_, err = paper.CreateOrder("BTC_USD", 1, 0, exmo.OrderMarketBuy)
+	assert.ErrorIs(t, err, ErrNoLiquidity)
+}
+
+func TestPaperExchange_ConsumedLiquidity(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	paper := NewPaperExchange(mockExchanger, WithPaperBalance("USD", 100000), WithPaperFees(0, 0))
+
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(paperBook(), nil).Times(2)
+	_, err := paper.CreateOrder("BTC_USD", 1, 45000, exmo.OrderBuy)
+	require.NoError(t, err)
+	_, err = paper.CreateOrder("BTC_USD", 1, 45000, exmo.OrderBuy)
+	require.NoError(t, err)
+
+	// Одного лота на 44000 хватает только первому ордеру, и на следующем
+	// опросе того же стакана он не исполняет второй
+	crossed := exmo.OrderBook{"BTC_USD": {Ask: [][]string{{"44000", "1", "44000"}}}}
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(crossed, nil).Times(2)
+	for i := 0; i < 2; i++ {
+		orders, err := paper.GetOpenOrders()
+		require.NoError(t, err)
+		assert.Len(t, orders["BTC_USD"], 1)
+	}
+
+	// Уровень ушел из стакана - учет по нему сброшен, новый уровень исполняет второй ордер
+	moved := exmo.OrderBook{"BTC_USD": {Ask: [][]string{{"43000", "1", "43000"}}}}
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(moved, nil)
+	info, err := paper.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "2", info.Balances["BTC"])
+	assert.Empty(t, info.Reserved)
+}
+
+func TestPaperExchange_LimitOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Unix(1700000000, 0)
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	paper := NewPaperExchange(mockExchanger,
+		WithPaperBalance("USD", 100000),
+		WithPaperFees(0, 0),
+		WithPaperClock(func() time.Time { return now }),
+	)
+
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(paperBook(), nil).Times(3)
+	id, err := paper.CreateOrder("BTC_USD", 1, 45000, exmo.OrderBuy)
+	require.NoError(t, err)
+
+	orders, err := paper.GetOpenOrders()
+	require.NoError(t, err)
+	require.Len(t, orders["BTC_USD"], 1)
+	assert.Equal(t, id, orders["BTC_USD"][0].OrderID)
+	assert.Equal(t, now.Unix(), orders["BTC_USD"][0].Created)
+
+	info, err := paper.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "55000", info.Balances["USD"])
+	assert.Equal(t, "45000", info.Reserved["USD"])
+
+	// Рынок опустился ниже цены ордера - ордер исполняется как мейкер
+	crossed := exmo.OrderBook{"BTC_USD": {Ask: [][]string{{"44000", "5", "220000"}}}}
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(crossed, nil)
+	info, err = paper.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "1", info.Balances["BTC"])
+	assert.Equal(t, "55000", info.Balances["USD"])
+	assert.Empty(t, info.Reserved)
+
+	trades, err := paper.GetUserTrades(10, "BTC_USD")
+	require.NoError(t, err)
+	require.Len(t, trades["BTC_USD"], 1)
+	assert.Equal(t, "45000", trades["BTC_USD"][0].Price)
+	assert.Equal(t, "maker", trades["BTC_USD"][0].ExecType)
+
+	assert.ErrorIs(t, paper.CancelOrder(id), ErrOrderNotFound)
+}
+
+func TestPaperExchange_CancelOrder(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetOrderBook(100, "BTC_USD").Return(paperBook(), nil)
+
+	paper := NewPaperExchange(mockExchanger, WithPaperBalance("BTC", 1))
+	id, err := paper.CreateOrder("BTC_USD", 1, 60000, exmo.OrderSell)
+	require.NoError(t, err)
+
+	require.NoError(t, paper.CancelOrder(id))
+
+	info, err := paper.GetUserInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "1", info.Balances["BTC"])
+	assert.Empty(t, info.Reserved)
+}
+
+func TestPaperExchange_CreateOrder_Validation(t *testing.T) {
+	paper := NewPaperExchange(nil)
+
+	testCases := []struct {
+		name      string
+		pair      string
+		quantity  float64
+		price     float64
+		orderType exmo.OrderType
+	}{
+		{name: "invalid pair", pair: "BTCUSD", quantity: 1, price: 1, orderType: exmo.OrderBuy},
+		{name: "zero quantity", pair: "BTC_USD", quantity: 0, price: 1, orderType: exmo.OrderBuy},
+		{name: "zero price", pair: "BTC_USD", quantity: 1, price: 0, orderType: exmo.OrderSell},
+		{name: "unknown type", pair: "BTC_USD", quantity: 1, price: 1, orderType: "stop"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := paper.CreateOrder(tc.pair, tc.quantity, tc.price, tc.orderType)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,382 @@
+// Package portfolio ведет позиции по исполненным сделкам, считает
+// реализованную и нереализованную прибыль методами FIFO, LIFO и средней
+// цены и оценивает балансы в выбранной валюте.
+package portfolio
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/convert"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// epsilon - остаток количества, который считается нулевым.
+const epsilon = 1e-12
+
+// CostBasisMethod определяет, какие лоты списываются при продаже.
+type CostBasisMethod string
+
+const (
+	CostFIFO    CostBasisMethod = "fifo"
+	CostLIFO    CostBasisMethod = "lifo"
+	CostAverage CostBasisMethod = "average"
+)
+
+// Fill - исполненная сделка, из которой строится портфель.
+type Fill struct {
+	TradeID     int64
+	Pair        string
+	Side        exmo.Type
+	Quantity    float64
+	Price       float64
+	Fee         float64
+	FeeCurrency string
+	Time        time.Time
+}
+
+// FillFromUserTrade переводит сделку из user_trades в Fill.
+func FillFromUserTrade(t exmo.UserTrade) (Fill, error) {
+	f := Fill{
+		TradeID:     t.TradeID,
+		Pair:        t.Pair,
+		Side:        t.Type,
+		FeeCurrency: t.CommissionCurrency,
+		Time:        time.Unix(t.Date, 0),
+	}
+	var err error
+	if f.Quantity, err = strconv.ParseFloat(t.Quantity, 64); err != nil {
+		return Fill{}, fmt.Errorf("trade %d: parse quantity: %w", t.TradeID, err)
+	}
+	if f.Price, err = strconv.ParseFloat(t.Price, 64); err != nil {
+		return Fill{}, fmt.Errorf("trade %d: parse price: %w", t.TradeID, err)
+	}
+	if t.CommissionAmount != "" {
+		if f.Fee, err = strconv.ParseFloat(t.CommissionAmount, 64); err != nil {
+			return Fill{}, fmt.Errorf("trade %d: parse commission: %w", t.TradeID, err)
+		}
+	}
+	return f, nil
+}
+
+// FillsFromUserTrades разворачивает ответ user_trades в хронологический список сделок.
+func FillsFromUserTrades(trades exmo.UserTrades) ([]Fill, error) {
+	var fills []Fill
+	for _, list := range trades {
+		for _, t := range list {
+			f, err := FillFromUserTrade(t)
+			if err != nil {
+				return nil, err
+			}
+			fills = append(fills, f)
+		}
+	}
+	sort.SliceStable(fills, func(i, j int) bool {
+		if !fills[i].Time.Equal(fills[j].Time) {
+			return fills[i].Time.Before(fills[j].Time)
+		}
+		return fills[i].TradeID < fills[j].TradeID
+	})
+	return fills, nil
+}
+
+type lot struct {
+	quantity float64
+	cost     float64 // цена единицы с учетом комиссии
+}
+
+type position struct {
+	lots      []lot
+	realized  float64
+	fees      float64
+	unmatched float64
+}
+
+// Portfolio ведет балансы по валютам и позиции по парам.
+// PnL и комиссии позиций считаются в котируемой валюте пары.
+type Portfolio struct {
+	method    CostBasisMethod
+	balances  map[string]float64
+	fees      map[string]float64
+	positions map[string]*position
+}
+
+type PortfolioOption func(*Portfolio)
+
+// WithInitialBalance задает баланс валюты до первой сделки.
+func WithInitialBalance(currency string, amount float64) PortfolioOption {
+	return func(p *Portfolio) {
+		p.balances[currency] = amount
+	}
+}
+
+func NewPortfolio(method CostBasisMethod, opts ...PortfolioOption) (*Portfolio, error) {
+	switch method {
+	case CostFIFO, CostLIFO, CostAverage:
+	default:
+		return nil, fmt.Errorf("unknown cost basis method %q", method)
+	}
+	p := &Portfolio{
+		method:    method,
+		balances:  make(map[string]float64),
+		fees:      make(map[string]float64),
+		positions: make(map[string]*position),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p, nil
+}
+
+// Apply учитывает сделку в балансах и позиции.
+func (p *Portfolio) Apply(f Fill) error {
+	base, quote, err := exmo.SplitPair(f.Pair)
+	if err != nil {
+		return err
+	}
+	if f.Quantity <= 0 || f.Price <= 0 {
+		return fmt.Errorf("trade %d: quantity and price must be positive", f.TradeID)
+	}
+	if f.Fee != 0 && f.FeeCurrency != base && f.FeeCurrency != quote {
+		return fmt.Errorf("trade %d: fee currency %q is not part of %s", f.TradeID, f.FeeCurrency, f.Pair)
+	}
+
+	pos := p.positions[f.Pair]
+	if pos == nil {
+		pos = &position{}
+		p.positions[f.Pair] = pos
+	}
+
+	amount := f.Quantity * f.Price
+	feeInQuote := f.Fee
+	if f.FeeCurrency == base {
+		feeInQuote = f.Fee * f.Price
+	}
+	p.fees[f.FeeCurrency] += f.Fee
+	pos.fees += feeInQuote
+
+	switch f.Side {
+	case exmo.Buy:
+		received, cost := f.Quantity, amount
+		if f.FeeCurrency == base {
+			received -= f.Fee
+		} else {
+			cost += f.Fee
+		}
+		p.balances[quote] -= cost
+		p.balances[base] += received
+		p.addLot(pos, lot{quantity: received, cost: cost / received})
+	case exmo.Sell:
+		sold, proceeds := f.Quantity, amount
+		if f.FeeCurrency == base {
+			sold += f.Fee
+		} else {
+			proceeds -= f.Fee
+		}
+		p.balances[base] -= sold
+		p.balances[quote] += proceeds
+		basis := p.consumeLots(pos, sold)
+		pos.realized += proceeds - basis
+	default:
+		return fmt.Errorf("trade %d: unknown side %q", f.TradeID, f.Side)
+	}
+	return nil
+}
+
+// ApplyAll учитывает сделки по порядку.
+func (p *Portfolio) ApplyAll(fills []Fill) error {
+	for _, f := range fills {
+		if err := p.Apply(f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Portfolio) addLot(pos *position, l lot) {
+	if p.method == CostAverage && len(pos.lots) > 0 {
+		cur := pos.lots[0]
+		qty := cur.quantity + l.quantity
+		pos.lots[0] = lot{quantity: qty, cost: (cur.quantity*cur.cost + l.quantity*l.cost) / qty}
+		return
+	}
+	pos.lots = append(pos.lots, l)
+}
+
+// consumeLots списывает qty из лотов и возвращает их суммарную стоимость.
+// Продажа сверх известных лотов не дает PnL: ее базис равен нулю, а объем
+// учитывается отдельно как несопоставленный.
+func (p *Portfolio) consumeLots(pos *position, qty float64) float64 {
+	var basis float64
+	for qty > epsilon && len(pos.lots) > 0 {
+		i := 0
+		if p.method == CostLIFO {
+			i = len(pos.lots) - 1
+		}
+		l := &pos.lots[i]
+		take := qty
+		if take > l.quantity {
+			take = l.quantity
+		}
+		basis += take * l.cost
+		l.quantity -= take
+		qty -= take
+		if l.quantity <= epsilon {
+			pos.lots = append(pos.lots[:i], pos.lots[i+1:]...)
+		}
+	}
+	if qty > epsilon {
+		pos.unmatched += qty
+	}
+	return basis
+}
+
+// Balances возвращает копию балансов по валютам.
+func (p *Portfolio) Balances() map[string]float64 {
+	result := make(map[string]float64, len(p.balances))
+	for c, v := range p.balances {
+		result[c] = v
+	}
+	return result
+}
+
+// Fees возвращает сумму уплаченных комиссий по валютам.
+func (p *Portfolio) Fees() map[string]float64 {
+	result := make(map[string]float64, len(p.fees))
+	for c, v := range p.fees {
+		result[c] = v
+	}
+	return result
+}
+
+// PositionReport - состояние позиции по паре, суммы в котируемой валюте.
+type PositionReport struct {
+	Pair              string  `json:"pair"`
+	Quantity          float64 `json:"quantity"`
+	AverageCost       float64 `json:"average_cost"`
+	CostBasis         float64 `json:"cost_basis"`
+	MarketPrice       float64 `json:"market_price"`
+	MarketValue       float64 `json:"market_value"`
+	RealizedPnL       float64 `json:"realized_pnl"`
+	UnrealizedPnL     float64 `json:"unrealized_pnl"`
+	Fees              float64 `json:"fees"`
+	UnmatchedQuantity float64 `json:"unmatched_quantity,omitempty"`
+}
+
+// Positions возвращает позиции без рыночной оценки, отсортированные по паре.
+func (p *Portfolio) Positions() []PositionReport {
+	reports := make([]PositionReport, 0, len(p.positions))
+	for pair, pos := range p.positions {
+		r := PositionReport{
+			Pair:              pair,
+			RealizedPnL:       pos.realized,
+			Fees:              pos.fees,
+			UnmatchedQuantity: pos.unmatched,
+		}
+		for _, l := range pos.lots {
+			r.Quantity += l.quantity
+			r.CostBasis += l.quantity * l.cost
+		}
+		if r.Quantity > 0 {
+			r.AverageCost = r.CostBasis / r.Quantity
+		}
+		reports = append(reports, r)
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Pair < reports[j].Pair })
+	return reports
+}
+
+// BalanceValue - баланс валюты и его стоимость в валюте оценки.
+type BalanceValue struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	Value    float64 `json:"value"`
+}
+
+// Valuation - отчет о стоимости портфеля в выбранной валюте.
+type Valuation struct {
+	Currency      string           `json:"currency"`
+	Total         float64          `json:"total"`
+	RealizedPnL   float64          `json:"realized_pnl"`
+	UnrealizedPnL float64          `json:"unrealized_pnl"`
+	Fees          float64          `json:"fees"`
+	Balances      []BalanceValue   `json:"balances"`
+	Positions     []PositionReport `json:"positions"`
+}
+
+// Valuate оценивает портфель по текущему тикеру биржи в валюте currency,
+// которая должна присутствовать в GetCurrencies.
+func (p *Portfolio) Valuate(ex exmo.Exchanger, currency string) (Valuation, error) {
+	currencies, err := ex.GetCurrencies()
+	if err != nil {
+		return Valuation{}, err
+	}
+	if _, ok := currencies[currency]; !ok {
+		return Valuation{}, fmt.Errorf("unknown currency %q", currency)
+	}
+	ticker, err := ex.GetTicker()
+	if err != nil {
+		return Valuation{}, err
+	}
+	return p.ValuateWithTicker(ticker, currency)
+}
+
+// ValuateWithTicker оценивает портфель по переданному тикеру. Позиции
+// размечаются по цене последней сделки, валюты переводятся через ConversionGraph.
+func (p *Portfolio) ValuateWithTicker(ticker exmo.Ticker, currency string) (Valuation, error) {
+	v := Valuation{Currency: currency}
+	graph := convert.NewConversionGraph(ticker)
+
+	for c, amount := range p.balances {
+		value, err := graph.Convert(amount, c, currency)
+		if err != nil {
+			return Valuation{}, err
+		}
+		v.Balances = append(v.Balances, BalanceValue{Currency: c, Amount: amount, Value: value})
+		v.Total += value
+	}
+	sort.Slice(v.Balances, func(i, j int) bool { return v.Balances[i].Currency < v.Balances[j].Currency })
+
+	for _, r := range p.Positions() {
+		_, quote, _ := exmo.SplitPair(r.Pair)
+		if r.Quantity > 0 {
+			price, err := lastTradePrice(ticker, r.Pair)
+			if err != nil {
+				return Valuation{}, err
+			}
+			r.MarketPrice = price
+			r.MarketValue = r.Quantity * price
+			r.UnrealizedPnL = r.MarketValue - r.CostBasis
+		}
+		for _, item := range []struct {
+			src float64
+			dst *float64
+		}{
+			{r.RealizedPnL, &v.RealizedPnL},
+			{r.UnrealizedPnL, &v.UnrealizedPnL},
+			{r.Fees, &v.Fees},
+		} {
+			converted, err := graph.Convert(item.src, quote, currency)
+			if err != nil {
+				return Valuation{}, err
+			}
+			*item.dst += converted
+		}
+		v.Positions = append(v.Positions, r)
+	}
+	return v, nil
+}
+
+func lastTradePrice(ticker exmo.Ticker, pair string) (float64, error) {
+	value, ok := ticker[pair]
+	if !ok {
+		return 0, fmt.Errorf("no ticker for pair %s", pair)
+	}
+	price, err := strconv.ParseFloat(value.LastTrade, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse last trade for %s: %w", pair, err)
+	}
+	return price, nil
+}
@@ -0,0 +1,165 @@
+package portfolio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+func portfolioFills() []Fill {
+	return []Fill{
+		{TradeID: 1, Pair: "BTC_USD", Side: exmo.Buy, Quantity: 1, Price: 100},
+		{TradeID: 2, Pair: "BTC_USD", Side: exmo.Buy, Quantity: 1, Price: 200},
+		{TradeID: 3, Pair: "BTC_USD", Side: exmo.Sell, Quantity: 1, Price: 300},
+	}
+}
+
+func TestPortfolio_CostBasisMethods(t *testing.T) {
+	testCases := []struct {
+		method       CostBasisMethod
+		realized     float64
+		averageCost  float64
+		remainingQty float64
+	}{
+		{method: CostFIFO, realized: 200, averageCost: 200, remainingQty: 1},
+		{method: CostLIFO, realized: 100, averageCost: 100, remainingQty: 1},
+		{method: CostAverage, realized: 150, averageCost: 150, remainingQty: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.method), func(t *testing.T) {
+			p, err := NewPortfolio(tc.method, WithInitialBalance("USD", 1000))
+			require.NoError(t, err)
+			require.NoError(t, p.ApplyAll(portfolioFills()))
+
+			positions := p.Positions()
+			require.Len(t, positions, 1)
+			assert.InDelta(t, tc.realized, positions[0].RealizedPnL, 1e-9)
+			assert.InDelta(t, tc.averageCost, positions[0].AverageCost, 1e-9)
+			assert.InDelta(t, tc.remainingQty, positions[0].Quantity, 1e-9)
+
+			balances := p.Balances()
+			assert.InDelta(t, 1000, balances["USD"], 1e-9)
+			assert.InDelta(t, 1, balances["BTC"], 1e-9)
+		})
+	}
+}
+
+func TestPortfolio_Fees(t *testing.T) {
+	p, err := NewPortfolio(CostFIFO)
+	require.NoError(t, err)
+
+	require.NoError(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Buy, Quantity: 1, Price: 100, Fee: 0.01, FeeCurrency: "BTC"}))
+	require.NoError(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Sell, Quantity: 0.99, Price: 200, Fee: 0.198, FeeCurrency: "USD"}))
+
+	positions := p.Positions()
+	require.Len(t, positions, 1)
+	// Базис 0.99 BTC равен 100 USD, выручка 198 - 0.198
+	assert.InDelta(t, 97.802, positions[0].RealizedPnL, 1e-9)
+	assert.InDelta(t, 1.198, positions[0].Fees, 1e-9)
+	assert.InDelta(t, 0, positions[0].Quantity, 1e-9)
+	assert.InDelta(t, 0.01, p.Fees()["BTC"], 1e-9)
+	assert.InDelta(t, 0.198, p.Fees()["USD"], 1e-9)
+}
+
+func TestPortfolio_Apply_Errors(t *testing.T) {
+	_, err := NewPortfolio("hifo")
+	assert.Error(t, err)
+
+	p, err := NewPortfolio(CostFIFO)
+	require.NoError(t, err)
+	assert.Error(t, p.Apply(Fill{Pair: "BTCUSD", Side: exmo.Buy, Quantity: 1, Price: 1}))
+	assert.Error(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Buy, Quantity: 0, Price: 1}))
+	assert.Error(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Buy, Quantity: 1, Price: 1, Fee: 1, FeeCurrency: "EUR"}))
+}
+
+func TestPortfolio_UnmatchedSell(t *testing.T) {
+	p, err := NewPortfolio(CostFIFO)
+	require.NoError(t, err)
+	require.NoError(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Buy, Quantity: 1, Price: 100}))
+	require.NoError(t, p.Apply(Fill{Pair: "BTC_USD", Side: exmo.Sell, Quantity: 2, Price: 150}))
+
+	positions := p.Positions()
+	assert.InDelta(t, 200, positions[0].RealizedPnL, 1e-9)
+	assert.InDelta(t, 1, positions[0].UnmatchedQuantity, 1e-9)
+}
+
+func TestFillsFromUserTrades(t *testing.T) {
+	trades := exmo.UserTrades{
+		"BTC_USD": {
+			{TradeID: 2, Date: 200, Type: exmo.Sell, Pair: "BTC_USD", Quantity: "1", Price: "300", CommissionAmount: "0.6", CommissionCurrency: "USD"},
+			{TradeID: 1, Date: 100, Type: exmo.Buy, Pair: "BTC_USD", Quantity: "1", Price: "100"},
+		},
+	}
+	fills, err := FillsFromUserTrades(trades)
+
+	require.NoError(t, err)
+	require.Len(t, fills, 2)
+	assert.Equal(t, int64(1), fills[0].TradeID)
+	assert.Equal(t, time.Unix(200, 0), fills[1].Time)
+	assert.Equal(t, 0.6, fills[1].Fee)
+
+	_, err = FillsFromUserTrades(exmo.UserTrades{"BTC_USD": {{Quantity: "x", Price: "1"}}})
+	assert.Error(t, err)
+}
+
+func TestPortfolio_Valuate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	p, err := NewPortfolio(CostFIFO, WithInitialBalance("USD", 1000))
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyAll(portfolioFills()))
+
+	mockExchanger := exmomock.NewMockExchanger(ctrl)
+	mockExchanger.EXPECT().GetCurrencies().Return(exmo.Currencies{"BTC": {}, "USD": {}, "EUR": {}}, nil).Times(2)
+	mockExchanger.EXPECT().GetTicker().Return(exmo.Ticker{
+		"BTC_USD": {LastTrade: "400"},
+		"BTC_EUR": {LastTrade: "200"},
+		"EUR_USD": {LastTrade: "2"},
+	}, nil)
+
+	v, err := p.Valuate(mockExchanger, "EUR")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", v.Currency)
+	// 1000 USD + 1 BTC * 400 = 1400 USD = 700 EUR
+	assert.InDelta(t, 700, v.Total, 1e-9)
+	assert.InDelta(t, 100, v.RealizedPnL, 1e-9)
+	assert.InDelta(t, 100, v.UnrealizedPnL, 1e-9)
+	require.Len(t, v.Positions, 1)
+	assert.InDelta(t, 400, v.Positions[0].MarketValue, 1e-9)
+
+	_, err = p.Valuate(mockExchanger, "RUB")
+	assert.Error(t, err)
+}
+
+func TestPortfolio_ValuateWithTicker_CrossRates(t *testing.T) {
+	p, err := NewPortfolio(CostFIFO, WithInitialBalance("USD", 1000))
+	require.NoError(t, err)
+	require.NoError(t, p.ApplyAll(portfolioFills()))
+
+	// Прямой пары BTC_RUB нет - курс строится через USD
+	ticker := exmo.Ticker{
+		"BTC_USD": {LastTrade: "400"},
+		"USD_RUB": {LastTrade: "90"},
+	}
+
+	v, err := p.ValuateWithTicker(ticker, "RUB")
+	require.NoError(t, err)
+	// 1000 USD * 90 + 1 BTC * 400 * 90
+	assert.InDelta(t, 126000, v.Total, 1e-6)
+	assert.InDelta(t, 18000, v.RealizedPnL, 1e-6)
+	assert.InDelta(t, 18000, v.UnrealizedPnL, 1e-6)
+	require.Len(t, v.Balances, 2)
+	assert.Equal(t, "BTC", v.Balances[0].Currency)
+	assert.InDelta(t, 36000, v.Balances[0].Value, 1e-6)
+
+	_, err = p.ValuateWithTicker(ticker, "JPY")
+	assert.Error(t, err)
+}
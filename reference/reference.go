@@ -0,0 +1,316 @@
+// Package reference сравнивает тикеры пары на нескольких биржах, считает
+// устойчивую опорную цену и помечает биржи с отклоняющимися котировками.
+package reference
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// ErrNoReferencePrice - слишком мало бирж с пригодными котировками.
+var ErrNoReferencePrice = errors.New("no reference price")
+
+// ReferenceMethod - способ расчета опорной цены по последним ценам бирж.
+type ReferenceMethod string
+
+const (
+	ReferenceMedian ReferenceMethod = "median"
+	ReferenceVWAP   ReferenceMethod = "vwap" // взвешивание по объему за 24 часа
+)
+
+// DeviationFlag - причина, по которой котировка биржи помечена.
+type DeviationFlag string
+
+const (
+	FlagLast        DeviationFlag = "last"        // последняя цена далеко от опорной
+	FlagBid         DeviationFlag = "bid"         // лучшая цена покупки далеко от опорной
+	FlagAsk         DeviationFlag = "ask"         // лучшая цена продажи далеко от опорной
+	FlagStale       DeviationFlag = "stale"       // тикер давно не обновлялся
+	FlagUnavailable DeviationFlag = "unavailable" // тикер не получен
+)
+
+// PriceVenue - биржа, котировки которой сравниваются.
+type PriceVenue struct {
+	Name     string
+	Exchange exmo.Exchanger
+}
+
+// VenueQuote - котировка пары на бирже. Deviation - отклонение последней
+// цены от опорной в долях. Outlier означает, что цена отброшена как выброс
+// и не участвовала в расчете.
+type VenueQuote struct {
+	Venue     string          `json:"venue"`
+	Bid       float64         `json:"bid"`
+	Ask       float64         `json:"ask"`
+	Last      float64         `json:"last"`
+	Volume    float64         `json:"volume"`
+	Updated   time.Time       `json:"updated"`
+	Deviation float64         `json:"deviation"`
+	Outlier   bool            `json:"outlier"`
+	Flags     []DeviationFlag `json:"flags,omitempty"`
+	Err       error           `json:"-"`
+}
+
+// Flagged сообщает, есть ли у котировки хотя бы одна пометка.
+func (q VenueQuote) Flagged() bool {
+	return len(q.Flags) > 0
+}
+
+// ReferencePrice - опорная цена пары и котировки всех бирж. Used - число
+// бирж, по которым посчитана цена.
+type ReferencePrice struct {
+	Pair       string          `json:"pair"`
+	Price      float64         `json:"price"`
+	Method     ReferenceMethod `json:"method"`
+	Used       int             `json:"used"`
+	Venues     []VenueQuote    `json:"venues"`
+	ObservedAt time.Time       `json:"observed_at"`
+}
+
+// Flagged возвращает котировки с пометками.
+func (r ReferencePrice) Flagged() []VenueQuote {
+	var flagged []VenueQuote
+	for _, q := range r.Venues {
+		if q.Flagged() {
+			flagged = append(flagged, q)
+		}
+	}
+	return flagged
+}
+
+// PriceMonitor сравнивает тикеры пары на нескольких биржах, считает
+// устойчивую опорную цену и помечает биржи с отклоняющимися или
+// устаревшими котировками.
+type PriceMonitor struct {
+	venues       []PriceVenue
+	method       ReferenceMethod
+	maxDeviation float64
+	outlierK     float64
+	maxAge       time.Duration
+	minVenues    int
+	now          func() time.Time
+}
+
+type PriceMonitorOption func(*PriceMonitor)
+
+// WithReferenceMethod задает способ расчета опорной цены.
+func WithReferenceMethod(method ReferenceMethod) PriceMonitorOption {
+	return func(m *PriceMonitor) {
+		m.method = method
+	}
+}
+
+// WithMaxDeviation задает допустимое отклонение цен от опорной в долях
+// (0.01 = 1%).
+func WithMaxDeviation(d float64) PriceMonitorOption {
+	return func(m *PriceMonitor) {
+		m.maxDeviation = d
+	}
+}
+
+// WithOutlierThreshold задает порог отбрасывания выбросов в масштабированных
+// медианных абсолютных отклонениях (MAD), 0 - не отбрасывать.
+func WithOutlierThreshold(k float64) PriceMonitorOption {
+	return func(m *PriceMonitor) {
+		m.outlierK = k
+	}
+}
+
+// WithMaxAge задает возраст тикера, после которого котировка считается
+// устаревшей и не участвует в расчете. 0 - не проверять.
+func WithMaxAge(d time.Duration) PriceMonitorOption {
+	return func(m *PriceMonitor) {
+		m.maxAge = d
+	}
+}
+
+// WithMinVenues задает минимальное число бирж для опорной цены.
+func WithMinVenues(n int) PriceMonitorOption {
+	return func(m *PriceMonitor) {
+		m.minVenues = n
+	}
+}
+
+func NewPriceMonitor(venues []PriceVenue, opts ...PriceMonitorOption) (*PriceMonitor, error) {
+	m := &PriceMonitor{
+		venues:       venues,
+		method:       ReferenceMedian,
+		maxDeviation: 0.01,
+		outlierK:     3,
+		maxAge:       5 * time.Minute,
+		minVenues:    1,
+		now:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	if len(venues) == 0 {
+		return nil, errors.New("at least one venue is required")
+	}
+	names := make(map[string]bool, len(venues))
+	for _, v := range venues {
+		if v.Name == "" || v.Exchange == nil {
+			return nil, errors.New("venue name and exchange are required")
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("duplicate venue %q", v.Name)
+		}
+		names[v.Name] = true
+	}
+	if m.method != ReferenceMedian && m.method != ReferenceVWAP {
+		return nil, fmt.Errorf("unknown reference method %q", m.method)
+	}
+	if m.maxDeviation <= 0 {
+		return nil, fmt.Errorf("max deviation must be positive, got %v", m.maxDeviation)
+	}
+	if m.minVenues < 1 || m.minVenues > len(venues) {
+		return nil, fmt.Errorf("min venues must be in [1, %d], got %d", len(venues), m.minVenues)
+	}
+	return m, nil
+}
+
+// Reference загружает тикеры всех бирж и считает опорную цену пары.
+// Если пригодных котировок меньше минимума, вместе с котировками и
+// пометками возвращается ошибка ErrNoReferencePrice.
+func (m *PriceMonitor) Reference(pair string) (ReferencePrice, error) {
+	quotes := make([]VenueQuote, len(m.venues))
+	var wg sync.WaitGroup
+	for i, v := range m.venues {
+		wg.Add(1)
+		go func(i int, v PriceVenue) {
+			defer wg.Done()
+			quotes[i] = fetchVenueQuote(v, pair)
+		}(i, v)
+	}
+	wg.Wait()
+
+	now := m.now()
+	result := ReferencePrice{Pair: pair, Method: m.method, ObservedAt: now}
+	var usable []int
+	for i := range quotes {
+		q := &quotes[i]
+		switch {
+		case q.Err != nil:
+			q.Flags = append(q.Flags, FlagUnavailable)
+		case m.maxAge > 0 && now.Sub(q.Updated) > m.maxAge:
+			q.Flags = append(q.Flags, FlagStale)
+		default:
+			usable = append(usable, i)
+		}
+	}
+
+	// Выбросы ищутся по медиане и MAD: медиана не сдвигается одной плохой
+	// ценой, а MAD масштабируется к стандартному отклонению
+	if m.outlierK > 0 && len(usable) > 2 {
+		lasts := make([]float64, len(usable))
+		for j, i := range usable {
+			lasts[j] = quotes[i].Last
+		}
+		med := median(lasts)
+		for j := range lasts {
+			lasts[j] = math.Abs(lasts[j] - med)
+		}
+		// Допуск не меньше maxDeviation, иначе при совпадающих ценах
+		// (MAD = 0) выбросом стала бы любая разница
+		limit := math.Max(m.outlierK*1.4826*median(lasts), m.maxDeviation*med)
+		kept := usable[:0]
+		for _, i := range usable {
+			if math.Abs(quotes[i].Last-med) > limit {
+				quotes[i].Outlier = true
+				continue
+			}
+			kept = append(kept, i)
+		}
+		usable = kept
+	}
+
+	result.Used = len(usable)
+	if len(usable) < m.minVenues {
+		result.Venues = quotes
+		return result, fmt.Errorf("%w for %s: %d of %d venues usable, need %d",
+			ErrNoReferencePrice, pair, len(usable), len(quotes), m.minVenues)
+	}
+	result.Price = m.price(quotes, usable)
+
+	for i := range quotes {
+		q := &quotes[i]
+		if q.Err != nil {
+			continue
+		}
+		q.Deviation = (q.Last - result.Price) / result.Price
+		if math.Abs(q.Deviation) > m.maxDeviation {
+			q.Flags = append(q.Flags, FlagLast)
+		}
+		if q.Bid > 0 && math.Abs(q.Bid-result.Price)/result.Price > m.maxDeviation {
+			q.Flags = append(q.Flags, FlagBid)
+		}
+		if q.Ask > 0 && math.Abs(q.Ask-result.Price)/result.Price > m.maxDeviation {
+			q.Flags = append(q.Flags, FlagAsk)
+		}
+	}
+	result.Venues = quotes
+	return result, nil
+}
+
+// price считает опорную цену по котировкам с индексами usable. Без объемов
+// VWAP сводится к медиане.
+func (m *PriceMonitor) price(quotes []VenueQuote, usable []int) float64 {
+	lasts := make([]float64, len(usable))
+	var sum, volume float64
+	for j, i := range usable {
+		lasts[j] = quotes[i].Last
+		sum += quotes[i].Last * quotes[i].Volume
+		volume += quotes[i].Volume
+	}
+	if m.method == ReferenceVWAP && volume > 0 {
+		return sum / volume
+	}
+	return median(lasts)
+}
+
+func fetchVenueQuote(v PriceVenue, pair string) VenueQuote {
+	q := VenueQuote{Venue: v.Name}
+	ticker, err := v.Exchange.GetTicker()
+	if err != nil {
+		q.Err = err
+		return q
+	}
+	value, ok := ticker[pair]
+	if !ok {
+		q.Err = fmt.Errorf("%w: %s", exmo.ErrInvalidPair, pair)
+		return q
+	}
+	q.Bid, q.Ask, q.Last = parsePrice(value.BuyPrice), parsePrice(value.SellPrice), parsePrice(value.LastTrade)
+	q.Volume = parsePrice(value.Vol)
+	q.Updated = time.Unix(value.Updated, 0)
+	if q.Last == 0 {
+		q.Err = fmt.Errorf("no last price for %s", pair)
+	}
+	return q
+}
+
+// median возвращает медиану, values переупорядочивается.
+func median(values []float64) float64 {
+	sort.Float64s(values)
+	n := len(values)
+	if n%2 == 1 {
+		return values[n/2]
+	}
+	return (values[n/2-1] + values[n/2]) / 2
+}
+
+func parsePrice(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,126 @@
+package reference
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+)
+
+var referenceNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// referenceVenues: a, b и c согласованы, d печатает 120, тикер e
+// не обновлялся 10 минут, f недоступна.
+func referenceVenues(ctrl *gomock.Controller) []PriceVenue {
+	ticker := func(bid, ask, last, vol string, age time.Duration) exmo.Ticker {
+		return exmo.Ticker{"BTC_USD": {BuyPrice: bid, SellPrice: ask, LastTrade: last, Vol: vol, Updated: referenceNow.Add(-age).Unix()}}
+	}
+	venue := func(name string, t exmo.Ticker, err error) PriceVenue {
+		m := exmomock.NewMockExchanger(ctrl)
+		m.EXPECT().GetTicker().Return(t, err).AnyTimes()
+		return PriceVenue{Name: name, Exchange: m}
+	}
+	return []PriceVenue{
+		venue("a", ticker("99.9", "100.1", "100", "10", 10*time.Second), nil),
+		venue("b", ticker("100.9", "101.1", "101", "30", 0), nil),
+		venue("c", ticker("", "", "100.5", "20", time.Minute), nil),
+		venue("d", ticker("119", "121", "120", "1", 0), nil),
+		venue("e", ticker("100.3", "100.5", "100.4", "5", 10*time.Minute), nil),
+		venue("f", nil, exmo.ErrRateLimited),
+	}
+}
+
+func newTestPriceMonitor(t *testing.T, opts ...PriceMonitorOption) *PriceMonitor {
+	ctrl := gomock.NewController(t)
+	m, err := NewPriceMonitor(referenceVenues(ctrl), opts...)
+	require.NoError(t, err)
+	m.now = func() time.Time { return referenceNow }
+	return m
+}
+
+func TestPriceMonitor_Median(t *testing.T) {
+	ref, err := newTestPriceMonitor(t).Reference("BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, ReferenceMedian, ref.Method)
+	assert.Equal(t, 100.5, ref.Price)
+	assert.Equal(t, 3, ref.Used)
+	require.Len(t, ref.Venues, 6)
+
+	flags := make(map[string][]DeviationFlag)
+	for _, q := range ref.Flagged() {
+		flags[q.Venue] = q.Flags
+	}
+	assert.Equal(t, map[string][]DeviationFlag{
+		"d": {FlagLast, FlagBid, FlagAsk},
+		"e": {FlagStale},
+		"f": {FlagUnavailable},
+	}, flags)
+
+	d := ref.Venues[3]
+	assert.True(t, d.Outlier)
+	assert.InDelta(t, 19.5/100.5, d.Deviation, 1e-12)
+	assert.InDelta(t, -0.5/100.5, ref.Venues[0].Deviation, 1e-12)
+	assert.True(t, errors.Is(ref.Venues[5].Err, exmo.ErrRateLimited))
+}
+
+func TestPriceMonitor_VWAP(t *testing.T) {
+	ref, err := newTestPriceMonitor(t, WithReferenceMethod(ReferenceVWAP)).Reference("BTC_USD")
+	require.NoError(t, err)
+	assert.InDelta(t, 6040.0/60, ref.Price, 1e-12)
+
+	// Без отбрасывания выбросов 120 попадает в расчет и сдвигает медиану
+	ref, err = newTestPriceMonitor(t, WithOutlierThreshold(0)).Reference("BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, 4, ref.Used)
+	assert.Equal(t, 100.75, ref.Price)
+	assert.False(t, ref.Venues[3].Outlier)
+}
+
+func TestPriceMonitor_Thresholds(t *testing.T) {
+	// Без проверки возраста e участвует в расчете
+	ref, err := newTestPriceMonitor(t, WithMaxAge(0)).Reference("BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, 4, ref.Used)
+	assert.Empty(t, ref.Venues[4].Flags)
+
+	// При допуске 0.1% помечаются и цены биржи b
+	ref, err = newTestPriceMonitor(t, WithMaxDeviation(0.001)).Reference("BTC_USD")
+	require.NoError(t, err)
+	assert.Equal(t, []DeviationFlag{FlagLast, FlagBid, FlagAsk}, ref.Venues[1].Flags)
+
+	ref, err = newTestPriceMonitor(t, WithMinVenues(4)).Reference("BTC_USD")
+	assert.True(t, errors.Is(err, ErrNoReferencePrice))
+	assert.EqualError(t, err, "no reference price for BTC_USD: 3 of 6 venues usable, need 4")
+	assert.Zero(t, ref.Price)
+	assert.Len(t, ref.Flagged(), 2, "flags are reported without a price")
+
+	ref, err = newTestPriceMonitor(t).Reference("ETH_USD")
+	assert.True(t, errors.Is(err, ErrNoReferencePrice))
+	assert.True(t, errors.Is(ref.Venues[0].Err, exmo.ErrInvalidPair))
+}
+
+func TestNewPriceMonitor_Validation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	venues := referenceVenues(ctrl)
+
+	for _, tc := range []struct {
+		venues []PriceVenue
+		opts   []PriceMonitorOption
+		want   string
+	}{
+		{nil, nil, "at least one venue is required"},
+		{[]PriceVenue{venues[0], venues[0]}, nil, `duplicate venue "a"`},
+		{venues, []PriceMonitorOption{WithReferenceMethod("mean")}, `unknown reference method "mean"`},
+		{venues, []PriceMonitorOption{WithMaxDeviation(0)}, "max deviation must be positive, got 0"},
+		{venues[:2], []PriceMonitorOption{WithMinVenues(3)}, "min venues must be in [1, 2], got 3"},
+	} {
+		_, err := NewPriceMonitor(tc.venues, tc.opts...)
+		assert.EqualError(t, err, tc.want)
+	}
+}
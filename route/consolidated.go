@@ -0,0 +1,257 @@
+// Package route сводит стаканы одной пары с нескольких бирж в общую
+// лестницу с учетом комиссий и распределяет по ней рыночную заявку.
+package route
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// epsilon - остаток заявки, который считается нулевым.
+const epsilon = 1e-12
+
+// ErrNoLiquidity - глубины сводного стакана не хватило на всю заявку.
+var ErrNoLiquidity = errors.New("not enough liquidity in consolidated book")
+
+// BookVenue - биржа сводного стакана. TakerFee - комиссия тейкера в долях
+// (0.001 = 0.1%), ею корректируются цены уровней.
+type BookVenue struct {
+	Name     string
+	Exchange exmo.Exchanger
+	TakerFee float64
+}
+
+// VenueLevel - уровень сводного стакана. Price - цена биржи, EffectivePrice -
+// цена с комиссией: для ask выше на комиссию, для bid ниже.
+type VenueLevel struct {
+	Venue          string  `json:"venue"`
+	Price          float64 `json:"price"`
+	Quantity       float64 `json:"quantity"`
+	EffectivePrice float64 `json:"effective_price"`
+}
+
+// ConsolidatedBook - стаканы одной пары с нескольких бирж в одной лестнице.
+// Уровни упорядочены по EffectivePrice: Bids по убыванию, Asks по
+// возрастанию, при равной цене - в порядке бирж. Errors содержит биржи,
+// стаканы которых не удалось получить.
+type ConsolidatedBook struct {
+	Pair       string           `json:"pair"`
+	Bids       []VenueLevel     `json:"bids"`
+	Asks       []VenueLevel     `json:"asks"`
+	Errors     map[string]error `json:"-"`
+	ObservedAt time.Time        `json:"observed_at"`
+}
+
+// BestBid возвращает лучший уровень на покупку с учетом комиссий.
+func (b *ConsolidatedBook) BestBid() (VenueLevel, bool) {
+	if len(b.Bids) == 0 {
+		return VenueLevel{}, false
+	}
+	return b.Bids[0], true
+}
+
+// BestAsk возвращает лучший уровень на продажу с учетом комиссий.
+func (b *ConsolidatedBook) BestAsk() (VenueLevel, bool) {
+	if len(b.Asks) == 0 {
+		return VenueLevel{}, false
+	}
+	return b.Asks[0], true
+}
+
+// RouteSlice - часть заявки, направляемая на одну биржу. Total - сколько
+// валюты котировки будет потрачено на покупку или получено от продажи
+// с учетом комиссии Fee.
+type RouteSlice struct {
+	Venue    string  `json:"venue"`
+	Quantity float64 `json:"quantity"`
+	AvgPrice float64 `json:"avg_price"`
+	Fee      float64 `json:"fee"`
+	Total    float64 `json:"total"`
+}
+
+// RoutePlan - разбиение рыночной заявки по биржам. AvgPrice - средняя
+// цена с комиссиями по всей заявке.
+type RoutePlan struct {
+	Pair     string       `json:"pair"`
+	Side     exmo.Type    `json:"side"`
+	Quantity float64      `json:"quantity"`
+	Filled   float64      `json:"filled"`
+	AvgPrice float64      `json:"avg_price"`
+	Total    float64      `json:"total"`
+	Slices   []RouteSlice `json:"slices"`
+}
+
+func (p RoutePlan) String() string {
+	return fmt.Sprintf("%s %s %s: %s filled at %s across %d venues",
+		p.Side, formatFloat(p.Quantity), p.Pair, formatFloat(p.Filled), formatFloat(p.AvgPrice), len(p.Slices))
+}
+
+// Plan распределяет заявку на quantity базовой валюты по лучшим ценам с
+// учетом комиссий: покупка забирает Asks, продажа - Bids. Если глубины не
+// хватает, возвращается частичный план и ошибка ErrNoLiquidity.
+func (b *ConsolidatedBook) Plan(side exmo.Type, quantity float64) (RoutePlan, error) {
+	if side != exmo.Buy && side != exmo.Sell {
+		return RoutePlan{}, fmt.Errorf("unknown side %q", side)
+	}
+	if quantity <= 0 {
+		return RoutePlan{}, fmt.Errorf("quantity must be positive, got %v", quantity)
+	}
+	levels := b.Asks
+	if side == exmo.Sell {
+		levels = b.Bids
+	}
+
+	plan := RoutePlan{Pair: b.Pair, Side: side, Quantity: quantity}
+	slices := make(map[string]*RouteSlice)
+	notional := make(map[string]float64)
+	var order []string
+	rest := quantity
+	for _, l := range levels {
+		if rest <= epsilon {
+			break
+		}
+		qty := math.Min(rest, l.Quantity)
+		s, ok := slices[l.Venue]
+		if !ok {
+			s = &RouteSlice{Venue: l.Venue}
+			slices[l.Venue] = s
+			order = append(order, l.Venue)
+		}
+		s.Quantity += qty
+		s.Fee += math.Abs(l.EffectivePrice-l.Price) * qty
+		notional[l.Venue] += l.Price * qty
+		s.Total += l.EffectivePrice * qty
+		rest -= qty
+	}
+
+	for _, venue := range order {
+		s := slices[venue]
+		s.AvgPrice = notional[venue] / s.Quantity
+		plan.Filled += s.Quantity
+		plan.Total += s.Total
+		plan.Slices = append(plan.Slices, *s)
+	}
+	if plan.Filled > 0 {
+		plan.AvgPrice = plan.Total / plan.Filled
+	}
+	if rest > epsilon {
+		return plan, fmt.Errorf("%w: %s filled %v of %v", ErrNoLiquidity, b.Pair, plan.Filled, quantity)
+	}
+	return plan, nil
+}
+
+// BookConsolidator загружает стаканы пары со всех бирж параллельно и
+// сводит их в ConsolidatedBook.
+type BookConsolidator struct {
+	venues []BookVenue
+	depth  int
+	now    func() time.Time
+}
+
+type ConsolidatorOption func(*BookConsolidator)
+
+// WithConsolidatedDepth задает глубину стакана, запрашиваемого у каждой биржи.
+func WithConsolidatedDepth(limit int) ConsolidatorOption {
+	return func(c *BookConsolidator) {
+		c.depth = limit
+	}
+}
+
+// NewBookConsolidator создает сводный стакан по биржам. Имена бирж должны
+// быть уникальны: по ним уровни и части заявки привязываются к биржам.
+func NewBookConsolidator(venues []BookVenue, opts ...ConsolidatorOption) (*BookConsolidator, error) {
+	if len(venues) == 0 {
+		return nil, errors.New("at least one venue is required")
+	}
+	names := make(map[string]bool, len(venues))
+	for _, v := range venues {
+		if v.Name == "" || v.Exchange == nil {
+			return nil, errors.New("venue name and exchange are required")
+		}
+		if names[v.Name] {
+			return nil, fmt.Errorf("duplicate venue %q", v.Name)
+		}
+		if v.TakerFee < 0 || v.TakerFee >= 1 {
+			return nil, fmt.Errorf("venue %q: taker fee must be in [0, 1), got %v", v.Name, v.TakerFee)
+		}
+		names[v.Name] = true
+	}
+	c := &BookConsolidator{
+		venues: venues,
+		depth:  50,
+		now:    time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Book возвращает сводный стакан пары. Сбой отдельной биржи попадает в
+// ConsolidatedBook.Errors, ошибка возвращается, только если отказали все.
+func (c *BookConsolidator) Book(pair string) (*ConsolidatedBook, error) {
+	type venueBook struct {
+		bids, asks []exmo.BookLevel
+		err        error
+	}
+	books := make([]venueBook, len(c.venues))
+	var wg sync.WaitGroup
+	for i, v := range c.venues {
+		wg.Add(1)
+		go func(i int, v BookVenue) {
+			defer wg.Done()
+			book, err := v.Exchange.GetOrderBook(c.depth, pair)
+			if err != nil {
+				books[i].err = err
+				return
+			}
+			levels, ok := book[pair]
+			if !ok {
+				books[i].err = fmt.Errorf("no order book for pair %s", pair)
+				return
+			}
+			if books[i].bids, err = levels.Bids(); err == nil {
+				books[i].asks, err = levels.Asks()
+			}
+			books[i].err = err
+		}(i, v)
+	}
+	wg.Wait()
+
+	result := &ConsolidatedBook{Pair: pair, Errors: make(map[string]error), ObservedAt: c.now()}
+	var errs []error
+	for i, v := range c.venues {
+		if err := books[i].err; err != nil {
+			result.Errors[v.Name] = err
+			errs = append(errs, fmt.Errorf("%s: %w", v.Name, err))
+			continue
+		}
+		for _, l := range books[i].bids {
+			if l.Quantity > 0 {
+				result.Bids = append(result.Bids, VenueLevel{Venue: v.Name, Price: l.Price, Quantity: l.Quantity, EffectivePrice: l.Price * (1 - v.TakerFee)})
+			}
+		}
+		for _, l := range books[i].asks {
+			if l.Quantity > 0 {
+				result.Asks = append(result.Asks, VenueLevel{Venue: v.Name, Price: l.Price, Quantity: l.Quantity, EffectivePrice: l.Price * (1 + v.TakerFee)})
+			}
+		}
+	}
+	if len(errs) == len(c.venues) {
+		return nil, errors.Join(errs...)
+	}
+	sort.SliceStable(result.Bids, func(i, j int) bool { return result.Bids[i].EffectivePrice > result.Bids[j].EffectivePrice })
+	sort.SliceStable(result.Asks, func(i, j int) bool { return result.Asks[i].EffectivePrice < result.Asks[j].EffectivePrice })
+	return result, nil
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,150 @@
+package route
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/binance/binancetest"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmotest"
+)
+
+// consolidatedVenues поднимает Exmo с комиссией 0.2% и Binance с 0.1%
+// с пересекающимися стаканами BTC_USD.
+func consolidatedVenues(t *testing.T) []BookVenue {
+	fe := exmotest.NewFakeExmo()
+	t.Cleanup(fe.Close)
+	fe.AddOrder("BTC_USD", exmo.Sell, 100, 1)
+	fe.AddOrder("BTC_USD", exmo.Sell, 101, 2)
+	fe.AddOrder("BTC_USD", exmo.Buy, 99, 1)
+
+	fb := binancetest.NewFakeBinance()
+	t.Cleanup(fb.Close)
+	fb.AddSymbol("BTC", "USDT")
+	fb.SetBook("BTCUSDT",
+		[]exmo.BookLevel{{Price: 99.05, Quantity: 2}},
+		[]exmo.BookLevel{{Price: 100.1, Quantity: 1}, {Price: 100.3, Quantity: 5}},
+	)
+
+	return []BookVenue{
+		{Name: "exmo", Exchange: fe.Client(), TakerFee: 0.002},
+		{Name: "binance", Exchange: fb.Client(), TakerFee: 0.001},
+	}
+}
+
+func TestBookConsolidator_Book(t *testing.T) {
+	c, err := NewBookConsolidator(consolidatedVenues(t), WithConsolidatedDepth(10))
+	require.NoError(t, err)
+
+	book, err := c.Book("BTC_USD")
+	require.NoError(t, err)
+	assert.Empty(t, book.Errors)
+
+	venues := func(levels []VenueLevel) []string {
+		names := make([]string, len(levels))
+		for i, l := range levels {
+			names[i] = l.Venue
+		}
+		return names
+	}
+	// Ask Exmo 100 с комиссией 100.2 лучше ask Binance 100.1 с комиссией 100.2001
+	assert.Equal(t, []string{"exmo", "binance", "binance", "exmo"}, venues(book.Asks))
+	assert.Equal(t, []string{"binance", "exmo"}, venues(book.Bids))
+
+	ask, ok := book.BestAsk()
+	require.True(t, ok)
+	assert.Equal(t, "exmo", ask.Venue)
+	assert.Equal(t, 100.0, ask.Price)
+	assert.InDelta(t, 100.2, ask.EffectivePrice, 1e-9)
+	bid, ok := book.BestBid()
+	require.True(t, ok)
+	assert.Equal(t, "binance", bid.Venue)
+	assert.InDelta(t, 98.95095, bid.EffectivePrice, 1e-9)
+}
+
+func TestConsolidatedBook_Plan(t *testing.T) {
+	c, err := NewBookConsolidator(consolidatedVenues(t))
+	require.NoError(t, err)
+	book, err := c.Book("BTC_USD")
+	require.NoError(t, err)
+
+	plan, err := book.Plan(exmo.Buy, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3.0, plan.Filled)
+	require.Len(t, plan.Slices, 2)
+	assert.Equal(t, "exmo", plan.Slices[0].Venue)
+	assert.Equal(t, 1.0, plan.Slices[0].Quantity)
+	assert.InDelta(t, 0.2, plan.Slices[0].Fee, 1e-9)
+	assert.InDelta(t, 100.2, plan.Slices[0].Total, 1e-9)
+	assert.Equal(t, "binance", plan.Slices[1].Venue)
+	assert.Equal(t, 2.0, plan.Slices[1].Quantity)
+	assert.InDelta(t, 100.2, plan.Slices[1].AvgPrice, 1e-9)
+	assert.InDelta(t, 0.2004, plan.Slices[1].Fee, 1e-9)
+	assert.InDelta(t, 300.8004, plan.Total, 1e-9)
+	assert.InDelta(t, 300.8004/3, plan.AvgPrice, 1e-9)
+
+	plan, err = book.Plan(exmo.Sell, 2.5)
+	require.NoError(t, err)
+	require.Len(t, plan.Slices, 2)
+	assert.Equal(t, "binance", plan.Slices[0].Venue)
+	assert.Equal(t, 2.0, plan.Slices[0].Quantity)
+	assert.InDelta(t, 0.1981, plan.Slices[0].Fee, 1e-9)
+	assert.InDelta(t, 197.9019, plan.Slices[0].Total, 1e-9)
+	assert.Equal(t, 0.5, plan.Slices[1].Quantity)
+	assert.InDelta(t, 99*0.998*0.5, plan.Slices[1].Total, 1e-9)
+
+	plan, err = book.Plan(exmo.Sell, 5)
+	assert.True(t, errors.Is(err, ErrNoLiquidity))
+	assert.Equal(t, 3.0, plan.Filled, "partial plan is returned")
+
+	_, err = book.Plan(exmo.Buy, 0)
+	assert.Error(t, err)
+	_, err = book.Plan("hold", 1)
+	assert.Error(t, err)
+}
+
+func TestBookConsolidator_Errors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ok := exmomock.NewMockExchanger(ctrl)
+	ok.EXPECT().GetOrderBook(50, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {
+		Ask: [][]string{{"101", "1"}},
+		Bid: [][]string{{"100", "1"}},
+	}}, nil).Times(2)
+	failing := exmomock.NewMockExchanger(ctrl)
+	failing.EXPECT().GetOrderBook(50, "BTC_USD").Return(nil, exmo.ErrRateLimited).Times(2)
+	empty := exmomock.NewMockExchanger(ctrl)
+	empty.EXPECT().GetOrderBook(50, "BTC_USD").Return(exmo.OrderBook{}, nil)
+
+	c, err := NewBookConsolidator([]BookVenue{{Name: "a", Exchange: ok}, {Name: "b", Exchange: failing}})
+	require.NoError(t, err)
+	book, err := c.Book("BTC_USD")
+	require.NoError(t, err, "one venue is enough")
+	assert.Len(t, book.Asks, 1)
+	assert.True(t, errors.Is(book.Errors["b"], exmo.ErrRateLimited))
+
+	c, err = NewBookConsolidator([]BookVenue{{Name: "b", Exchange: failing}, {Name: "c", Exchange: empty}})
+	require.NoError(t, err)
+	_, err = c.Book("BTC_USD")
+	assert.True(t, errors.Is(err, exmo.ErrRateLimited))
+	assert.ErrorContains(t, err, "c: no order book for pair BTC_USD")
+
+	_, err = NewBookConsolidator(nil)
+	assert.Error(t, err)
+	_, err = NewBookConsolidator([]BookVenue{{Name: "a", Exchange: ok}, {Name: "a", Exchange: ok}})
+	assert.EqualError(t, err, `duplicate venue "a"`)
+	_, err = NewBookConsolidator([]BookVenue{{Name: "a", Exchange: ok, TakerFee: 1.5}})
+	assert.Error(t, err)
+
+	c, _ = NewBookConsolidator([]BookVenue{{Name: "a", Exchange: ok}})
+	book, err = c.Book("BTC_USD")
+	require.NoError(t, err)
+	_, err = book.Plan(exmo.Buy, 2)
+	assert.True(t, errors.Is(err, ErrNoLiquidity))
+}
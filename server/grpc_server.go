@@ -0,0 +1,426 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmopb"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+)
+
+// GRPCServer реализует сервис exmopb.MarketData поверх Exchanger и
+// Indicatorer. Потоки тикера и сделок берутся из Hub, поэтому клиенты
+// делят один опрос биржи; без хаба эти потоки недоступны.
+type GRPCServer struct {
+	exmopb.UnimplementedMarketDataServer
+
+	exchange  exmo.Exchanger
+	indicator indicator.Indicatorer
+	hub       *Hub
+	now       func() time.Time
+	done      <-chan struct{}
+}
+
+type GRPCOption func(*GRPCServer)
+
+func WithGRPCHub(h *Hub) GRPCOption {
+	return func(s *GRPCServer) {
+		s.hub = h
+	}
+}
+
+// WithGRPCContext завершает открытые потоки с кодом Unavailable, когда ctx
+// отменен. grpc.Server.GracefulStop ждет окончания вызовов и сам потоки не
+// прерывает, поэтому без этой опции остановка ждет, пока клиенты отключатся.
+func WithGRPCContext(ctx context.Context) GRPCOption {
+	return func(s *GRPCServer) {
+		s.done = ctx.Done()
+	}
+}
+
+func WithGRPCClock(now func() time.Time) GRPCOption {
+	return func(s *GRPCServer) {
+		s.now = now
+	}
+}
+
+func NewGRPCServer(exchange exmo.Exchanger, ind indicator.Indicatorer, opts ...GRPCOption) *GRPCServer {
+	s := &GRPCServer{
+		exchange:  exchange,
+		indicator: ind,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Register регистрирует сервис на gRPC-сервере.
+func (s *GRPCServer) Register(g *grpc.Server) {
+	exmopb.RegisterMarketDataServer(g, s)
+}
+
+func (s *GRPCServer) GetTicker(ctx context.Context, req *exmopb.GetTickerRequest) (*exmopb.GetTickerResponse, error) {
+	pairs, err := grpcPairs(req.GetPairs())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	ticker, err := exmo.BindContext(ctx, s.exchange).GetTicker()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &exmopb.GetTickerResponse{Ticker: make(map[string]*exmopb.TickerValue)}
+	if len(pairs) == 0 {
+		for p, v := range ticker {
+			resp.Ticker[p] = tickerToProto(v)
+		}
+		return resp, nil
+	}
+	for _, p := range pairs {
+		v, ok := ticker[p]
+		if !ok {
+			return nil, status.Errorf(codes.NotFound, "unknown pair %s", p)
+		}
+		resp.Ticker[p] = tickerToProto(v)
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetTrades(ctx context.Context, req *exmopb.GetTradesRequest) (*exmopb.GetTradesResponse, error) {
+	pairs, err := grpcPairs(req.GetPairs())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if len(pairs) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one pair is required")
+	}
+	trades, err := exmo.BindContext(ctx, s.exchange).GetTrades(pairs...)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &exmopb.GetTradesResponse{Trades: make(map[string]*exmopb.TradeList)}
+	for _, p := range pairs {
+		resp.Trades[p] = &exmopb.TradeList{Trades: tradesToProto(trades[p])}
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetOrderBook(ctx context.Context, req *exmopb.GetOrderBookRequest) (*exmopb.OrderBookPair, error) {
+	pair, err := pathPair(req.GetPair())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	limit := int(req.GetLimit())
+	if limit == 0 {
+		limit = 100
+	}
+	if limit < 0 || limit > maxServerLimit {
+		return nil, status.Errorf(codes.InvalidArgument, "limit must be from 1 to %d", maxServerLimit)
+	}
+	book, err := exmo.BindContext(ctx, s.exchange).GetOrderBook(limit, pair)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	v, ok := book[pair]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "unknown pair %s", pair)
+	}
+	return orderBookToProto(v), nil
+}
+
+func (s *GRPCServer) GetCandles(ctx context.Context, req *exmopb.GetCandlesRequest) (*exmopb.GetCandlesResponse, error) {
+	pair, err := pathPair(req.GetPair())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resolution := int(req.GetResolution())
+	from, to, err := s.grpcRange(req.GetFrom(), req.GetTo(), resolution)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	history, err := exmo.BindContext(ctx, s.exchange).GetCandlesHistory(pair, resolution, from, to)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+
+	resp := &exmopb.GetCandlesResponse{Candles: make([]*exmopb.Candle, len(history.Candles))}
+	for i, c := range history.Candles {
+		resp.Candles[i] = &exmopb.Candle{T: c.T, O: c.O, C: c.C, H: c.H, L: c.L, V: c.V}
+	}
+	return resp, nil
+}
+
+func (s *GRPCServer) GetIndicator(ctx context.Context, req *exmopb.IndicatorRequest) (*exmopb.IndicatorResponse, error) {
+	calc, err := s.indicatorFunc(req.GetName())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	pair, err := pathPair(req.GetPair())
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resolution, period := int(req.GetResolution()), int(req.GetPeriod())
+	if period <= 0 || period > maxServerPeriod {
+		return nil, status.Errorf(codes.InvalidArgument, "period must be from 1 to %d", maxServerPeriod)
+	}
+	from, to, err := s.grpcRange(req.GetFrom(), req.GetTo(), resolution)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	values, err := calc(ctx, pair, resolution, period, from, to)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &exmopb.IndicatorResponse{Values: values}, nil
+}
+
+func (s *GRPCServer) StreamTicker(req *exmopb.StreamRequest, stream exmopb.MarketData_StreamTickerServer) error {
+	return s.streamHub(stream.Context(), req.GetPairs(), HubTicker, func(ev HubEvent) error {
+		return stream.Send(&exmopb.TickerUpdate{
+			Seq:   ev.Seq,
+			Pair:  ev.Pair,
+			Time:  timestamppb.New(ev.Time),
+			Value: tickerToProto(ev.Data.(exmo.TickerValue)),
+		})
+	})
+}
+
+func (s *GRPCServer) StreamTrades(req *exmopb.StreamRequest, stream exmopb.MarketData_StreamTradesServer) error {
+	return s.streamHub(stream.Context(), req.GetPairs(), HubTrades, func(ev HubEvent) error {
+		return stream.Send(&exmopb.TradesUpdate{
+			Seq:    ev.Seq,
+			Pair:   ev.Pair,
+			Time:   timestamppb.New(ev.Time),
+			Trades: tradesToProto(ev.Data.([]exmo.Pair)),
+		})
+	})
+}
+
+var errShuttingDown = status.Error(codes.Unavailable, "server is shutting down")
+
+func (s *GRPCServer) streamHub(ctx context.Context, rawPairs []string, typ HubEventType, send func(HubEvent) error) error {
+	if s.hub == nil {
+		return status.Error(codes.Unimplemented, "streaming is disabled on this server")
+	}
+	pairs, err := grpcPairs(rawPairs)
+	if err != nil {
+		return grpcError(err)
+	}
+	sub, err := s.hub.Subscribe(HubFilter{Pairs: pairs, Types: []HubEventType{typ}})
+	if err != nil {
+		return grpcError(err)
+	}
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return errShuttingDown
+		case ev, ok := <-sub.C:
+			if !ok {
+				return status.Error(codes.Unavailable, "market data hub stopped")
+			}
+			if err := send(ev); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StreamIndicator пересчитывает индикатор раз в interval_seconds и
+// отправляет последнее значение, когда оно меняется.
+func (s *GRPCServer) StreamIndicator(req *exmopb.StreamIndicatorRequest, stream exmopb.MarketData_StreamIndicatorServer) error {
+	calc, err := s.indicatorFunc(req.GetName())
+	if err != nil {
+		return grpcError(err)
+	}
+	pair, err := pathPair(req.GetPair())
+	if err != nil {
+		return grpcError(err)
+	}
+	resolution, period := int(req.GetResolution()), int(req.GetPeriod())
+	if resolution <= 0 || period <= 0 || period > maxServerPeriod {
+		return status.Errorf(codes.InvalidArgument, "resolution must be positive and period from 1 to %d", maxServerPeriod)
+	}
+	interval := 30 * time.Second
+	if n := req.GetIntervalSeconds(); n < 0 {
+		return status.Error(codes.InvalidArgument, "interval_seconds must not be negative")
+	} else if n > 0 {
+		interval = time.Duration(n) * time.Second
+	}
+
+	ctx := stream.Context()
+	window := time.Duration(indicator.Warmup(period)*resolution) * time.Minute
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last, sent := 0.0, false
+	for {
+		now := s.now()
+		values, err := calc(ctx, pair, resolution, period, now.Add(-window), now)
+		if err != nil {
+			return grpcError(err)
+		}
+		if len(values) > 0 {
+			v := values[len(values)-1]
+			// NaN не равен сам себе, поэтому сравниваем и его отдельно
+			if !sent || (v != last && !(math.IsNaN(v) && math.IsNaN(last))) {
+				if err := stream.Send(&exmopb.IndicatorUpdate{Time: timestamppb.New(now), Value: v}); err != nil {
+					return err
+				}
+				last, sent = v, true
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-s.done:
+			return errShuttingDown
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *GRPCServer) indicatorFunc(name string) (func(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error), error) {
+	switch strings.ToLower(name) {
+	case "sma":
+		return s.indicator.SMAContext, nil
+	case "ema":
+		return s.indicator.EMAContext, nil
+	case "rsi":
+		return s.indicator.RSIContext, nil
+	}
+	return nil, badRequest("unknown indicator %q: want sma, ema or rsi", name)
+}
+
+// grpcRange проверяет интервал. Без to берется текущий момент, без from -
+// двое суток до to, как у REST API.
+func (s *GRPCServer) grpcRange(fromTS, toTS *timestamppb.Timestamp, resolution int) (time.Time, time.Time, error) {
+	if resolution <= 0 {
+		return time.Time{}, time.Time{}, badRequest("resolution must be positive")
+	}
+	to := s.now()
+	if toTS != nil {
+		to = toTS.AsTime()
+	}
+	from := to.Add(-48 * time.Hour)
+	if fromTS != nil {
+		from = fromTS.AsTime()
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, badRequest("from %s is not before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	if to.Sub(from) > time.Duration(maxServerCandles*resolution)*time.Minute {
+		return time.Time{}, time.Time{}, badRequest("interval spans more than %d candles", maxServerCandles)
+	}
+	return from, to, nil
+}
+
+func grpcPairs(raw []string) ([]string, error) {
+	pairs := make([]string, 0, len(raw))
+	for _, p := range raw {
+		pair, err := pathPair(p)
+		if err != nil {
+			return nil, err
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// grpcError переводит ошибку в статус gRPC по тем же правилам, что и REST API.
+func grpcError(err error) error {
+	var reqErr *requestError
+	switch {
+	case errors.As(err, &reqErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, exmo.ErrInvalidPair):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, exmo.ErrRateLimited):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+func tickerToProto(v exmo.TickerValue) *exmopb.TickerValue {
+	return &exmopb.TickerValue{
+		BuyPrice:  v.BuyPrice,
+		SellPrice: v.SellPrice,
+		LastTrade: v.LastTrade,
+		High:      v.High,
+		Low:       v.Low,
+		Avg:       v.Avg,
+		Vol:       v.Vol,
+		VolCurr:   v.VolCurr,
+		Updated:   v.Updated,
+	}
+}
+
+func tradesToProto(trades []exmo.Pair) []*exmopb.Trade {
+	out := make([]*exmopb.Trade, len(trades))
+	for i, t := range trades {
+		typ := exmopb.TradeType_TRADE_TYPE_UNSPECIFIED
+		switch t.Type {
+		case exmo.Buy:
+			typ = exmopb.TradeType_TRADE_TYPE_BUY
+		case exmo.Sell:
+			typ = exmopb.TradeType_TRADE_TYPE_SELL
+		}
+		out[i] = &exmopb.Trade{
+			TradeId:  t.TradeID,
+			Date:     t.Date,
+			Type:     typ,
+			Quantity: t.Quantity,
+			Price:    t.Price,
+			Amount:   t.Amount,
+		}
+	}
+	return out
+}
+
+func orderBookToProto(v exmo.OrderBookPair) *exmopb.OrderBookPair {
+	return &exmopb.OrderBookPair{
+		AskQuantity: v.AskQuantity,
+		AskAmount:   v.AskAmount,
+		AskTop:      v.AskTop,
+		BidQuantity: v.BidQuantity,
+		BidAmount:   v.BidAmount,
+		BidTop:      v.BidTop,
+		Ask:         levelsToProto(v.Ask),
+		Bid:         levelsToProto(v.Bid),
+	}
+}
+
+// levelsToProto переводит уровни [цена, количество, сумма] из ответа Exmo.
+func levelsToProto(raw [][]string) []*exmopb.OrderBookLevel {
+	out := make([]*exmopb.OrderBookLevel, len(raw))
+	for i, level := range raw {
+		l := &exmopb.OrderBookLevel{}
+		if len(level) > 0 {
+			l.Price = level[0]
+		}
+		if len(level) > 1 {
+			l.Quantity = level[1]
+		}
+		if len(level) > 2 {
+			l.Amount = level[2]
+		}
+		out[i] = l
+	}
+	return out
+}
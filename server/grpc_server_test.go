@@ -0,0 +1,237 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmopb"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+)
+
+func newGRPCClient(t *testing.T, srv *GRPCServer) exmopb.MarketDataClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	g := grpc.NewServer()
+	srv.Register(g)
+	go g.Serve(ln)
+	t.Cleanup(g.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return exmopb.NewMarketDataClient(conn)
+}
+
+func assertCode(t *testing.T, want codes.Code, err error) {
+	t.Helper()
+	require.Error(t, err)
+	assert.Equal(t, want, status.Code(err), err.Error())
+}
+
+func TestGRPC_Snapshots(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock)))
+	ctx := context.Background()
+
+	ticker := exmo.Ticker{"BTC_USD": {LastTrade: "100", Updated: 7}, "ETH_USD": {LastTrade: "10"}}
+	mock.EXPECT().GetTicker().Return(ticker, nil).Times(3)
+
+	resp, err := client.GetTicker(ctx, &exmopb.GetTickerRequest{})
+	require.NoError(t, err)
+	assert.Len(t, resp.Ticker, 2)
+
+	resp, err = client.GetTicker(ctx, &exmopb.GetTickerRequest{Pairs: []string{"btc_usd"}})
+	require.NoError(t, err)
+	require.Len(t, resp.Ticker, 1)
+	assert.Equal(t, "100", resp.Ticker["BTC_USD"].LastTrade)
+	assert.Equal(t, int64(7), resp.Ticker["BTC_USD"].Updated)
+
+	_, err = client.GetTicker(ctx, &exmopb.GetTickerRequest{Pairs: []string{"XRP_USD"}})
+	assertCode(t, codes.NotFound, err)
+	_, err = client.GetTicker(ctx, &exmopb.GetTickerRequest{Pairs: []string{"BTCUSD"}})
+	assertCode(t, codes.InvalidArgument, err)
+
+	mock.EXPECT().GetOrderBook(100, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {
+		AskTop: "101",
+		Ask:    [][]string{{"101", "0.5", "50.5"}},
+		Bid:    [][]string{{"99", "1"}},
+	}}, nil)
+	book, err := client.GetOrderBook(ctx, &exmopb.GetOrderBookRequest{Pair: "BTC_USD"})
+	require.NoError(t, err)
+	assert.Equal(t, "101", book.AskTop)
+	assert.Equal(t, "50.5", book.Ask[0].Amount)
+	assert.Equal(t, "1", book.Bid[0].Quantity)
+	assert.Empty(t, book.Bid[0].Amount)
+
+	for _, limit := range []int32{-1, 1001} {
+		_, err = client.GetOrderBook(ctx, &exmopb.GetOrderBookRequest{Pair: "BTC_USD", Limit: limit})
+		assertCode(t, codes.InvalidArgument, err)
+	}
+
+	mock.EXPECT().GetTrades("ETH_USD").Return(exmo.Trades{"ETH_USD": {{TradeID: 1, Type: exmo.Sell, Price: "10"}}}, nil)
+	trades, err := client.GetTrades(ctx, &exmopb.GetTradesRequest{Pairs: []string{"eth_usd"}})
+	require.NoError(t, err)
+	require.Len(t, trades.Trades["ETH_USD"].Trades, 1)
+	assert.Equal(t, exmopb.TradeType_TRADE_TYPE_SELL, trades.Trades["ETH_USD"].Trades[0].Type)
+
+	_, err = client.GetTrades(ctx, &exmopb.GetTradesRequest{})
+	assertCode(t, codes.InvalidArgument, err)
+	_, err = client.GetOrderBook(ctx, &exmopb.GetOrderBookRequest{Pair: "../../tmp/evil_x"})
+	assertCode(t, codes.InvalidArgument, err)
+}
+
+func TestGRPC_CandlesAndIndicators(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock), WithGRPCClock(func() time.Time { return now })))
+	ctx := context.Background()
+
+	mock.EXPECT().GetCandlesHistory("BTC_USD", 60, now.Add(-48*time.Hour), now).
+		Return(exmo.CandlesHistory{Candles: []exmo.Candle{{T: 1, C: 2.5}}}, nil)
+	candles, err := client.GetCandles(ctx, &exmopb.GetCandlesRequest{Pair: "BTC_USD", Resolution: 60})
+	require.NoError(t, err)
+	require.Len(t, candles.Candles, 1)
+	assert.Equal(t, 2.5, candles.Candles[0].C)
+
+	from := now.Add(-24 * time.Hour)
+	mock.EXPECT().GetClosePrice("BTC_USD", 60, from, now).Return([]float64{1, 2, 3, 4}, nil)
+	ind, err := client.GetIndicator(ctx, &exmopb.IndicatorRequest{
+		Name: "SMA", Pair: "btc_usd", Resolution: 60, Period: 2, From: timestamppb.New(from),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []float64{1.5, 2.5, 3.5}, ind.Values)
+
+	tests := []*exmopb.IndicatorRequest{
+		{Name: "macd", Pair: "BTC_USD", Resolution: 60, Period: 2},
+		{Name: "sma", Pair: "BTC_USD", Resolution: 0, Period: 2},
+		{Name: "sma", Pair: "BTC_USD", Resolution: 60, Period: 0},
+		{Name: "sma", Pair: "BTC_USD", Resolution: 60, Period: 2, From: timestamppb.New(now), To: timestamppb.New(from)},
+		{Name: "sma", Pair: "BTC_USD", Resolution: 1, Period: 2, From: timestamppb.New(now.Add(-30 * 24 * time.Hour))},
+	}
+	for _, req := range tests {
+		_, err := client.GetIndicator(ctx, req)
+		assertCode(t, codes.InvalidArgument, err)
+	}
+
+	mock.EXPECT().GetCandlesHistory("BTC_USD", 60, gomock.Any(), gomock.Any()).Return(exmo.CandlesHistory{}, exmo.ErrRateLimited)
+	_, err = client.GetCandles(ctx, &exmopb.GetCandlesRequest{Pair: "BTC_USD", Resolution: 60})
+	assertCode(t, codes.ResourceExhausted, err)
+
+	mock.EXPECT().GetCandlesHistory("BTC_USD", 60, gomock.Any(), gomock.Any()).Return(exmo.CandlesHistory{}, errors.New("boom"))
+	_, err = client.GetCandles(ctx, &exmopb.GetCandlesRequest{Pair: "BTC_USD", Resolution: 60})
+	assertCode(t, codes.Unavailable, err)
+}
+
+func TestGRPC_StreamTicker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"})
+	require.NoError(t, err)
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock), WithGRPCHub(hub)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamTicker(ctx, &exmopb.StreamRequest{Pairs: []string{"eth_usd"}})
+	require.NoError(t, err)
+	waitSubscribers(t, hub, 1)
+
+	expectPoll(mock, exmo.Ticker{"BTC_USD": {LastTrade: "100"}, "ETH_USD": {LastTrade: "10"}}, exmo.Trades{}, exmo.OrderBook{})
+	require.NoError(t, hub.Poll())
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, "ETH_USD", update.Pair)
+	assert.Equal(t, "10", update.Value.LastTrade)
+
+	cancel()
+	waitSubscribers(t, hub, 0)
+
+	trades, err := client.StreamTrades(context.Background(), &exmopb.StreamRequest{Pairs: []string{"XRP_USD"}})
+	require.NoError(t, err)
+	_, err = trades.Recv()
+	assertCode(t, codes.NotFound, err)
+}
+
+func TestGRPC_StreamShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	mock.EXPECT().GetClosePrice("BTC_USD", 1, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 3}, nil).AnyTimes()
+
+	serveCtx, stopServe := context.WithCancel(context.Background())
+	defer stopServe()
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock), WithGRPCContext(serveCtx)))
+
+	stream, err := client.StreamIndicator(context.Background(), &exmopb.StreamIndicatorRequest{
+		Name: "sma", Pair: "BTC_USD", Resolution: 1, Period: 2, IntervalSeconds: 60,
+	})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	require.NoError(t, err)
+
+	stopServe()
+	_, err = stream.Recv()
+	assertCode(t, codes.Unavailable, err)
+}
+
+func TestGRPC_StreamWithoutHub(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock)))
+
+	stream, err := client.StreamTicker(context.Background(), &exmopb.StreamRequest{})
+	require.NoError(t, err)
+	_, err = stream.Recv()
+	assertCode(t, codes.Unimplemented, err)
+}
+
+func TestGRPC_StreamIndicator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	client := newGRPCClient(t, NewGRPCServer(mock, indicator.NewIndicator(mock)))
+
+	gomock.InOrder(
+		mock.EXPECT().GetClosePrice("BTC_USD", 1, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 3}, nil),
+		mock.EXPECT().GetClosePrice("BTC_USD", 1, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 3}, nil),
+		mock.EXPECT().GetClosePrice("BTC_USD", 1, gomock.Any(), gomock.Any()).Return([]float64{1, 2, 5}, nil).MinTimes(1),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.StreamIndicator(ctx, &exmopb.StreamIndicatorRequest{
+		Name: "sma", Pair: "BTC_USD", Resolution: 1, Period: 2, IntervalSeconds: 1,
+	})
+	require.NoError(t, err)
+
+	update, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, update.Value)
+
+	// Второй пересчет дал то же значение и не отправляется
+	update, err = stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, 3.5, update.Value)
+
+	bad, err := client.StreamIndicator(context.Background(), &exmopb.StreamIndicatorRequest{Name: "sma", Pair: "BTC_USD"})
+	require.NoError(t, err)
+	_, err = bad.Recv()
+	assertCode(t, codes.InvalidArgument, err)
+}
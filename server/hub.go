@@ -0,0 +1,523 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/net/websocket"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+)
+
+// HubEventType - вид события хаба.
+type HubEventType string
+
+const (
+	HubTicker    HubEventType = "ticker"
+	HubTrades    HubEventType = "trades"
+	HubOrderBook HubEventType = "orderbook"
+)
+
+var hubEventTypes = []HubEventType{HubTicker, HubTrades, HubOrderBook}
+
+// HubEvent - обновление по одной паре. Data - TickerValue, []Pair с новыми
+// сделками по возрастанию trade_id или OrderBookPair.
+type HubEvent struct {
+	Seq  uint64       `json:"seq"`
+	Type HubEventType `json:"type"`
+	Pair string       `json:"pair"`
+	Time time.Time    `json:"time"`
+	Data interface{}  `json:"data"`
+}
+
+// HubFilter отбирает события подписчика. Пустые поля - без ограничений.
+type HubFilter struct {
+	Pairs []string
+	Types []HubEventType
+}
+
+func (f HubFilter) match(ev HubEvent) bool {
+	return (len(f.Pairs) == 0 || contains(f.Pairs, ev.Pair)) &&
+		(len(f.Types) == 0 || contains(f.Types, ev.Type))
+}
+
+func contains[T comparable](list []T, v T) bool {
+	for _, x := range list {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Hub опрашивает один Exchanger и рассылает изменения подписчикам. За
+// цикл опроса делается ровно три запроса (тикер, сделки и стаканы всех
+// пар), сколько бы подписчиков ни было; без подписчиков опрос не идет.
+//
+// Хаб никогда не ждет подписчиков: если буфер медленного клиента
+// заполнен, самое старое событие выбрасывается и учитывается в Dropped.
+type Hub struct {
+	exchange exmo.Exchanger
+	pairs    []string
+	interval time.Duration
+	depth    int
+	buffer   int
+	origins  []string
+	now      func() time.Time
+
+	mu        sync.Mutex
+	subs      map[*Subscription]struct{}
+	snapshots map[string]HubEvent // последний тикер и стакан по паре
+	lastTrade map[string]int64
+	seq       uint64
+	wake      chan struct{}
+}
+
+type HubOption func(*Hub)
+
+func WithHubInterval(d time.Duration) HubOption {
+	return func(h *Hub) {
+		h.interval = d
+	}
+}
+
+// WithHubDepth задает глубину стакана в событиях orderbook.
+func WithHubDepth(n int) HubOption {
+	return func(h *Hub) {
+		h.depth = n
+	}
+}
+
+// WithHubBuffer задает размер буфера подписчика.
+func WithHubBuffer(n int) HubOption {
+	return func(h *Hub) {
+		h.buffer = n
+	}
+}
+
+// WithHubAllowedOrigins разрешает WebSocket-подключения со страниц других
+// сайтов, например "https://dashboard.example.com". "*" разрешает любые.
+// По умолчанию принимаются только страницы того же хоста, иначе чужой сайт
+// мог бы открыть соединение из браузера пользователя.
+func WithHubAllowedOrigins(origins ...string) HubOption {
+	return func(h *Hub) {
+		h.origins = origins
+	}
+}
+
+func WithHubClock(now func() time.Time) HubOption {
+	return func(h *Hub) {
+		h.now = now
+	}
+}
+
+func NewHub(exchange exmo.Exchanger, pairs []string, opts ...HubOption) (*Hub, error) {
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("hub: at least one pair is required")
+	}
+	h := &Hub{
+		exchange:  exchange,
+		pairs:     pairs,
+		interval:  2 * time.Second,
+		depth:     20,
+		buffer:    64,
+		now:       time.Now,
+		subs:      make(map[*Subscription]struct{}),
+		snapshots: make(map[string]HubEvent),
+		lastTrade: make(map[string]int64),
+		wake:      make(chan struct{}, 1),
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.interval <= 0 || h.depth <= 0 || h.buffer <= 0 {
+		return nil, fmt.Errorf("hub: interval, depth and buffer must be positive")
+	}
+	return h, nil
+}
+
+func (h *Hub) Pairs() []string {
+	return append([]string(nil), h.pairs...)
+}
+
+// Subscription - подписка на события. C закрывается после Close.
+type Subscription struct {
+	C       <-chan HubEvent
+	ch      chan HubEvent
+	hub     *Hub
+	filter  HubFilter
+	dropped atomic.Uint64
+	once    sync.Once
+}
+
+// Subscribe регистрирует подписчика и сразу кладет в его буфер последние
+// снимки тикера и стаканов, подходящие под фильтр.
+func (h *Hub) Subscribe(filter HubFilter) (*Subscription, error) {
+	for _, p := range filter.Pairs {
+		if !contains(h.pairs, p) {
+			return nil, fmt.Errorf("%w: %s is not streamed", exmo.ErrInvalidPair, p)
+		}
+	}
+	for _, t := range filter.Types {
+		if !contains(hubEventTypes, t) {
+			return nil, fmt.Errorf("unknown event type %q: want ticker, trades or orderbook", t)
+		}
+	}
+
+	ch := make(chan HubEvent, h.buffer)
+	sub := &Subscription{C: ch, ch: ch, hub: h, filter: filter}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	keys := make([]string, 0, len(h.snapshots))
+	for k := range h.snapshots {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if ev := h.snapshots[k]; filter.match(ev) {
+			sub.send(ev)
+		}
+	}
+	h.subs[sub] = struct{}{}
+	if len(h.subs) == 1 {
+		select {
+		case h.wake <- struct{}{}:
+		default:
+		}
+	}
+	return sub, nil
+}
+
+// Subscribers возвращает число активных подписчиков.
+func (h *Hub) Subscribers() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		s.hub.mu.Lock()
+		delete(s.hub.subs, s)
+		close(s.ch)
+		s.hub.mu.Unlock()
+	})
+}
+
+// Dropped - сколько событий выброшено из-за переполнения буфера.
+func (s *Subscription) Dropped() uint64 {
+	return s.dropped.Load()
+}
+
+// send не блокируется: при полном буфере вытесняет самое старое событие.
+// Вызывается под h.mu.
+func (s *Subscription) send(ev HubEvent) {
+	for {
+		select {
+		case s.ch <- ev:
+			return
+		default:
+		}
+		select {
+		case <-s.ch:
+			s.dropped.Add(1)
+		default:
+		}
+	}
+}
+
+// Poll выполняет один цикл опроса и рассылает изменившиеся данные.
+// Ошибки запросов объединяются, остальные данные все равно рассылаются.
+func (h *Hub) Poll() error {
+	var errs []string
+	now := h.now()
+
+	if ticker, err := h.exchange.GetTicker(); err != nil {
+		errs = append(errs, "ticker: "+err.Error())
+	} else {
+		for _, p := range h.pairs {
+			if v, ok := ticker[p]; ok {
+				h.publishSnapshot(HubEvent{Type: HubTicker, Pair: p, Time: now, Data: v})
+			}
+		}
+	}
+
+	if trades, err := h.exchange.GetTrades(h.pairs...); err != nil {
+		errs = append(errs, "trades: "+err.Error())
+	} else {
+		for _, p := range h.pairs {
+			h.publishTrades(p, trades[p], now)
+		}
+	}
+
+	if book, err := h.exchange.GetOrderBook(h.depth, h.pairs...); err != nil {
+		errs = append(errs, "orderbook: "+err.Error())
+	} else {
+		for _, p := range h.pairs {
+			if v, ok := book[p]; ok {
+				h.publishSnapshot(HubEvent{Type: HubOrderBook, Pair: p, Time: now, Data: v})
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("hub poll: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// publishSnapshot рассылает снимок, только если он отличается от прошлого.
+func (h *Hub) publishSnapshot(ev HubEvent) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	key := string(ev.Type) + "/" + ev.Pair
+	if prev, ok := h.snapshots[key]; ok && reflect.DeepEqual(prev.Data, ev.Data) {
+		return
+	}
+	h.publishLocked(&ev)
+	h.snapshots[key] = ev
+}
+
+// publishTrades рассылает сделки новее уже виденных. Первый опрос только
+// запоминает последнюю сделку, чтобы не выдавать историю за новые.
+func (h *Hub) publishTrades(pair string, trades []exmo.Pair, now time.Time) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	last, seen := h.lastTrade[pair]
+	var fresh []exmo.Pair
+	for _, t := range trades {
+		if t.TradeID > last {
+			fresh = append(fresh, t)
+		}
+	}
+	// Пока не видно ни одной сделки, пара остается непросмотренной: иначе
+	// следующий опрос разослал бы всю историю как новые сделки
+	if len(fresh) == 0 {
+		return
+	}
+	sort.Slice(fresh, func(i, j int) bool { return fresh[i].TradeID < fresh[j].TradeID })
+	h.lastTrade[pair] = fresh[len(fresh)-1].TradeID
+	if seen {
+		h.publishLocked(&HubEvent{Type: HubTrades, Pair: pair, Time: now, Data: fresh})
+	}
+}
+
+func (h *Hub) publishLocked(ev *HubEvent) {
+	h.seq++
+	ev.Seq = h.seq
+	for sub := range h.subs {
+		if sub.filter.match(*ev) {
+			sub.send(*ev)
+		}
+	}
+}
+
+// Run опрашивает биржу каждые interval, пока есть подписчики, до отмены ctx.
+// При выходе подписки закрываются, и потоковые обработчики завершаются.
+func (h *Hub) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	defer h.closeAll()
+	for {
+		if h.Subscribers() == 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.wake:
+			}
+		}
+		if err := h.Poll(); err != nil && onError != nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Hub) closeAll() {
+	h.mu.Lock()
+	subs := make([]*Subscription, 0, len(h.subs))
+	for sub := range h.subs {
+		subs = append(subs, sub)
+	}
+	h.mu.Unlock()
+	for _, sub := range subs {
+		sub.Close()
+	}
+}
+
+// parseHubFilter читает фильтр из параметров pair и type.
+func parseHubFilter(r *http.Request) (HubFilter, error) {
+	var f HubFilter
+	q := r.URL.Query()
+	for _, p := range strings.Split(q.Get("pair"), ",") {
+		if p = strings.TrimSpace(p); p == "" {
+			continue
+		}
+		pair, err := pathPair(p)
+		if err != nil {
+			return f, err
+		}
+		f.Pairs = append(f.Pairs, pair)
+	}
+	for _, t := range strings.Split(q.Get("type"), ",") {
+		if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+			f.Types = append(f.Types, HubEventType(t))
+		}
+	}
+	return f, nil
+}
+
+func (h *Hub) subscribeRequest(r *http.Request) (*Subscription, error) {
+	filter, err := parseHubFilter(r)
+	if err != nil {
+		return nil, err
+	}
+	sub, err := h.Subscribe(filter)
+	if err != nil && !errors.Is(err, exmo.ErrInvalidPair) {
+		return nil, badRequest("%v", err)
+	}
+	return sub, err
+}
+
+// sseHeartbeat - период комментариев, которые держат соединение открытым.
+var sseHeartbeat = 15 * time.Second
+
+// ServeSSE отдает события как Server-Sent Events:
+//
+//	GET /v1/stream?pair=BTC_USD&type=ticker,trades
+func (h *Hub) ServeSSE(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeAPIError(w, http.StatusInternalServerError, "internal", "streaming is not supported")
+		return
+	}
+	sub, err := h.subscribeRequest(r)
+	if err != nil {
+		writeServiceError(w, err)
+		return
+	}
+	defer sub.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(sseHeartbeat)
+	defer heartbeat.Stop()
+	var reported uint64
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		case ev, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			// Клиент узнает, что часть событий пропущена
+			if d := sub.Dropped(); d > reported {
+				fmt.Fprintf(w, "event: dropped\ndata: %d\n\n", d-reported)
+				reported = d
+			}
+			data, err := json.Marshal(ev)
+			if err != nil {
+				return
+			}
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", ev.Seq, ev.Type, data); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// originAllowed проверяет Origin подключения. Клиенты не из браузера
+// заголовок не передают, их не от чего защищать.
+func (h *Hub) originAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range h.origins {
+		if allowed == "*" || strings.EqualFold(strings.TrimSuffix(strings.TrimSpace(allowed), "/"), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// WebSocketHandler отдает те же события по WebSocket, по одному JSON
+// сообщению на событие. Фильтр задается параметрами URL, как у ServeSSE.
+func (h *Hub) WebSocketHandler() http.Handler {
+	ws := websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			if !h.originAllowed(r) {
+				return fmt.Errorf("origin %q is not allowed", r.Header.Get("Origin"))
+			}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			defer conn.Close()
+			sub, err := h.subscribeRequest(conn.Request())
+			if err != nil {
+				websocket.JSON.Send(conn, map[string]string{"error": err.Error()})
+				return
+			}
+			defer sub.Close()
+
+			// Чтение нужно, чтобы заметить закрытие соединения клиентом
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(conn, &discard) == nil {
+				}
+			}()
+			for {
+				select {
+				case <-closed:
+					return
+				case ev, ok := <-sub.C:
+					if !ok {
+						return
+					}
+					if err := websocket.JSON.Send(conn, ev); err != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+			writeAPIError(w, http.StatusBadRequest, "invalid_argument", "websocket upgrade required")
+			return
+		}
+		ws.ServeHTTP(w, r)
+	})
+}
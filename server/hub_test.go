@@ -0,0 +1,270 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+)
+
+func expectPoll(mock *exmomock.MockExchanger, ticker exmo.Ticker, trades exmo.Trades, book exmo.OrderBook) {
+	mock.EXPECT().GetTicker().Return(ticker, nil)
+	mock.EXPECT().GetTrades("BTC_USD", "ETH_USD").Return(trades, nil)
+	mock.EXPECT().GetOrderBook(20, "BTC_USD", "ETH_USD").Return(book, nil)
+}
+
+func drain(sub *Subscription) []HubEvent {
+	var events []HubEvent
+	for {
+		select {
+		case ev := <-sub.C:
+			events = append(events, ev)
+		default:
+			return events
+		}
+	}
+}
+
+func TestHub_Poll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"})
+	require.NoError(t, err)
+
+	all, err := hub.Subscribe(HubFilter{})
+	require.NoError(t, err)
+	btcTrades, err := hub.Subscribe(HubFilter{Pairs: []string{"BTC_USD"}, Types: []HubEventType{HubTrades}})
+	require.NoError(t, err)
+
+	ticker := exmo.Ticker{"BTC_USD": {LastTrade: "100"}, "ETH_USD": {LastTrade: "10"}}
+	book := exmo.OrderBook{"BTC_USD": {AskTop: "101"}}
+	expectPoll(mock, ticker, exmo.Trades{"BTC_USD": {{TradeID: 5}}}, book)
+	require.NoError(t, hub.Poll())
+
+	events := drain(all)
+	require.Len(t, events, 3, "история сделок при первом опросе не рассылается")
+	assert.Equal(t, HubTicker, events[0].Type)
+	assert.Equal(t, uint64(1), events[0].Seq)
+	assert.Equal(t, HubOrderBook, events[2].Type)
+	assert.Empty(t, drain(btcTrades))
+
+	// Изменился только тикер ETH и появились новые сделки BTC
+	ticker2 := exmo.Ticker{"BTC_USD": {LastTrade: "100"}, "ETH_USD": {LastTrade: "11"}}
+	expectPoll(mock, ticker2, exmo.Trades{"BTC_USD": {{TradeID: 7}, {TradeID: 6}, {TradeID: 5}}}, book)
+	require.NoError(t, hub.Poll())
+
+	events = drain(all)
+	require.Len(t, events, 2)
+	assert.Equal(t, "ETH_USD", events[0].Pair)
+	trades := drain(btcTrades)
+	require.Len(t, trades, 1)
+	assert.Equal(t, []exmo.Pair{{TradeID: 6}, {TradeID: 7}}, trades[0].Data)
+
+	// Новый подписчик сразу получает последние снимки
+	late, err := hub.Subscribe(HubFilter{Types: []HubEventType{HubTicker}})
+	require.NoError(t, err)
+	assert.Len(t, drain(late), 2)
+
+	_, err = hub.Subscribe(HubFilter{Pairs: []string{"XRP_USD"}})
+	assert.ErrorIs(t, err, exmo.ErrInvalidPair)
+	_, err = hub.Subscribe(HubFilter{Types: []HubEventType{"candles"}})
+	assert.Error(t, err)
+
+	all.Close()
+	all.Close()
+	_, open := <-all.C
+	assert.False(t, open)
+	assert.Equal(t, 2, hub.Subscribers())
+}
+
+func TestHub_TradesAfterEmptyPoll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"})
+	require.NoError(t, err)
+	sub, err := hub.Subscribe(HubFilter{Types: []HubEventType{HubTrades}})
+	require.NoError(t, err)
+
+	// Первый опрос без сделок не делает пару просмотренной
+	expectPoll(mock, exmo.Ticker{}, exmo.Trades{}, exmo.OrderBook{})
+	require.NoError(t, hub.Poll())
+	expectPoll(mock, exmo.Ticker{}, exmo.Trades{"BTC_USD": {{TradeID: 2}, {TradeID: 1}}}, exmo.OrderBook{})
+	require.NoError(t, hub.Poll())
+	assert.Empty(t, drain(sub), "история сделок не рассылается")
+
+	expectPoll(mock, exmo.Ticker{}, exmo.Trades{"BTC_USD": {{TradeID: 3}, {TradeID: 2}}}, exmo.OrderBook{})
+	require.NoError(t, hub.Poll())
+	events := drain(sub)
+	require.Len(t, events, 1)
+	assert.Equal(t, []exmo.Pair{{TradeID: 3}}, events[0].Data)
+}
+
+func TestHub_ConstantRequestsAndBackpressure(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"}, WithHubBuffer(2))
+	require.NoError(t, err)
+
+	var subs []*Subscription
+	for i := 0; i < 50; i++ {
+		sub, err := hub.Subscribe(HubFilter{Types: []HubEventType{HubTicker}})
+		require.NoError(t, err)
+		subs = append(subs, sub)
+	}
+
+	// Пять опросов - пятнадцать запросов, независимо от числа подписчиков
+	for i := 0; i < 5; i++ {
+		expectPoll(mock, exmo.Ticker{"BTC_USD": {Updated: int64(i)}}, exmo.Trades{}, exmo.OrderBook{})
+		require.NoError(t, hub.Poll())
+	}
+	for _, sub := range subs {
+		events := drain(sub)
+		require.Len(t, events, 2)
+		assert.Equal(t, int64(3), events[0].Data.(exmo.TickerValue).Updated)
+		assert.Equal(t, int64(4), events[1].Data.(exmo.TickerValue).Updated)
+		assert.Equal(t, uint64(3), sub.Dropped())
+	}
+}
+
+func newHubServer(t *testing.T) (*Hub, *exmomock.MockExchanger, *httptest.Server) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"})
+	require.NoError(t, err)
+	ts := httptest.NewServer(NewServer(mock, indicator.NewIndicator(mock), WithServerHub(hub)))
+	t.Cleanup(ts.Close)
+	return hub, mock, ts
+}
+
+func waitSubscribers(t *testing.T, hub *Hub, n int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return hub.Subscribers() == n }, time.Second, 5*time.Millisecond)
+}
+
+func TestHub_SSE(t *testing.T) {
+	hub, mock, ts := newHubServer(t)
+
+	resp, err := http.Get(ts.URL + "/v1/stream?pair=eth_usd&type=ticker")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	waitSubscribers(t, hub, 1)
+
+	expectPoll(mock, exmo.Ticker{"BTC_USD": {LastTrade: "1"}, "ETH_USD": {LastTrade: "2"}}, exmo.Trades{}, exmo.OrderBook{})
+	require.NoError(t, hub.Poll())
+
+	r := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, strings.TrimSuffix(line, "\n"))
+	}
+	assert.Equal(t, "id: 2", lines[0])
+	assert.Equal(t, "event: ticker", lines[1])
+	var ev HubEvent
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &ev))
+	assert.Equal(t, "ETH_USD", ev.Pair)
+
+	for target, status := range map[string]int{
+		"/v1/stream?pair=XRP_USD": http.StatusNotFound,
+		"/v1/stream?pair=XRPUSD":  http.StatusBadRequest,
+		"/v1/stream?type=candles": http.StatusBadRequest,
+		"/v1/ws":                  http.StatusBadRequest,
+	} {
+		resp, err := http.Get(ts.URL + target)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, status, resp.StatusCode, target)
+	}
+}
+
+func TestHub_WebSocket(t *testing.T) {
+	hub, mock, ts := newHubServer(t)
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/v1/ws?pair=BTC_USD&type=orderbook"
+	conn, err := websocket.Dial(url, "", ts.URL)
+	require.NoError(t, err)
+	waitSubscribers(t, hub, 1)
+
+	expectPoll(mock, exmo.Ticker{}, exmo.Trades{}, exmo.OrderBook{"BTC_USD": {BidTop: "99"}, "ETH_USD": {BidTop: "9"}})
+	require.NoError(t, hub.Poll())
+
+	var ev struct {
+		Type string             `json:"type"`
+		Pair string             `json:"pair"`
+		Data exmo.OrderBookPair `json:"data"`
+	}
+	require.NoError(t, websocket.JSON.Receive(conn, &ev))
+	assert.Equal(t, "orderbook", ev.Type)
+	assert.Equal(t, "99", ev.Data.BidTop)
+
+	conn.Close()
+	waitSubscribers(t, hub, 0)
+}
+
+func TestHub_WebSocketOrigin(t *testing.T) {
+	_, _, ts := newHubServer(t)
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/v1/ws?pair=BTC_USD"
+
+	_, err := websocket.Dial(url, "", "https://evil.example.com")
+	assert.Error(t, err, "чужой сайт не подключается")
+
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD"}, WithHubAllowedOrigins("https://dashboard.example.com/"))
+	require.NoError(t, err)
+	allowed := httptest.NewServer(hub.WebSocketHandler())
+	t.Cleanup(allowed.Close)
+	url = "ws" + strings.TrimPrefix(allowed.URL, "http") + "/?pair=BTC_USD"
+
+	conn, err := websocket.Dial(url, "", "https://dashboard.example.com")
+	require.NoError(t, err)
+	conn.Close()
+	_, err = websocket.Dial(url, "", "https://evil.example.com")
+	assert.Error(t, err)
+}
+
+func TestHub_RunStopsWithoutSubscribers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	hub, err := NewHub(mock, []string{"BTC_USD", "ETH_USD"}, WithHubInterval(time.Hour))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		hub.Run(ctx, func(err error) { t.Error(err) })
+		close(done)
+	}()
+
+	// Без подписчиков запросов нет, первый подписчик запускает опрос
+	polled := make(chan struct{})
+	mock.EXPECT().GetTicker().Return(exmo.Ticker{}, nil)
+	mock.EXPECT().GetTrades("BTC_USD", "ETH_USD").Return(exmo.Trades{}, nil)
+	mock.EXPECT().GetOrderBook(20, "BTC_USD", "ETH_USD").DoAndReturn(func(int, ...string) (exmo.OrderBook, error) {
+		close(polled)
+		return exmo.OrderBook{}, nil
+	})
+	sub, err := hub.Subscribe(HubFilter{})
+	require.NoError(t, err)
+	<-polled
+
+	cancel()
+	<-done
+	_, open := <-sub.C
+	assert.False(t, open, "подписки закрываются при остановке хаба")
+}
@@ -0,0 +1,424 @@
+// Package server раздает рыночные данные и индикаторы по HTTP (Server) и
+// gRPC (GRPCServer). Hub опрашивает биржу и рассылает подписчикам события
+// тикера и сделок через SSE, WebSocket и потоковые методы gRPC.
+package server
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/internal/timearg"
+)
+
+// Ограничения параметров запросов к серверу.
+const (
+	maxServerLimit   = 1000
+	maxServerPeriod  = 1000
+	maxServerCandles = 10000
+)
+
+// Server отдает рыночные данные и индикаторы в JSON:
+//
+//	GET /v1/ticker?pair=BTC_USD,ETH_USD
+//	GET /v1/orderbook/{pair}?limit=100
+//	GET /v1/trades/{pair}
+//	GET /v1/indicators/{name}/{pair}?resolution=30&period=14&from=-2d&to=now
+//	GET /v1/stream?pair=BTC_USD&type=ticker  (SSE, с WithServerHub)
+//	GET /v1/ws?pair=BTC_USD&type=trades      (WebSocket, с WithServerHub)
+//
+// Ошибки возвращаются как {"error":{"status":400,"code":"...","message":"..."}}.
+// Успешные ответы содержат Cache-Control по CacheTTLs и ETag.
+type Server struct {
+	exchange  exmo.Exchanger
+	indicator indicator.Indicatorer
+	ttls      exmo.CacheTTLs
+	now       func() time.Time
+	hub       *Hub
+	mux       *http.ServeMux
+}
+
+type ServerOption func(*Server)
+
+// WithServerCacheTTLs задает max-age ответов, по умолчанию DefaultCacheTTLs.
+func WithServerCacheTTLs(ttls exmo.CacheTTLs) ServerOption {
+	return func(s *Server) {
+		s.ttls = ttls
+	}
+}
+
+// WithServerHub подключает потоковые маршруты с событиями хаба.
+func WithServerHub(h *Hub) ServerOption {
+	return func(s *Server) {
+		s.hub = h
+	}
+}
+
+func WithServerClock(now func() time.Time) ServerOption {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+func NewServer(exchange exmo.Exchanger, ind indicator.Indicatorer, opts ...ServerOption) *Server {
+	s := &Server{
+		exchange:  exchange,
+		indicator: ind,
+		ttls:      exmo.DefaultCacheTTLs,
+		now:       time.Now,
+		mux:       http.NewServeMux(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	s.mux.HandleFunc("GET /v1/ticker", s.handleTicker)
+	s.mux.HandleFunc("GET /v1/orderbook/{pair}", s.handleOrderBook)
+	s.mux.HandleFunc("GET /v1/trades/{pair}", s.handleTrades)
+	s.mux.HandleFunc("GET /v1/indicators/{name}/{pair}", s.handleIndicator)
+	if s.hub != nil {
+		s.mux.HandleFunc("GET /v1/stream", s.hub.ServeSSE)
+		s.mux.Handle("GET /v1/ws", s.hub.WebSocketHandler())
+	}
+	s.mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		s.writeJSON(w, r, 0, map[string]string{"status": "ok"})
+	})
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, "not_found", fmt.Sprintf("no route for %s %s", r.Method, r.URL.Path))
+	})
+	return s
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// Serve обслуживает ln до отмены ctx, затем дожидается текущих запросов
+// не дольше shutdownTimeout.
+func (s *Server) Serve(ctx context.Context, ln net.Listener, shutdownTimeout time.Duration) error {
+	srv := &http.Server{
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return context.WithoutCancel(ctx) },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ListenAndServe слушает addr и вызывает Serve.
+func (s *Server) ListenAndServe(ctx context.Context, addr string, shutdownTimeout time.Duration) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln, shutdownTimeout)
+}
+
+// requestError - ошибка в параметрах запроса, ответ 400.
+type requestError struct {
+	msg string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &requestError{msg: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) handleTicker(w http.ResponseWriter, r *http.Request) {
+	var pairs []string
+	if v := r.URL.Query().Get("pair"); v != "" {
+		for _, p := range strings.Split(v, ",") {
+			pair, err := pathPair(p)
+			if err != nil {
+				s.writeError(w, err)
+				return
+			}
+			pairs = append(pairs, pair)
+		}
+	}
+
+	ticker, err := exmo.BindContext(r.Context(), s.exchange).GetTicker()
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	if len(pairs) > 0 {
+		filtered := make(exmo.Ticker, len(pairs))
+		for _, p := range pairs {
+			v, ok := ticker[p]
+			if !ok {
+				s.writeError(w, fmt.Errorf("%w: %s", exmo.ErrInvalidPair, p))
+				return
+			}
+			filtered[p] = v
+		}
+		ticker = filtered
+	}
+	s.writeJSON(w, r, s.ttls.Ticker, ticker)
+}
+
+func (s *Server) handleOrderBook(w http.ResponseWriter, r *http.Request) {
+	pair, err := pathPair(r.PathValue("pair"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	limit, err := intParam(r, "limit", 100, 1, maxServerLimit)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	book, err := exmo.BindContext(r.Context(), s.exchange).GetOrderBook(limit, pair)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	v, ok := book[pair]
+	if !ok {
+		s.writeError(w, fmt.Errorf("%w: %s", exmo.ErrInvalidPair, pair))
+		return
+	}
+	s.writeJSON(w, r, s.ttls.OrderBook, v)
+}
+
+func (s *Server) handleTrades(w http.ResponseWriter, r *http.Request) {
+	pair, err := pathPair(r.PathValue("pair"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	trades, err := exmo.BindContext(r.Context(), s.exchange).GetTrades(pair)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	v, ok := trades[pair]
+	if !ok {
+		s.writeError(w, fmt.Errorf("%w: %s", exmo.ErrInvalidPair, pair))
+		return
+	}
+	s.writeJSON(w, r, s.ttls.Trades, v)
+}
+
+type indicatorResponse struct {
+	Indicator  string        `json:"indicator"`
+	Pair       string        `json:"pair"`
+	Resolution int           `json:"resolution"`
+	Period     int           `json:"period"`
+	From       time.Time     `json:"from"`
+	To         time.Time     `json:"to"`
+	Values     []interface{} `json:"values"`
+}
+
+func (s *Server) handleIndicator(w http.ResponseWriter, r *http.Request) {
+	name := strings.ToLower(r.PathValue("name"))
+	var calc func(ctx context.Context, pair string, resolution, period int, from, to time.Time) ([]float64, error)
+	switch name {
+	case "sma":
+		calc = s.indicator.SMAContext
+	case "ema":
+		calc = s.indicator.EMAContext
+	case "rsi":
+		calc = s.indicator.RSIContext
+	default:
+		writeAPIError(w, http.StatusNotFound, "unknown_indicator", fmt.Sprintf("unknown indicator %q: want sma, ema or rsi", name))
+		return
+	}
+
+	pair, err := pathPair(r.PathValue("pair"))
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	resolution := 30
+	if v := r.URL.Query().Get("resolution"); v != "" {
+		if resolution, err = timearg.ParseResolution(v); err != nil {
+			s.writeError(w, badRequest("resolution: %v", err))
+			return
+		}
+	}
+	period, err := intParam(r, "period", 14, 1, maxServerPeriod)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	from, to, err := s.timeParams(r, resolution)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	values, err := calc(r.Context(), pair, resolution, period, from, to)
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+	resp := indicatorResponse{
+		Indicator:  name,
+		Pair:       pair,
+		Resolution: resolution,
+		Period:     period,
+		From:       from.UTC(),
+		To:         to.UTC(),
+		Values:     make([]interface{}, len(values)),
+	}
+	for i, v := range values {
+		resp.Values[i] = jsonValue(v)
+	}
+	s.writeJSON(w, r, s.ttls.Candles, resp)
+}
+
+// timeParams разбирает from и to в тех же форматах, что и флаги CLI (см. timearg.Parse).
+func (s *Server) timeParams(r *http.Request, resolution int) (time.Time, time.Time, error) {
+	now := s.now()
+	q := r.URL.Query()
+	fromArg, toArg := q.Get("from"), q.Get("to")
+	if fromArg == "" {
+		fromArg = "-2d"
+	}
+	from, err := timearg.Parse(fromArg, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, badRequest("from: %v", err)
+	}
+	to, err := timearg.Parse(toArg, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, badRequest("to: %v", err)
+	}
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, badRequest("from %s is not before to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	if to.Sub(from) > time.Duration(maxServerCandles*resolution)*time.Minute {
+		return time.Time{}, time.Time{}, badRequest("interval spans more than %d candles", maxServerCandles)
+	}
+	return from, to, nil
+}
+
+// pairPattern - допустимая пара. Пара из запроса доходит до хранилища
+// свечей и становится именем каталога, поэтому кроме букв и цифр ничего нет.
+var pairPattern = regexp.MustCompile(`^[A-Z0-9]+_[A-Z0-9]+$`)
+
+func pathPair(s string) (string, error) {
+	pair := strings.ToUpper(strings.TrimSpace(s))
+	if !pairPattern.MatchString(pair) {
+		return "", badRequest("invalid pair %q, want BASE_QUOTE", s)
+	}
+	return pair, nil
+}
+
+func intParam(r *http.Request, name string, def, min, max int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < min || n > max {
+		return 0, badRequest("%s must be an integer from %d to %d, got %q", name, min, max, v)
+	}
+	return n, nil
+}
+
+// writeJSON пишет ответ с Cache-Control и ETag. Совпавший If-None-Match
+// дает 304 без тела.
+func (s *Server) writeJSON(w http.ResponseWriter, r *http.Request, maxAge time.Duration, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeAPIError(w, http.StatusInternalServerError, "internal", err.Error())
+		return
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:8]) + `"`
+
+	h := w.Header()
+	h.Set("ETag", etag)
+	if maxAge > 0 {
+		h.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(maxAge.Seconds())))
+	} else {
+		h.Set("Cache-Control", "no-cache")
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && strings.Contains(match, etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.Set("Content-Type", "application/json")
+	w.Write(body)
+}
+
+func (s *Server) writeError(w http.ResponseWriter, err error) {
+	writeServiceError(w, err)
+}
+
+// writeServiceError переводит ошибку в HTTP-статус и код ошибки API.
+func writeServiceError(w http.ResponseWriter, err error) {
+	var reqErr *requestError
+	switch {
+	case errors.As(err, &reqErr):
+		writeAPIError(w, http.StatusBadRequest, "invalid_argument", err.Error())
+	case errors.Is(err, exmo.ErrInvalidPair):
+		writeAPIError(w, http.StatusNotFound, "unknown_pair", err.Error())
+	case errors.Is(err, exmo.ErrRateLimited):
+		w.Header().Set("Retry-After", "1")
+		writeAPIError(w, http.StatusTooManyRequests, "rate_limited", err.Error())
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		writeAPIError(w, http.StatusGatewayTimeout, "timeout", err.Error())
+	default:
+		writeAPIError(w, http.StatusBadGateway, "upstream_error", err.Error())
+	}
+}
+
+func writeAPIError(w http.ResponseWriter, status int, code, message string) {
+	var buf bytes.Buffer
+	json.NewEncoder(&buf).Encode(map[string]interface{}{
+		"error": map[string]interface{}{
+			"status":  status,
+			"code":    code,
+			"message": message,
+		},
+	})
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// jsonValue готовит значение индикатора к JSON: NaN и бесконечность не
+// представимы в JSON и становятся null.
+func jsonValue(v float64) interface{} {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+}
@@ -0,0 +1,195 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/exmo/exmomock"
+	"github.com/Bobakek/GOLANG/go-kata/course2/2.oop/5.oop_mock/task2.2.5.1/indicator"
+)
+
+func serverGet(t *testing.T, h http.Handler, target string, header ...string) (*httptest.ResponseRecorder, map[string]interface{}) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	return rec, body
+}
+
+func errorCode(body map[string]interface{}) interface{} {
+	e, _ := body["error"].(map[string]interface{})
+	return e["code"]
+}
+
+func TestServer_Ticker(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	ticker := exmo.Ticker{"BTC_USD": {LastTrade: "100"}, "ETH_USD": {LastTrade: "10"}}
+	mock.EXPECT().GetTicker().Return(ticker, nil).Times(3)
+	srv := NewServer(mock, indicator.NewIndicator(mock))
+
+	rec, body := serverGet(t, srv, "/v1/ticker")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, body, 2)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "public, max-age=5", rec.Header().Get("Cache-Control"))
+
+	rec, body = serverGet(t, srv, "/v1/ticker?pair=btc_usd")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "100", body["BTC_USD"].(map[string]interface{})["last_trade"])
+	assert.Len(t, body, 1)
+
+	rec, body = serverGet(t, srv, "/v1/ticker?pair=XRP_USD")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, "unknown_pair", errorCode(body))
+
+	rec, body = serverGet(t, srv, "/v1/ticker?pair=BTCUSD")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "invalid_argument", errorCode(body))
+}
+
+func TestServer_OrderBookAndTrades(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	mock.EXPECT().GetOrderBook(5, "BTC_USD").Return(exmo.OrderBook{"BTC_USD": {AskTop: "101", BidTop: "99"}}, nil).Times(2)
+	mock.EXPECT().GetTrades("ETH_USD").Return(exmo.Trades{"ETH_USD": {{TradeID: 1}, {TradeID: 2}}}, nil)
+	srv := NewServer(mock, indicator.NewIndicator(mock))
+
+	rec, body := serverGet(t, srv, "/v1/orderbook/BTC_USD?limit=5")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "101", body["ask_top"])
+	assert.Equal(t, "public, max-age=2", rec.Header().Get("Cache-Control"))
+	etag := rec.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	rec, _ = serverGet(t, srv, "/v1/orderbook/BTC_USD?limit=5", "If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+	assert.Empty(t, rec.Body.String())
+
+	for _, limit := range []string{"0", "1001", "x"} {
+		rec, body = serverGet(t, srv, "/v1/orderbook/BTC_USD?limit="+limit)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, limit)
+		assert.Contains(t, body["error"].(map[string]interface{})["message"], "limit must be an integer")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/trades/eth_usd", nil)
+	rec = httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var trades []exmo.Pair
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &trades))
+	assert.Len(t, trades, 2)
+}
+
+func TestServer_Indicator(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	mock.EXPECT().GetClosePrice("BTC_USD", 60, now.Add(-24*time.Hour), now).Return([]float64{1, 2, 3, 4}, nil)
+
+	ind := indicator.NewIndicator(mock, indicator.WithCalculateEMA(func([]float64, int) []float64 { return []float64{math.NaN(), 1} }))
+	srv := NewServer(mock, ind, WithServerClock(func() time.Time { return now }))
+
+	rec, body := serverGet(t, srv, "/v1/indicators/SMA/btc_usd?resolution=1h&period=2&from=-1d")
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "sma", body["indicator"])
+	assert.Equal(t, float64(60), body["resolution"])
+	assert.Equal(t, "2024-02-29T12:00:00Z", body["from"])
+	assert.Equal(t, []interface{}{1.5, 2.5, 3.5}, body["values"])
+	assert.Equal(t, "public, max-age=60", rec.Header().Get("Cache-Control"))
+
+	mock.EXPECT().GetClosePrice("BTC_USD", 30, gomock.Any(), gomock.Any()).Return([]float64{1}, nil)
+	_, body = serverGet(t, srv, "/v1/indicators/ema/BTC_USD")
+	assert.Equal(t, []interface{}{nil, float64(1)}, body["values"])
+
+	tests := []struct {
+		target string
+		status int
+		code   string
+	}{
+		{"/v1/indicators/macd/BTC_USD", http.StatusNotFound, "unknown_indicator"},
+		{"/v1/indicators/sma/BTC_USD?resolution=0", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/BTC_USD?period=0", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/BTC_USD?from=now&to=-1h", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/BTC_USD?from=yesterday", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/BTC_USD?resolution=1&from=-30d", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/..%2F..%2Ftmp%2Fevil_x", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/indicators/sma/BTC.USD_X", http.StatusBadRequest, "invalid_argument"},
+		{"/v1/nothing", http.StatusNotFound, "not_found"},
+	}
+	for _, tt := range tests {
+		rec, body := serverGet(t, srv, tt.target)
+		assert.Equal(t, tt.status, rec.Code, tt.target)
+		assert.Equal(t, tt.code, errorCode(body), tt.target)
+		assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	}
+}
+
+func TestServer_UpstreamErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	srv := NewServer(mock, indicator.NewIndicator(mock))
+
+	mock.EXPECT().GetTicker().Return(nil, &exmo.APIError{Endpoint: "/ticker", StatusCode: 429, Message: "Too many requests"})
+	rec, body := serverGet(t, srv, "/v1/ticker")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "rate_limited", errorCode(body))
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	mock.EXPECT().GetTrades("BTC_USD").Return(nil, errors.New("connection reset"))
+	rec, body = serverGet(t, srv, "/v1/trades/BTC_USD")
+	assert.Equal(t, http.StatusBadGateway, rec.Code)
+	assert.Equal(t, map[string]interface{}{"status": float64(502), "code": "upstream_error", "message": "connection reset"}, body["error"])
+}
+
+func TestServer_GracefulShutdown(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mock := exmomock.NewMockExchanger(ctrl)
+	started := make(chan struct{})
+	mock.EXPECT().GetTicker().DoAndReturn(func() (exmo.Ticker, error) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		return exmo.Ticker{"BTC_USD": {LastTrade: "1"}}, nil
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- NewServer(mock, indicator.NewIndicator(mock)).Serve(ctx, ln, 5*time.Second) }()
+
+	respc := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String() + "/v1/ticker")
+		assert.NoError(t, err)
+		respc <- resp
+	}()
+	<-started
+	cancel()
+
+	resp := <-respc
+	require.NotNil(t, resp)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.NoError(t, <-done)
+
+	_, err = http.Get("http://" + ln.Addr().String() + "/healthz")
+	assert.Error(t, err)
+}
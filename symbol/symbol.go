@@ -0,0 +1,137 @@
+// Package symbol переводит названия торговых пар между форматами бирж.
+//
+// Каноническая форма пары - BASE_QUOTE в верхнем регистре, как у Exmo:
+// BTC_USD. Ее используют модели пакета exmo и все адаптеры бирж, поэтому
+// стратегии не зависят от того, как пару называет конкретная биржа.
+package symbol
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Pair - торговая пара.
+type Pair struct {
+	Base  string
+	Quote string
+}
+
+// String возвращает каноническую форму BASE_QUOTE.
+func (p Pair) String() string {
+	return p.Base + "_" + p.Quote
+}
+
+// Format - способ записи пары на бирже.
+type Format int
+
+const (
+	Underscore Format = iota // BTC_USD, Exmo
+	Concat                   // BTCUSDT, Binance
+	Dash                     // BTC-USD, Coinbase
+)
+
+// Format записывает пару в формате f.
+func (p Pair) Format(f Format) string {
+	switch f {
+	case Concat:
+		return p.Base + p.Quote
+	case Dash:
+		return p.Base + "-" + p.Quote
+	}
+	return p.String()
+}
+
+// Parse разбирает пару с разделителем: BTC_USD, BTC-USD или BTC/USD.
+// Регистр не важен.
+func Parse(s string) (Pair, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	i := strings.IndexAny(s, "_-/")
+	if i <= 0 || i == len(s)-1 || strings.ContainsAny(s[i+1:], "_-/") {
+		return Pair{}, fmt.Errorf("invalid pair %q", s)
+	}
+	return Pair{Base: s[:i], Quote: s[i+1:]}, nil
+}
+
+// ParseConcat разбирает пару без разделителя, например BTCUSDT. Котируемая
+// валюта ищется среди quotes, при нескольких совпадениях берется самая
+// длинная: для BTCUSDT при quotes USD и USDT получится BTC_USDT.
+func ParseConcat(s string, quotes ...string) (Pair, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	best := ""
+	for _, q := range quotes {
+		q = strings.ToUpper(q)
+		if len(q) > len(best) && len(q) < len(s) && strings.HasSuffix(s, q) {
+			best = q
+		}
+	}
+	if best == "" {
+		return Pair{}, fmt.Errorf("invalid pair %q: no known quote currency", s)
+	}
+	return Pair{Base: s[:len(s)-len(best)], Quote: best}, nil
+}
+
+// Mapper переводит канонические пары в символы биржи и обратно. Псевдонимы
+// задают валюты, которые биржа называет иначе: для Binance каноническому
+// USD соответствует USDT.
+//
+// Перевод обратим только для канонических названий. Валюта биржи, занятая
+// псевдонимом, в канонической паре не принимается: при USD=USDT пара
+// BTC_USDT перешла бы в BTCUSDT, а та читается обратно как BTC_USD.
+type Mapper struct {
+	format  Format
+	aliases map[string]string // каноническая валюта -> валюта биржи
+	reverse map[string]string
+}
+
+// NewMapper создает преобразователь для формата биржи. aliases задает
+// соответствие канонических валют валютам биржи.
+func NewMapper(format Format, aliases map[string]string) *Mapper {
+	m := &Mapper{
+		format:  format,
+		aliases: make(map[string]string, len(aliases)),
+		reverse: make(map[string]string, len(aliases)),
+	}
+	for canonical, venue := range aliases {
+		canonical, venue = strings.ToUpper(canonical), strings.ToUpper(venue)
+		m.aliases[canonical] = venue
+		m.reverse[venue] = canonical
+	}
+	return m
+}
+
+// Venue возвращает символ биржи для канонической пары.
+func (m *Mapper) Venue(pair string) (string, error) {
+	p, err := Parse(pair)
+	if err != nil {
+		return "", err
+	}
+	for _, asset := range []string{p.Base, p.Quote} {
+		if c, ok := m.reverse[asset]; ok && c != asset {
+			return "", fmt.Errorf("pair %s: %s is the venue name of %s, use %s", p, asset, c, c)
+		}
+	}
+	return Pair{Base: m.VenueAsset(p.Base), Quote: m.VenueAsset(p.Quote)}.Format(m.format), nil
+}
+
+// Canonical возвращает каноническую пару для валют биржи.
+func (m *Mapper) Canonical(base, quote string) string {
+	return Pair{Base: m.CanonicalAsset(base), Quote: m.CanonicalAsset(quote)}.String()
+}
+
+// VenueAsset возвращает название валюты на бирже.
+func (m *Mapper) VenueAsset(asset string) string {
+	asset = strings.ToUpper(asset)
+	if v, ok := m.aliases[asset]; ok {
+		return v
+	}
+	return asset
+}
+
+// CanonicalAsset возвращает каноническое название валюты биржи.
+func (m *Mapper) CanonicalAsset(asset string) string {
+	asset = strings.ToUpper(asset)
+	if c, ok := m.reverse[asset]; ok {
+		return c
+	}
+	return asset
+}
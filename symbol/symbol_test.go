@@ -0,0 +1,58 @@
+package symbol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	for _, s := range []string{"BTC_USD", "btc-usd", " BTC/USD "} {
+		p, err := Parse(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, Pair{Base: "BTC", Quote: "USD"}, p)
+	}
+	for _, s := range []string{"", "BTCUSD", "_USD", "BTC_", "BTC_USD_EUR"} {
+		_, err := Parse(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestPair_Format(t *testing.T) {
+	p := Pair{Base: "BTC", Quote: "USD"}
+	assert.Equal(t, "BTC_USD", p.Format(Underscore))
+	assert.Equal(t, "BTCUSD", p.Format(Concat))
+	assert.Equal(t, "BTC-USD", p.Format(Dash))
+}
+
+func TestParseConcat(t *testing.T) {
+	p, err := ParseConcat("btcusdt", "USD", "USDT", "BTC")
+	require.NoError(t, err)
+	assert.Equal(t, Pair{Base: "BTC", Quote: "USDT"}, p)
+
+	_, err = ParseConcat("USDT", "USDT")
+	assert.Error(t, err, "base must not be empty")
+	_, err = ParseConcat("BTCEUR", "USD")
+	assert.Error(t, err)
+}
+
+func TestMapper(t *testing.T) {
+	m := NewMapper(Concat, map[string]string{"usd": "usdt"})
+
+	venue, err := m.Venue("btc_usd")
+	require.NoError(t, err)
+	assert.Equal(t, "BTCUSDT", venue)
+	venue, err = m.Venue("ETH_BTC")
+	require.NoError(t, err)
+	assert.Equal(t, "ETHBTC", venue)
+	_, err = m.Venue("BTCUSD")
+	assert.Error(t, err)
+	// BTCUSDT читается обратно как BTC_USD, поэтому BTC_USDT не принимается
+	_, err = m.Venue("BTC_USDT")
+	assert.EqualError(t, err, "pair BTC_USDT: USDT is the venue name of USD, use USD")
+
+	assert.Equal(t, "BTC_USD", m.Canonical("BTC", "USDT"))
+	assert.Equal(t, "USD", m.CanonicalAsset("usdt"))
+	assert.Equal(t, "EUR", m.VenueAsset("eur"))
+}
@@ -0,0 +1,5 @@
+// Package telemetry - метрики в формате Prometheus и трассировка с экспортом
+// по OTLP/HTTP без внешних зависимостей. Клиент exmo и индикаторы пишут в
+// них через опции exmo.WithMetrics, indicator.WithIndicatorMetrics и
+// indicator.WithIndicatorTracer.
+package telemetry
@@ -0,0 +1,244 @@
+package telemetry
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics собирает счетчики клиента Exmo и индикаторов и отдает их в
+// текстовом формате Prometheus. Нулевой указатель допустим: методы
+// записи на nil ничего не делают, поэтому метрики можно не подключать.
+type Metrics struct {
+	mu       sync.Mutex
+	families []*metricFamily
+
+	requests       *metricFamily
+	duration       *metricFamily
+	decodeErrors   *metricFamily
+	retries        *metricFamily
+	limiterWaits   *metricFamily
+	limiterSeconds *metricFamily
+	indicatorTime  *metricFamily
+}
+
+var (
+	requestBuckets   = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	indicatorBuckets = []float64{.00001, .00005, .0001, .0005, .001, .005, .01, .05, .1}
+)
+
+// NewMetrics регистрирует семейства метрик клиента Exmo и индикаторов.
+func NewMetrics() *Metrics {
+	m := &Metrics{}
+	m.requests = m.family("exmo_requests_total", "Запросы к API Exmo по эндпоинту и классу ответа.", "counter", nil, "endpoint", "code")
+	m.duration = m.family("exmo_request_duration_seconds", "Время запроса к API Exmo, включая чтение тела.", "histogram", requestBuckets, "endpoint")
+	m.decodeErrors = m.family("exmo_decode_errors_total", "Ответы Exmo, которые не удалось разобрать.", "counter", nil, "endpoint")
+	m.retries = m.family("exmo_retries_total", "Повторы запросов после временных ошибок.", "counter", nil, "endpoint")
+	m.limiterWaits = m.family("exmo_rate_limit_waits_total", "Запросы, ожидавшие ограничителя частоты.", "counter", nil)
+	m.limiterSeconds = m.family("exmo_rate_limit_wait_seconds_total", "Суммарное ожидание ограничителя частоты.", "counter", nil)
+	m.indicatorTime = m.family("indicator_compute_duration_seconds", "Время расчета индикатора без загрузки данных.", "histogram", indicatorBuckets, "indicator")
+	return m
+}
+
+func (m *Metrics) family(name, help, kind string, buckets []float64, labels ...string) *metricFamily {
+	f := &metricFamily{
+		name:    name,
+		help:    help,
+		kind:    kind,
+		labels:  labels,
+		buckets: buckets,
+		series:  make(map[string]*metricSeries),
+	}
+	m.families = append(m.families, f)
+	return f
+}
+
+// ObserveRequest учитывает завершенный HTTP-запрос. status 0 - сетевая ошибка.
+func (m *Metrics) ObserveRequest(endpoint string, status int, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests.add(1, endpoint, statusClass(status))
+	m.duration.observe(d.Seconds(), endpoint)
+}
+
+// DecodeFailed учитывает ответ, который не удалось разобрать.
+func (m *Metrics) DecodeFailed(endpoint string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.decodeErrors.add(1, endpoint)
+}
+
+// Retried учитывает повтор запроса.
+func (m *Metrics) Retried(endpoint string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries.add(1, endpoint)
+}
+
+// LimiterWaited учитывает ожидание ограничителя частоты.
+func (m *Metrics) LimiterWaited(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limiterWaits.add(1)
+	m.limiterSeconds.add(d.Seconds())
+}
+
+// ObserveIndicator учитывает время расчета индикатора.
+func (m *Metrics) ObserveIndicator(name string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.indicatorTime.observe(d.Seconds(), name)
+}
+
+func statusClass(status int) string {
+	if status <= 0 {
+		return "error"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// WritePrometheus пишет все метрики в текстовом формате Prometheus 0.0.4.
+// Серии отсортированы по меткам, вывод стабилен.
+func (m *Metrics) WritePrometheus(w io.Writer) error {
+	var buf bytes.Buffer
+	m.mu.Lock()
+	for _, f := range m.families {
+		f.write(&buf)
+	}
+	m.mu.Unlock()
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// ServeHTTP отдает метрики, Metrics можно повесить на /metrics.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WritePrometheus(w)
+}
+
+// ServeMetrics слушает addr и отдает метрики на /metrics в фоне. Ошибка
+// открытия порта возвращается сразу; Addr сервера - фактический адрес.
+func ServeMetrics(addr string, m *Metrics) (*http.Server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", m)
+	srv := &http.Server{
+		Addr:              ln.Addr().String(),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go srv.Serve(ln)
+	return srv, nil
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*metricSeries
+}
+
+type metricSeries struct {
+	values []string
+	value  float64  // счетчик
+	counts []uint64 // гистограмма: попадания в каждую корзину, без накопления
+	sum    float64
+	count  uint64
+}
+
+func (f *metricFamily) get(values []string) *metricSeries {
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &metricSeries{values: values}
+		if f.buckets != nil {
+			s.counts = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *metricFamily) add(v float64, values ...string) {
+	f.get(values).value += v
+}
+
+func (f *metricFamily) observe(v float64, values ...string) {
+	s := f.get(values)
+	s.sum += v
+	s.count++
+	if i := sort.SearchFloat64s(f.buckets, v); i < len(f.buckets) {
+		s.counts[i]++
+	}
+}
+
+func (f *metricFamily) write(w io.Writer) {
+	fmt.Fprintf(w, "# HELP %s %s\n", f.name, f.help)
+	fmt.Fprintf(w, "# TYPE %s %s\n", f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for k := range f.series {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		s := f.series[k]
+		if f.kind != "histogram" {
+			fmt.Fprintf(w, "%s%s %s\n", f.name, labelPairs(f.labels, s.values), formatMetric(s.value))
+			continue
+		}
+		labels := append(f.labels[:len(f.labels):len(f.labels)], "le")
+		var cumulative uint64
+		for i, upper := range f.buckets {
+			cumulative += s.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, labelPairs(labels, append(s.values[:len(s.values):len(s.values)], formatMetric(upper))), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", f.name, labelPairs(labels, append(s.values[:len(s.values):len(s.values)], "+Inf")), s.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", f.name, labelPairs(f.labels, s.values), formatMetric(s.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", f.name, labelPairs(f.labels, s.values), s.count)
+	}
+}
+
+func labelPairs(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for i, name := range names {
+		pairs[i] = name + `="` + labelEscaper.Replace(values[i]) + `"`
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatMetric(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
@@ -0,0 +1,56 @@
+package telemetry
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+	var b strings.Builder
+	require.NoError(t, m.WritePrometheus(&b))
+	return b.String()
+}
+
+func TestMetrics_Histogram(t *testing.T) {
+	m := NewMetrics()
+	m.ObserveIndicator(`we"ird`, 50*time.Microsecond)
+	m.ObserveIndicator(`we"ird`, time.Second)
+
+	out := scrape(t, m)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_bucket{indicator="we\"ird",le="1e-05"} 0`)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_bucket{indicator="we\"ird",le="5e-05"} 1`)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_bucket{indicator="we\"ird",le="0.1"} 1`)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_bucket{indicator="we\"ird",le="+Inf"} 2`)
+	assert.Contains(t, out, `indicator_compute_duration_seconds_sum{indicator="we\"ird"} 1.00005`)
+
+	// Запись в nil-метрики ничего не делает
+	var none *Metrics
+	none.ObserveRequest("/ticker", 200, time.Second)
+	none.ObserveIndicator("sma", time.Second)
+}
+
+func TestServeMetrics(t *testing.T) {
+	m := NewMetrics()
+	m.Retried("/trades")
+	srv, err := ServeMetrics("127.0.0.1:0", m)
+	require.NoError(t, err)
+	defer srv.Close()
+
+	resp, err := http.Get("http://" + srv.Addr + "/metrics")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "version=0.0.4")
+	assert.Contains(t, string(body), `exmo_retries_total{endpoint="/trades"} 1`)
+
+	_, err = ServeMetrics(srv.Addr, m)
+	assert.Error(t, err)
+}
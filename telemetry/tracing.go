@@ -0,0 +1,506 @@
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SpanKind - вид спана в терминах OpenTelemetry.
+type SpanKind int
+
+const (
+	SpanInternal SpanKind = 1
+	SpanClient   SpanKind = 3
+)
+
+// Attribute - атрибут спана. Value - string, int64, float64 или bool.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+func StringAttr(key, value string) Attribute {
+	return Attribute{Key: key, Value: value}
+}
+
+func IntAttr(key string, value int) Attribute {
+	return Attribute{Key: key, Value: int64(value)}
+}
+
+// Span - завершенный спан, который получает экспортер.
+type Span struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string // пусто у корневого спана
+	Name       string
+	Kind       SpanKind
+	Start      time.Time
+	End        time.Time
+	Attributes []Attribute
+	Err        string
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Attr возвращает значение атрибута или nil.
+func (s Span) Attr(key string) interface{} {
+	for _, a := range s.Attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+// SpanExporter отправляет завершенные спаны во внешнюю систему.
+type SpanExporter interface {
+	ExportSpans(ctx context.Context, spans []Span) error
+	Shutdown(ctx context.Context) error
+}
+
+// Tracer создает спаны и передает их экспортеру по завершении. Как и с
+// Metrics, нулевой указатель допустим: спаны тогда не создаются.
+type Tracer struct {
+	exporter SpanExporter
+	now      func() time.Time
+	onError  func(error)
+}
+
+// TracerOption настраивает Tracer.
+type TracerOption func(*Tracer)
+
+// WithTracerClock подменяет часы для времени начала и конца спанов.
+func WithTracerClock(now func() time.Time) TracerOption {
+	return func(t *Tracer) {
+		t.now = now
+	}
+}
+
+// WithTracerErrorHandler получает ошибки экспорта, по умолчанию они отбрасываются.
+func WithTracerErrorHandler(f func(error)) TracerOption {
+	return func(t *Tracer) {
+		t.onError = f
+	}
+}
+
+// NewTracer создает трассировщик, который отдает завершенные спаны exporter.
+func NewTracer(exporter SpanExporter, opts ...TracerOption) *Tracer {
+	t := &Tracer{
+		exporter: exporter,
+		now:      time.Now,
+		onError:  func(error) {},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Start открывает спан, дочерний к спану из ctx, и возвращает контекст с ним.
+func (t *Tracer) Start(ctx context.Context, name string, kind SpanKind, attrs ...Attribute) (context.Context, *ActiveSpan) {
+	if t == nil {
+		return ctx, nil
+	}
+	s := &ActiveSpan{tracer: t}
+	s.span = Span{
+		SpanID:     newTraceID(8),
+		Name:       name,
+		Kind:       kind,
+		Start:      t.now(),
+		Attributes: attrs,
+	}
+	if parent := SpanFromContext(ctx); parent != nil {
+		s.span.TraceID, s.span.ParentID = parent.span.TraceID, parent.span.SpanID
+	} else {
+		s.span.TraceID = newTraceID(16)
+	}
+	return ContextWithSpan(ctx, s), s
+}
+
+// Shutdown отправляет накопленные экспортером спаны.
+func (t *Tracer) Shutdown(ctx context.Context) error {
+	if t == nil {
+		return nil
+	}
+	return t.exporter.Shutdown(ctx)
+}
+
+func newTraceID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ActiveSpan - открытый спан. Методы на nil ничего не делают.
+type ActiveSpan struct {
+	tracer *Tracer
+	mu     sync.Mutex
+	span   Span
+	ended  bool
+}
+
+func (s *ActiveSpan) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.span.Attributes = append(s.span.Attributes, attrs...)
+}
+
+// End закрывает спан с ошибкой err (nil - успех) и экспортирует его.
+// Повторные вызовы игнорируются.
+func (s *ActiveSpan) End(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if s.ended {
+		s.mu.Unlock()
+		return
+	}
+	s.ended = true
+	s.span.End = s.tracer.now()
+	if err != nil {
+		s.span.Err = err.Error()
+	}
+	span := s.span
+	s.mu.Unlock()
+
+	if err := s.tracer.exporter.ExportSpans(context.Background(), []Span{span}); err != nil {
+		s.tracer.onError(err)
+	}
+}
+
+type spanContextKey struct{}
+
+func ContextWithSpan(ctx context.Context, s *ActiveSpan) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, s)
+}
+
+func SpanFromContext(ctx context.Context) *ActiveSpan {
+	s, _ := ctx.Value(spanContextKey{}).(*ActiveSpan)
+	return s
+}
+
+// InMemoryExporter хранит спаны в памяти, удобен в тестах.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+// NewInMemoryExporter создает экспортер, хранящий спаны в памяти.
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpans(_ context.Context, spans []Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *InMemoryExporter) Shutdown(context.Context) error {
+	return nil
+}
+
+// Spans возвращает копию спанов в порядке завершения.
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]Span(nil), e.spans...)
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.spans = nil
+}
+
+// DefaultOTLPEndpoint - адрес OTLP/HTTP локального коллектора.
+const DefaultOTLPEndpoint = "http://localhost:4318/v1/traces"
+
+// OTLPExporter отправляет спаны пачками в коллектор по OTLP/HTTP в
+// кодировке JSON. Отправка идет в фоновой горутине: полная пачка уходит
+// сразу, неполная - раз в интервал и при Shutdown, поэтому медленный
+// коллектор не задерживает завершение спанов. Ошибка фоновой отправки
+// возвращается следующим вызовом ExportSpans или Shutdown.
+type OTLPExporter struct {
+	endpoint string
+	service  string
+	batch    int
+	interval time.Duration
+	client   *http.Client
+
+	mu      sync.Mutex
+	pending []Span
+	err     error
+	started bool
+	closed  bool
+	flush   chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// otlpMaxBatches - сколько пачек копится, пока коллектор недоступен;
+// спаны сверх этого отбрасываются.
+const otlpMaxBatches = 32
+
+// OTLPOption настраивает OTLPExporter.
+type OTLPOption func(*OTLPExporter)
+
+// WithOTLPServiceName задает service.name ресурса.
+func WithOTLPServiceName(name string) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.service = name
+	}
+}
+
+// WithOTLPBatchSize задает размер пачки: полная пачка отправляется сразу,
+// остаток - по интервалу и при Shutdown.
+func WithOTLPBatchSize(n int) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.batch = n
+	}
+}
+
+// WithOTLPFlushInterval задает, как часто отправлять неполную пачку,
+// по умолчанию 5 секунд. 0 - только полными пачками и при Shutdown.
+func WithOTLPFlushInterval(d time.Duration) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.interval = d
+	}
+}
+
+// WithOTLPHTTPClient подменяет HTTP-клиент экспортера.
+func WithOTLPHTTPClient(c *http.Client) OTLPOption {
+	return func(e *OTLPExporter) {
+		e.client = c
+	}
+}
+
+// NewOTLPExporter создает экспортер. Пустой endpoint - DefaultOTLPEndpoint.
+func NewOTLPExporter(endpoint string, opts ...OTLPOption) *OTLPExporter {
+	if endpoint == "" {
+		endpoint = DefaultOTLPEndpoint
+	}
+	e := &OTLPExporter{
+		endpoint: endpoint,
+		service:  "exmo",
+		batch:    64,
+		interval: 5 * time.Second,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		flush:    make(chan struct{}, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// ExportSpans ставит спаны в очередь и не ждет отправки. Фоновая горутина
+// запускается при первом вызове.
+func (e *OTLPExporter) ExportSpans(_ context.Context, spans []Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if room := otlpMaxBatches*e.batch - len(e.pending); len(spans) > room {
+		e.err = fmt.Errorf("otlp export: queue full, dropped %d spans", len(spans)-max(room, 0))
+		spans = spans[:max(room, 0)]
+	}
+	e.pending = append(e.pending, spans...)
+	if !e.started && !e.closed {
+		e.started = true
+		go e.loop()
+	}
+	if len(e.pending) >= e.batch {
+		select {
+		case e.flush <- struct{}{}:
+		default:
+		}
+	}
+	err := e.err
+	e.err = nil
+	return err
+}
+
+// Shutdown останавливает фоновую отправку и отправляет остаток очереди.
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	running := e.started && !e.closed
+	e.closed = true
+	e.mu.Unlock()
+	if running {
+		close(e.stop)
+		select {
+		case <-e.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	err := e.sendPending(ctx, true)
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	err, e.err = errors.Join(e.err, err), nil
+	return err
+}
+
+func (e *OTLPExporter) loop() {
+	defer close(e.done)
+	var tick <-chan time.Time
+	var all bool
+	if e.interval > 0 {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-e.stop:
+			return
+		case <-e.flush:
+			all = false
+		case <-tick:
+			all = true
+		}
+		if err := e.sendPending(context.Background(), all); err != nil {
+			e.mu.Lock()
+			e.err = err
+			e.mu.Unlock()
+		}
+	}
+}
+
+// sendPending отправляет очередь пачками по batch спанов; неполная пачка
+// уходит, только если all.
+func (e *OTLPExporter) sendPending(ctx context.Context, all bool) error {
+	for {
+		e.mu.Lock()
+		n := min(len(e.pending), e.batch)
+		if n < e.batch && !all {
+			n = 0
+		}
+		batch := e.pending[:n:n]
+		e.pending = e.pending[n:]
+		e.mu.Unlock()
+		if n == 0 {
+			return nil
+		}
+		if err := e.send(ctx, batch); err != nil {
+			return err
+		}
+	}
+}
+
+func (e *OTLPExporter) send(ctx context.Context, spans []Span) error {
+	body, err := json.Marshal(otlpRequest(e.service, spans))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otlp export: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("otlp export: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Структуры OTLP JSON: ExportTraceServiceRequest с одним ресурсом.
+type otlpKeyValue struct {
+	Key   string                 `json:"key"`
+	Value map[string]interface{} `json:"value"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            *otlpStatus    `json:"status,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code"` // 2 - ошибка
+	Message string `json:"message,omitempty"`
+}
+
+func otlpRequest(service string, spans []Span) map[string]interface{} {
+	out := make([]otlpSpan, len(spans))
+	for i, s := range spans {
+		out[i] = otlpSpan{
+			TraceID:           s.TraceID,
+			SpanID:            s.SpanID,
+			ParentSpanID:      s.ParentID,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(s.Attributes),
+		}
+		if s.Err != "" {
+			out[i].Status = &otlpStatus{Code: 2, Message: s.Err}
+		}
+	}
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": otlpAttributes([]Attribute{StringAttr("service.name", service)}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]string{"name": "exmo"},
+				"spans": out,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attrs []Attribute) []otlpKeyValue {
+	out := make([]otlpKeyValue, 0, len(attrs))
+	for _, a := range attrs {
+		var v map[string]interface{}
+		switch x := a.Value.(type) {
+		case string:
+			v = map[string]interface{}{"stringValue": x}
+		case int64:
+			// int64 в OTLP JSON передается строкой
+			v = map[string]interface{}{"intValue": strconv.FormatInt(x, 10)}
+		case float64:
+			v = map[string]interface{}{"doubleValue": x}
+		case bool:
+			v = map[string]interface{}{"boolValue": x}
+		default:
+			v = map[string]interface{}{"stringValue": fmt.Sprint(x)}
+		}
+		out = append(out, otlpKeyValue{Key: a.Key, Value: v})
+	}
+	return out
+}
@@ -0,0 +1,147 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type collector struct {
+	mu       sync.Mutex
+	requests []map[string]interface{}
+	status   int
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	var req map[string]interface{}
+	json.Unmarshal(body, &req)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, req)
+	if c.status != 0 {
+		w.WriteHeader(c.status)
+	}
+}
+
+// exported возвращает спаны из всех запросов к коллектору.
+func (c *collector) exported() []map[string]interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var spans []map[string]interface{}
+	for _, req := range c.requests {
+		for _, rs := range req["resourceSpans"].([]interface{}) {
+			for _, ss := range rs.(map[string]interface{})["scopeSpans"].([]interface{}) {
+				for _, s := range ss.(map[string]interface{})["spans"].([]interface{}) {
+					spans = append(spans, s.(map[string]interface{}))
+				}
+			}
+		}
+	}
+	return spans
+}
+
+func TestOTLPExporter(t *testing.T) {
+	c := &collector{}
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+
+	start := time.Unix(100, 0)
+	exporter := NewOTLPExporter(ts.URL, WithOTLPBatchSize(2), WithOTLPServiceName("svc"))
+	tracer := NewTracer(exporter, WithTracerClock(func() time.Time { return start }))
+
+	ctx, parent := tracer.Start(context.Background(), "parent", SpanInternal)
+	_, child := tracer.Start(ctx, "child", SpanClient, IntAttr("n", 7), StringAttr("s", "v"))
+	child.End(errors.New("failed"))
+	parent.End(nil)
+	_, last := tracer.Start(context.Background(), "last", SpanInternal)
+	last.End(nil)
+
+	require.Eventually(t, func() bool { return len(c.exported()) == 2 }, time.Second, time.Millisecond,
+		"полная пачка уходит сразу")
+	require.NoError(t, tracer.Shutdown(context.Background()))
+	spans := c.exported()
+	require.Len(t, spans, 3)
+
+	assert.Equal(t, "child", spans[0]["name"])
+	assert.Equal(t, spans[1]["spanId"], spans[0]["parentSpanId"])
+	assert.Equal(t, "100000000000", spans[0]["startTimeUnixNano"])
+	assert.Equal(t, float64(3), spans[0]["kind"])
+	assert.Equal(t, map[string]interface{}{"code": float64(2), "message": "failed"}, spans[0]["status"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "n", "value": map[string]interface{}{"intValue": "7"}},
+		map[string]interface{}{"key": "s", "value": map[string]interface{}{"stringValue": "v"}},
+	}, spans[0]["attributes"])
+	assert.Nil(t, spans[1]["status"])
+
+	resource := c.requests[0]["resourceSpans"].([]interface{})[0].(map[string]interface{})["resource"]
+	assert.Contains(t, toJSON(t, resource), `"stringValue":"svc"`)
+
+	c.status = http.StatusBadRequest
+	require.NoError(t, exporter.ExportSpans(context.Background(), []Span{{Name: "x"}}))
+	assert.ErrorContains(t, exporter.Shutdown(context.Background()), "status 400")
+}
+
+func TestOTLPExporter_FlushInterval(t *testing.T) {
+	c := &collector{}
+	ts := httptest.NewServer(c)
+	defer ts.Close()
+
+	exporter := NewOTLPExporter(ts.URL, WithOTLPFlushInterval(10*time.Millisecond))
+	require.NoError(t, exporter.ExportSpans(context.Background(), []Span{{Name: "one"}}))
+
+	// Неполная пачка уходит по таймеру, без Shutdown
+	require.Eventually(t, func() bool { return len(c.exported()) == 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, exporter.Shutdown(context.Background()))
+}
+
+func TestOTLPExporter_SlowCollector(t *testing.T) {
+	release := make(chan struct{})
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer ts.Close()
+	defer close(release)
+
+	exporter := NewOTLPExporter(ts.URL, WithOTLPBatchSize(1))
+	tracer := NewTracer(exporter)
+
+	// Завершение спана не ждет ответа коллектора
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			_, span := tracer.Start(context.Background(), "span", SpanInternal)
+			span.End(nil)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("span End blocked on export")
+	}
+}
+
+func toJSON(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestTracer_Nil(t *testing.T) {
+	// Без трассировщика спаны не пишутся и не падают
+	var none *Tracer
+	_, span := none.Start(context.Background(), "x", SpanInternal)
+	span.SetAttributes(IntAttr("a", 1))
+	span.End(nil)
+}